@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -27,8 +28,11 @@ func init() {
 }
 
 func mcpServer(_ *cobra.Command, _ []string) {
-	// Set auto reconnect to whatsapp server after booting
-	go helpers.SetAutoConnectAfterBooting(appUsecase)
+	// Set auto reconnect to whatsapp server after booting, bulk send jobs carry on once it connected
+	go func() {
+		helpers.SetAutoConnectAfterBooting(appUsecase)
+		bulkUsecase.ResumeJobs(context.Background())
+	}()
 	// Set auto reconnect checking
 	go helpers.SetAutoReconnectChecking(whatsappCli)
 
@@ -53,6 +57,9 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	groupHandler := mcp.InitMcpGroup(groupUsecase)
 	groupHandler.AddGroupTools(mcpServer)
 
+	autoReplyHandler := mcp.InitMcpAutoReply(autoReplyUsecase)
+	autoReplyHandler.AddAutoReplyTools(mcpServer)
+
 	// Create SSE server
 	sseServer := server.NewSSEServer(
 		mcpServer,
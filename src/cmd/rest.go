@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -71,7 +73,7 @@ func restServer(_ *cobra.Command, _ []string) {
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, Idempotency-Key",
 	}))
 
 	// Health check endpoint - BEFORE basic auth (tidak perlu autentikasi)
@@ -83,6 +85,11 @@ func restServer(_ *cobra.Command, _ []string) {
 		})
 	})
 
+	// Signed webhook media URLs carry their own authentication, they are only served when webhooks link to them
+	if config.WhatsappWebhookMediaMode != domainWebhook.MediaModePath {
+		rest.InitRestWebhookMedia(app)
+	}
+
 	if len(config.AppBasicAuthCredential) > 0 {
 		account := make(map[string]string)
 		for _, basicAuth := range config.AppBasicAuthCredential {
@@ -108,11 +115,25 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestAccount(apiGroup, accountUsecase)
 	rest.InitRestApp(apiGroup, appUsecase)
 	rest.InitRestChat(apiGroup, chatUsecase)
+	// A repeated Idempotency-Key on a send replays the first response instead of sending again
+	idempotency := middleware.Idempotency(idempotencyRepo, config.WhatsappIdempotencyTTL)
+	apiGroup.Use("/send", idempotency)
+	apiGroup.Use("/message/:message_id/forward", idempotency)
 	rest.InitRestSend(apiGroup, sendUsecase)
+	rest.InitRestBulk(apiGroup, bulkUsecase)
+	rest.InitRestSchedule(apiGroup, scheduleUsecase)
+	rest.InitRestTemplate(apiGroup, templateUsecase)
+	rest.InitRestStatus(apiGroup, statusUsecase)
+	rest.InitRestPoll(apiGroup, pollUsecase)
 	rest.InitRestUser(apiGroup, userUsecase)
-	rest.InitRestMessage(apiGroup, messageUsecase)
+	rest.InitRestMessage(apiGroup, messageUsecase, sendUsecase)
 	rest.InitRestGroup(apiGroup, groupUsecase)
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
+	rest.InitRestWebhook(apiGroup, webhookUsecase)
+	rest.InitRestAutoReply(apiGroup, autoReplyUsecase)
+	rest.InitRestFlow(apiGroup, flowUsecase)
+	rest.InitRestScript(apiGroup, scriptUsecase)
+	rest.InitRestRelay(apiGroup, relayUsecase)
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -125,11 +146,13 @@ func restServer(_ *cobra.Command, _ []string) {
 		})
 	})
 
-	websocket.RegisterRoutes(apiGroup, appUsecase)
-	go websocket.RunHub()
+	websocket.RegisterRoutes(apiGroup, appUsecase, sendUsecase, messageUsecase)
 
-	// Set auto reconnect to whatsapp server after booting
-	go helpers.SetAutoConnectAfterBooting(appUsecase)
+	// Set auto reconnect to whatsapp server after booting, bulk send jobs carry on once it connected
+	go func() {
+		helpers.SetAutoConnectAfterBooting(appUsecase)
+		bulkUsecase.ResumeJobs(context.Background())
+	}()
 	// Set auto reconnect checking
 	go helpers.SetAutoReconnectChecking(whatsappCli)
 
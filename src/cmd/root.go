@@ -13,15 +13,38 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	infraAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/autoreply"
+	infraBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/bulk"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/eventsink"
+	infraFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/flow"
+	infraIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/idempotency"
+	infraPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/poll"
+	infraRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/relay"
+	infraSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/schedule"
+	infraScript "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/script"
+	infraStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/status"
+	infraTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/template"
+	infraWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/webhook"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
@@ -45,6 +68,9 @@ var (
 	chatStorageDB   *sql.DB
 	chatStorageRepo domainChatStorage.IChatStorageRepository
 
+	// Idempotency keys of send requests
+	idempotencyRepo domainIdempotency.IIdempotencyRepository
+
 	// Account Management
 	accountRepo    domainAccount.IAccountRepository
 	accountManager domainAccount.IAccountManager
@@ -58,6 +84,16 @@ var (
 	messageUsecase    domainMessage.IMessageUsecase
 	groupUsecase      domainGroup.IGroupUsecase
 	newsletterUsecase domainNewsletter.INewsletterUsecase
+	webhookUsecase    domainWebhook.IWebhookUsecase
+	autoReplyUsecase  domainAutoReply.IAutoReplyUsecase
+	flowUsecase       domainFlow.IFlowUsecase
+	scriptUsecase     domainScript.IScriptUsecase
+	relayUsecase      domainRelay.IRelayUsecase
+	bulkUsecase       domainBulk.IBulkUsecase
+	scheduleUsecase   domainSchedule.IScheduleUsecase
+	templateUsecase   domainTemplate.ITemplateUsecase
+	statusUsecase     domainStatus.IStatusUsecase
+	pollUsecase       domainPoll.IPollUsecase
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -131,9 +167,80 @@ func initEnvConfig() {
 	if envWebhookSecret := viper.GetString("whatsapp_webhook_secret"); envWebhookSecret != "" {
 		config.WhatsappWebhookSecret = envWebhookSecret
 	}
+	if viper.IsSet("whatsapp_webhook_cloudevents") {
+		config.WhatsappWebhookCloudEvents = viper.GetBool("whatsapp_webhook_cloudevents")
+	}
+	if envMediaMode := viper.GetString("whatsapp_webhook_media_mode"); envMediaMode != "" {
+		config.WhatsappWebhookMediaMode = envMediaMode
+	}
+	if viper.IsSet("whatsapp_webhook_media_inline_max_size") {
+		config.WhatsappWebhookMediaInlineMaxSize = viper.GetInt64("whatsapp_webhook_media_inline_max_size")
+	}
+	if viper.IsSet("whatsapp_webhook_media_url_expiry") {
+		config.WhatsappWebhookMediaURLExpiry = viper.GetDuration("whatsapp_webhook_media_url_expiry")
+	}
+	if viper.IsSet("whatsapp_webhook_reply_actions") {
+		config.WhatsappWebhookReplyActions = viper.GetBool("whatsapp_webhook_reply_actions")
+	}
+	if viper.IsSet("whatsapp_webhook_delivery_retention") {
+		config.WhatsappWebhookDeliveryRetention = viper.GetDuration("whatsapp_webhook_delivery_retention")
+	}
+	if envBatchEndpoints := viper.GetString("whatsapp_webhook_batch_endpoints"); envBatchEndpoints != "" {
+		config.WhatsappWebhookBatchEndpoints = strings.Split(envBatchEndpoints, ",")
+	}
+	if viper.IsSet("whatsapp_webhook_batch_size") {
+		config.WhatsappWebhookBatchSize = viper.GetInt("whatsapp_webhook_batch_size")
+	}
+	if viper.IsSet("whatsapp_webhook_batch_max_latency") {
+		config.WhatsappWebhookBatchMaxLatency = viper.GetDuration("whatsapp_webhook_batch_max_latency")
+	}
+	if viper.IsSet("whatsapp_webhook_skip_history") {
+		config.WhatsappWebhookSkipHistory = viper.GetBool("whatsapp_webhook_skip_history")
+	}
+	if envScriptAllowlist := viper.GetString("whatsapp_script_http_allowlist"); envScriptAllowlist != "" {
+		config.WhatsappScriptHTTPAllowlist = strings.Split(envScriptAllowlist, ",")
+	}
+	if viper.IsSet("whatsapp_script_timeout") {
+		config.WhatsappScriptTimeout = viper.GetDuration("whatsapp_script_timeout")
+	}
+	if viper.IsSet("whatsapp_script_max_steps") {
+		config.WhatsappScriptMaxSteps = viper.GetUint64("whatsapp_script_max_steps")
+	}
+	if viper.IsSet("whatsapp_bulk_interval") {
+		config.WhatsappBulkInterval = viper.GetDuration("whatsapp_bulk_interval")
+	}
+	if viper.IsSet("whatsapp_bulk_jitter") {
+		config.WhatsappBulkJitter = viper.GetDuration("whatsapp_bulk_jitter")
+	}
+	if envMissedPolicy := viper.GetString("whatsapp_schedule_missed_policy"); envMissedPolicy != "" {
+		config.WhatsappScheduleMissedPolicy = envMissedPolicy
+	}
+	if viper.IsSet("whatsapp_idempotency_ttl") {
+		config.WhatsappIdempotencyTTL = viper.GetDuration("whatsapp_idempotency_ttl")
+	}
+	if envMediaBaseURL := viper.GetString("whatsapp_webhook_media_base_url"); envMediaBaseURL != "" {
+		config.WhatsappWebhookMediaBaseURL = envMediaBaseURL
+	}
 	if viper.IsSet("whatsapp_account_validation") {
 		config.WhatsappAccountValidation = viper.GetBool("whatsapp_account_validation")
 	}
+
+	// Event sink settings
+	if envNatsURL := viper.GetString("event_sink_nats"); envNatsURL != "" {
+		config.EventSinkNatsURL = envNatsURL
+	}
+	if envNatsPrefix := viper.GetString("event_sink_nats_prefix"); envNatsPrefix != "" {
+		config.EventSinkNatsSubjectPrefix = envNatsPrefix
+	}
+	if envRedisURL := viper.GetString("event_sink_redis"); envRedisURL != "" {
+		config.EventSinkRedisURL = envRedisURL
+	}
+	if envRedisPrefix := viper.GetString("event_sink_redis_prefix"); envRedisPrefix != "" {
+		config.EventSinkRedisStreamPrefix = envRedisPrefix
+	}
+	if viper.IsSet("event_sink_redis_maxlen") {
+		config.EventSinkRedisMaxLen = viper.GetInt64("event_sink_redis_maxlen")
+	}
 }
 
 func initFlags() {
@@ -221,12 +328,173 @@ func initFlags() {
 		config.WhatsappWebhookSecret,
 		`secure webhook request --webhook-secret <string> | example: --webhook-secret="super-secret-key"`,
 	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookCloudEvents,
+		"webhook-cloudevents", "",
+		config.WhatsappWebhookCloudEvents,
+		`wrap webhook and event sink payloads in a CloudEvents 1.0 envelope --webhook-cloudevents <true/false> | example: --webhook-cloudevents=true`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookMediaMode,
+		"webhook-media-mode", "",
+		config.WhatsappWebhookMediaMode,
+		`how downloaded media is delivered in webhooks: path, inline (base64) or url (signed download URL) --webhook-media-mode <string> | example: --webhook-media-mode="url"`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappWebhookMediaInlineMaxSize,
+		"webhook-media-inline-max-size", "",
+		config.WhatsappWebhookMediaInlineMaxSize,
+		`largest file in bytes inlined as base64, larger files get a signed URL --webhook-media-inline-max-size <int> | example: --webhook-media-inline-max-size=1000000`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookMediaURLExpiry,
+		"webhook-media-url-expiry", "",
+		config.WhatsappWebhookMediaURLExpiry,
+		`lifetime of signed media download URLs --webhook-media-url-expiry <duration> | example: --webhook-media-url-expiry=1h`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookMediaBaseURL,
+		"webhook-media-base-url", "",
+		config.WhatsappWebhookMediaBaseURL,
+		`public URL of this gateway used in signed media URLs --webhook-media-base-url <string> | example: --webhook-media-base-url="https://wa.example.com"`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookReplyActions,
+		"webhook-reply-actions", "",
+		config.WhatsappWebhookReplyActions,
+		`execute the reply actions (reply, react, send_media, mark_read) returned in webhook response bodies --webhook-reply-actions <true/false> | example: --webhook-reply-actions=true`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookDeliveryRetention,
+		"webhook-delivery-retention", "",
+		config.WhatsappWebhookDeliveryRetention,
+		`how long webhook delivery records are kept, 0 keeps everything --webhook-delivery-retention <duration> | example: --webhook-delivery-retention=72h`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookBatchEndpoints,
+		"webhook-batch-endpoints", "",
+		config.WhatsappWebhookBatchEndpoints,
+		`webhook URLs that receive events as JSON arrays, "*" for every URL --webhook-batch-endpoints <string> | example: --webhook-batch-endpoints="https://yourcallback.com/bulk"`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookBatchSize,
+		"webhook-batch-size", "",
+		config.WhatsappWebhookBatchSize,
+		`events per webhook batch --webhook-batch-size <int> | example: --webhook-batch-size=100`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookBatchMaxLatency,
+		"webhook-batch-max-latency", "",
+		config.WhatsappWebhookBatchMaxLatency,
+		`longest an event waits before an incomplete batch is sent --webhook-batch-max-latency <duration> | example: --webhook-batch-max-latency=5s`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookSkipHistory,
+		"webhook-skip-history", "",
+		config.WhatsappWebhookSkipHistory,
+		`do not forward messages backfilled by a history sync, history_sync events are still sent --webhook-skip-history <true/false> | example: --webhook-skip-history=true`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappScriptHTTPAllowlist,
+		"script-http-allowlist", "",
+		config.WhatsappScriptHTTPAllowlist,
+		`URL prefixes scripts may call with http.get and http.post --script-http-allowlist <string> | example: --script-http-allowlist="https://api.example.com/"`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappScriptTimeout,
+		"script-timeout", "",
+		config.WhatsappScriptTimeout,
+		`how long a script may run for one event unless it sets timeout_ms --script-timeout <duration> | example: --script-timeout=5s`,
+	)
+	rootCmd.PersistentFlags().Uint64VarP(
+		&config.WhatsappScriptMaxSteps,
+		"script-max-steps", "",
+		config.WhatsappScriptMaxSteps,
+		`Starlark execution steps a script may take for one event --script-max-steps <int> | example: --script-max-steps=5000000`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappBulkInterval,
+		"bulk-interval", "",
+		config.WhatsappBulkInterval,
+		`pause between two messages of a bulk send job unless the job sets interval_ms --bulk-interval <duration> | example: --bulk-interval=5s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappBulkJitter,
+		"bulk-jitter", "",
+		config.WhatsappBulkJitter,
+		`random extra pause of up to this long between two messages of a bulk send job unless the job sets jitter_ms --bulk-jitter <duration> | example: --bulk-jitter=3s`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappScheduleMissedPolicy,
+		"schedule-missed-policy", "",
+		config.WhatsappScheduleMissedPolicy,
+		`what happens to scheduled messages that came due while the gateway was down, fire sends them late and skip drops them --schedule-missed-policy <fire/skip> | example: --schedule-missed-policy=skip`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappIdempotencyTTL,
+		"idempotency-ttl", "",
+		config.WhatsappIdempotencyTTL,
+		`how long a send with an Idempotency-Key header replays its response when the key is repeated --idempotency-ttl <duration> | example: --idempotency-ttl=48h`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAccountValidation,
 		"account-validation", "",
 		config.WhatsappAccountValidation,
 		`enable or disable account validation --account-validation <true/false> | example: --account-validation=true`,
 	)
+
+	// Event sink flags
+	rootCmd.PersistentFlags().StringVarP(
+		&config.EventSinkNatsURL,
+		"event-sink-nats", "",
+		config.EventSinkNatsURL,
+		`publish events to a NATS server --event-sink-nats <string> | example: --event-sink-nats="nats://localhost:4222"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.EventSinkNatsSubjectPrefix,
+		"event-sink-nats-prefix", "",
+		config.EventSinkNatsSubjectPrefix,
+		`NATS subject prefix, events are published on <prefix>.<account>.<event> --event-sink-nats-prefix <string> | example: --event-sink-nats-prefix="whatsapp"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.EventSinkRedisURL,
+		"event-sink-redis", "",
+		config.EventSinkRedisURL,
+		`append events to Redis streams --event-sink-redis <string> | example: --event-sink-redis="redis://localhost:6379/0"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.EventSinkRedisStreamPrefix,
+		"event-sink-redis-prefix", "",
+		config.EventSinkRedisStreamPrefix,
+		`Redis stream key prefix, events are appended to <prefix>:<account>:<event> --event-sink-redis-prefix <string> | example: --event-sink-redis-prefix="whatsapp"`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.EventSinkRedisMaxLen,
+		"event-sink-redis-maxlen", "",
+		config.EventSinkRedisMaxLen,
+		`approximate number of entries kept per Redis stream, 0 keeps everything --event-sink-redis-maxlen <number> | example: --event-sink-redis-maxlen=10000`,
+	)
+}
+
+// initEventSinks registers the configured message broker sinks next to the webhooks
+func initEventSinks() {
+	if config.EventSinkNatsURL != "" {
+		sink, err := eventsink.NewNATSSink(config.EventSinkNatsURL, config.EventSinkNatsSubjectPrefix)
+		if err != nil {
+			logrus.Fatalf("failed to initialize NATS event sink: %v", err)
+		}
+		whatsapp.RegisterEventSink(sink)
+		logrus.Infof("Publishing events to NATS subjects %s.<account>.<event>", config.EventSinkNatsSubjectPrefix)
+	}
+
+	if config.EventSinkRedisURL != "" {
+		sink, err := eventsink.NewRedisSink(config.EventSinkRedisURL, config.EventSinkRedisStreamPrefix, config.EventSinkRedisMaxLen)
+		if err != nil {
+			logrus.Fatalf("failed to initialize Redis event sink: %v", err)
+		}
+		whatsapp.RegisterEventSink(sink)
+		logrus.Infof("Publishing events to Redis streams %s:<account>:<event>", config.EventSinkRedisStreamPrefix)
+	}
 }
 
 func initChatStorage() (*sql.DB, error) {
@@ -276,6 +544,58 @@ func initApp() {
 	chatStorageRepo = chatstorage.NewStorageRepository(chatStorageDB)
 	chatStorageRepo.InitializeSchema()
 
+	webhookRuleRepo := infraWebhook.NewRuleRepository(chatStorageDB)
+	if err := webhookRuleRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize webhook rules: %v", err)
+	}
+	webhookDeliveryRepo := infraWebhook.NewDeliveryRepository(chatStorageDB)
+	if err := webhookDeliveryRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize webhook delivery log: %v", err)
+	}
+	whatsapp.SetWebhookDeliveryRepository(webhookDeliveryRepo)
+
+	autoReplyRepo := infraAutoReply.NewRepository(chatStorageDB)
+	if err := autoReplyRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize auto-reply rules: %v", err)
+	}
+	flowRepo := infraFlow.NewRepository(chatStorageDB)
+	if err := flowRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize flows: %v", err)
+	}
+	scriptRepo := infraScript.NewRepository(chatStorageDB)
+	if err := scriptRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize scripts: %v", err)
+	}
+	relayRepo := infraRelay.NewRepository(chatStorageDB)
+	if err := relayRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize relay rules: %v", err)
+	}
+	bulkRepo := infraBulk.NewRepository(chatStorageDB)
+	if err := bulkRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize bulk send jobs: %v", err)
+	}
+	scheduleRepo := infraSchedule.NewRepository(chatStorageDB)
+	if err := scheduleRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize scheduled messages: %v", err)
+	}
+	templateRepo := infraTemplate.NewRepository(chatStorageDB)
+	if err := templateRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize message templates: %v", err)
+	}
+	statusRepo := infraStatus.NewRepository(chatStorageDB)
+	if err := statusRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize statuses: %v", err)
+	}
+	pollRepo := infraPoll.NewRepository(chatStorageDB)
+	if err := pollRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize polls: %v", err)
+	}
+	whatsapp.SetPollRepository(pollRepo)
+	idempotencyRepo = infraIdempotency.NewRepository(chatStorageDB)
+	if err := idempotencyRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize idempotency keys: %v", err)
+	}
+
 	// Initialize account management
 	accountDBPath := fmt.Sprintf("%s/accounts.db", config.PathStorages)
 	var err2 error
@@ -291,17 +611,64 @@ func initApp() {
 		keysDB = whatsapp.InitWaDB(ctx, config.DBKeysURI)
 	}
 
+	switch config.WhatsappWebhookMediaMode {
+	case domainWebhook.MediaModePath, domainWebhook.MediaModeInline, domainWebhook.MediaModeURL:
+	default:
+		logrus.Fatalf("invalid webhook media mode %q, use path, inline or url", config.WhatsappWebhookMediaMode)
+	}
+	if config.WhatsappWebhookMediaMode != domainWebhook.MediaModePath {
+		// Signed media URLs are served without basic auth, anyone could sign them with the well known default secret
+		if config.WhatsappWebhookSecret == config.DefaultWebhookSecret {
+			logrus.Fatalf("--webhook-media-mode=%s signs media URLs with the webhook secret, set --webhook-secret to a value other than the default", config.WhatsappWebhookMediaMode)
+		}
+		if config.WhatsappWebhookMediaBaseURL == "" {
+			logrus.Warn("--webhook-media-base-url is not set, signed media URLs are relative to this gateway")
+		}
+	}
+
+	if len(config.WhatsappWebhookBatchEndpoints) > 0 && (config.WhatsappWebhookBatchSize < 1 || config.WhatsappWebhookBatchMaxLatency <= 0) {
+		logrus.Fatal("--webhook-batch-size must be at least 1 and --webhook-batch-max-latency greater than 0")
+	}
+	if config.WhatsappScriptTimeout <= 0 || config.WhatsappScriptTimeout > domainScript.MaxTimeout {
+		logrus.Fatalf("--script-timeout must be greater than 0 and at most %s", domainScript.MaxTimeout)
+	}
+	if config.WhatsappIdempotencyTTL <= 0 {
+		logrus.Fatal("--idempotency-ttl must be greater than 0")
+	}
+	if config.WhatsappBulkInterval < 0 || config.WhatsappBulkJitter < 0 {
+		logrus.Fatal("--bulk-interval and --bulk-jitter cannot be negative")
+	}
+	switch config.WhatsappScheduleMissedPolicy {
+	case domainSchedule.MissedFire, domainSchedule.MissedSkip:
+	default:
+		logrus.Fatalf("invalid schedule missed policy %q, use fire or skip", config.WhatsappScheduleMissedPolicy)
+	}
+
+	initEventSinks()
 	whatsappCli = whatsapp.InitWaCLI(ctx, whatsappDB, keysDB, chatStorageRepo)
 
 	// Usecase
 	accountUsecase = usecaseAccount.NewAccountUsecase(accountRepo, accountManager, chatStorageRepo)
 	appUsecase = usecase.NewAppService(chatStorageRepo)
 	chatUsecase = usecase.NewChatService(chatStorageRepo)
-	sendUsecase = usecase.NewSendService(appUsecase, chatStorageRepo, accountManager)
+	sendUsecase = usecase.NewSendService(appUsecase, chatStorageRepo, accountManager, accountRepo, pollRepo)
+	scheduleUsecase = usecase.NewScheduleService(scheduleRepo, sendUsecase)
+	sendUsecase = usecase.NewScheduledSendService(sendUsecase, scheduleUsecase)
+	templateUsecase = usecase.NewTemplateService(templateRepo)
+	sendUsecase = usecase.NewTemplateSendService(sendUsecase, templateUsecase)
+	statusUsecase = usecase.NewStatusService(statusRepo, appUsecase, chatStorageRepo, accountManager, accountRepo)
+	pollUsecase = usecase.NewPollService(pollRepo)
 	userUsecase = usecase.NewUserService()
 	messageUsecase = usecase.NewMessageService(chatStorageRepo)
 	groupUsecase = usecase.NewGroupService()
 	newsletterUsecase = usecase.NewNewsletterService()
+	webhookUsecase = usecase.NewWebhookService(webhookRuleRepo, webhookDeliveryRepo)
+	whatsapp.SetWebhookActionExecutor(usecase.NewWebhookActionExecutor(sendUsecase, messageUsecase))
+	autoReplyUsecase = usecase.NewAutoReplyService(autoReplyRepo, sendUsecase, chatStorageRepo)
+	flowUsecase = usecase.NewFlowService(flowRepo, sendUsecase)
+	scriptUsecase = usecase.NewScriptService(scriptRepo, sendUsecase, messageUsecase)
+	relayUsecase = usecase.NewRelayService(relayRepo, sendUsecase)
+	bulkUsecase = usecase.NewBulkService(bulkRepo, sendUsecase)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"time"
+
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
 )
 
+// DefaultWebhookSecret is the webhook secret of an install that did not configure one
+const DefaultWebhookSecret = "secret"
+
 var (
 	AppVersion             = "v7.9.0"
 	AppPort                = "3000"
@@ -29,7 +34,8 @@ var (
 	WhatsappAutoMarkRead           = false // Auto-mark incoming messages as read
 	WhatsappAutoDownloadMedia      = true  // Auto-download media from incoming messages
 	WhatsappWebhook                []string
-	WhatsappWebhookSecret                = "secret"
+	WhatsappWebhookSecret                = DefaultWebhookSecret
+	WhatsappWebhookCloudEvents           = false // Wrap webhook payloads in a CloudEvents 1.0 envelope
 	WhatsappLogLevel                     = "ERROR"
 	WhatsappSettingMaxImageSize    int64 = 20000000  // 20MB
 	WhatsappSettingMaxFileSize     int64 = 50000000  // 50MB
@@ -39,6 +45,37 @@ var (
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
 
+	WhatsappWebhookMediaMode                = "path"  // How downloaded media is delivered: path, inline or url
+	WhatsappWebhookMediaInlineMaxSize int64 = 5000000 // 5MB, larger files are delivered as signed URL in inline mode
+	WhatsappWebhookMediaURLExpiry           = 24 * time.Hour
+	WhatsappWebhookMediaBaseURL             = "" // Public URL of the gateway used in signed media URLs, e.g. https://wa.example.com
+
+	WhatsappWebhookReplyActions      = false              // Execute the reply actions returned in webhook response bodies
+	WhatsappWebhookDeliveryRetention = 7 * 24 * time.Hour // How long webhook delivery records are kept, 0 keeps everything
+
+	WhatsappWebhookBatchEndpoints  []string          // Webhook URLs that receive events in batches, "*" batches every URL
+	WhatsappWebhookBatchSize       = 50              // Events per batch
+	WhatsappWebhookBatchMaxLatency = 2 * time.Second // Longest an event waits for its batch to fill up
+
+	WhatsappWebhookSkipHistory = false // Do not forward messages backfilled by a history sync
+
+	WhatsappScriptHTTPAllowlist []string                   // URL prefixes scripts may call, e.g. https://api.example.com/
+	WhatsappScriptTimeout                = 2 * time.Second // How long a script may run for one event unless it sets its own timeout
+	WhatsappScriptMaxSteps      uint64   = 1000000         // Starlark execution steps a script may take for one event
+
+	WhatsappBulkInterval = 3 * time.Second // Pause between two messages of a bulk send job unless the job sets its own
+	WhatsappBulkJitter   = 2 * time.Second // Random extra pause of up to this long between two messages of a bulk send job
+
+	WhatsappScheduleMissedPolicy = "fire" // fire or skip, what happens to scheduled messages that came due while the gateway was down
+
+	WhatsappIdempotencyTTL = 24 * time.Hour // How long the response of a send with an Idempotency-Key is replayed
+
+	EventSinkNatsURL                 = ""
+	EventSinkNatsSubjectPrefix       = "whatsapp"
+	EventSinkRedisURL                = ""
+	EventSinkRedisStreamPrefix       = "whatsapp"
+	EventSinkRedisMaxLen       int64 = 10000 // approximate number of entries kept per stream, 0 keeps everything
+
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
@@ -19,6 +19,15 @@ type DevicesResponse struct {
 	Device string `json:"device"`
 }
 
+// QRCodeEvent is dispatched on the event bus of the client whenever a login QR code is generated
+type QRCodeEvent struct {
+	// QRLink is the path of the QR code image
+	QRLink string
+	Code   string
+	// Duration is how many seconds the code stays valid
+	Duration int
+}
+
 type LoginResponse struct {
 	ImagePath string        `json:"image_path"`
 	Duration  time.Duration `json:"duration"`
@@ -0,0 +1,53 @@
+package autoreply
+
+import (
+	"context"
+	"time"
+)
+
+type IAutoReplyUsecase interface {
+	ListRules(ctx context.Context) (rules []Rule, err error)
+	GetRule(ctx context.Context, ruleID string) (rule Rule, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (rule Rule, err error)
+	UpdateRule(ctx context.Context, ruleID string, request RuleRequest) (rule Rule, err error)
+	DeleteRule(ctx context.Context, ruleID string) (err error)
+
+	ListMatches(ctx context.Context, filter MatchFilter) (matches []Match, err error)
+	// Stats returns the match counts of every rule that matched at least once
+	Stats(ctx context.Context) (stats []RuleStats, err error)
+
+	ListSchedules(ctx context.Context) (schedules []Schedule, err error)
+	GetSchedule(ctx context.Context, accountID string) (schedule Schedule, err error)
+	SaveSchedule(ctx context.Context, accountID string, request ScheduleRequest) (schedule Schedule, err error)
+	DeleteSchedule(ctx context.Context, accountID string) (err error)
+}
+
+type IAutoReplyRepository interface {
+	InitializeSchema() error
+	ListRules() ([]Rule, error)
+	GetRule(ruleID string) (*Rule, error)
+	CreateRule(rule *Rule) error
+	UpdateRule(rule *Rule) error
+	DeleteRule(ruleID string) error
+
+	CreateMatch(match *Match) error
+	ListMatches(filter MatchFilter) ([]Match, error)
+	Stats() ([]RuleStats, error)
+
+	ListSchedules() ([]Schedule, error)
+	GetSchedule(accountID string) (*Schedule, error)
+	SaveSchedule(schedule *Schedule) error
+	DeleteSchedule(accountID string) error
+
+	// RecordContact remembers a contact that wrote to the account and reports whether it was unknown
+	RecordContact(accountID, contactJID string, seenAt time.Time) (isNew bool, err error)
+	// ClaimAwayWindow reports whether the contact has not received the away message of the closed period
+	// starting at window yet, and marks it as received
+	ClaimAwayWindow(accountID, contactJID string, window time.Time) (bool, error)
+}
+
+// IResponder answers incoming messages with the auto-reply rules, greetings and away messages
+type IResponder interface {
+	// Respond answers the message and reports whether it did
+	Respond(ctx context.Context, message Incoming) bool
+}
@@ -0,0 +1,45 @@
+package autoreply
+
+import "time"
+
+// Match statuses
+const (
+	MatchStatusSent     = "sent"
+	MatchStatusFailed   = "failed"
+	MatchStatusCooldown = "cooldown" // the rule matched but already replied to the contact within its cooldown
+)
+
+// Match is the record of a rule answering a message
+type Match struct {
+	ID        string `json:"id"`
+	RuleID    string `json:"rule_id"`
+	AccountID string `json:"account_id"`
+	ChatJID   string `json:"chat_jid"`
+	SenderJID string `json:"sender_jid"`
+	// MessageID is the received message, ReplyMessageID the last message the rule sent back
+	MessageID      string    `json:"message_id"`
+	ReplyMessageID string    `json:"reply_message_id,omitempty"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MatchFilter narrows down the recorded matches, empty fields match everything
+type MatchFilter struct {
+	RuleID    string `query:"rule_id"`
+	AccountID string `query:"account_id"`
+	ChatJID   string `query:"chat_jid"`
+	Status    string `query:"status"`
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+}
+
+// RuleStats sums up the recorded matches of a rule
+type RuleStats struct {
+	RuleID        string     `json:"rule_id"`
+	Matches       int        `json:"matches"`
+	Sent          int        `json:"sent"`
+	Failed        int        `json:"failed"`
+	Cooldown      int        `json:"cooldown"`
+	LastMatchedAt *time.Time `json:"last_matched_at,omitempty"`
+}
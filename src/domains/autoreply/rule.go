@@ -0,0 +1,191 @@
+package autoreply
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Trigger types
+const (
+	TriggerKeyword = "keyword" // one of the keywords appears as a word in the message
+	TriggerRegex   = "regex"   // the pattern matches somewhere in the message
+	TriggerExact   = "exact"   // the whole message equals the pattern, surrounding spaces are ignored
+)
+
+// Media types a rule can reply with
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+	MediaAudio = "audio"
+)
+
+// Rule answers incoming messages that match its trigger. The first enabled rule in ascending priority order
+// that matches a message replies to it.
+type Rule struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Enabled  bool   `json:"enabled"`
+	// AccountID limits the rule to one account, "default" is the single-device client and empty matches every account
+	AccountID string `json:"account_id,omitempty"`
+	// ChatJID limits the rule to one chat. Groups are only answered by rules scoped to them.
+	ChatJID       string   `json:"chat_jid,omitempty"`
+	TriggerType   string   `json:"trigger_type"`
+	Keywords      []string `json:"keywords,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+	CaseSensitive bool     `json:"case_sensitive"`
+	// Reply is a template, see RenderReply. It is the caption of image and video replies.
+	Reply     string `json:"reply,omitempty"`
+	MediaType string `json:"media_type,omitempty"`
+	MediaURL  string `json:"media_url,omitempty"`
+	// CooldownSeconds is how long the rule stays silent for a contact after replying to it, 0 always replies
+	CooldownSeconds int       `json:"cooldown_seconds"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Cooldown returns the cooldown of the rule as a duration
+func (r Rule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownSeconds) * time.Second
+}
+
+// RuleRequest creates or replaces a rule
+type RuleRequest struct {
+	Name            string   `json:"name"`
+	Priority        int      `json:"priority"`
+	Enabled         *bool    `json:"enabled"`
+	AccountID       string   `json:"account_id"`
+	ChatJID         string   `json:"chat_jid"`
+	TriggerType     string   `json:"trigger_type"`
+	Keywords        []string `json:"keywords"`
+	Pattern         string   `json:"pattern"`
+	CaseSensitive   bool     `json:"case_sensitive"`
+	Reply           string   `json:"reply"`
+	MediaType       string   `json:"media_type"`
+	MediaURL        string   `json:"media_url"`
+	CooldownSeconds int      `json:"cooldown_seconds"`
+}
+
+// Incoming is a received message the rules are matched against, Text is empty for media
+type Incoming struct {
+	AccountID string
+	ChatJID   string
+	SenderJID string
+	PushName  string
+	MessageID string
+	Text      string
+	IsGroup   bool
+	Timestamp time.Time
+}
+
+// RenderReply fills the placeholders of a reply template: {{pushname}} is the display name of the sender (their
+// phone number when they have none), {{phone}} the phone number and {{message}} the received text
+func RenderReply(template string, message Incoming) string {
+	phone, _, _ := strings.Cut(message.SenderJID, "@")
+	pushName := message.PushName
+	if pushName == "" {
+		pushName = phone
+	}
+	return strings.NewReplacer(
+		"{{pushname}}", pushName,
+		"{{phone}}", phone,
+		"{{message}}", message.Text,
+	).Replace(template)
+}
+
+// RuleSet is a compiled, ordered set of enabled rules
+type RuleSet struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	trigger *regexp.Regexp
+}
+
+// NewRuleSet compiles the triggers of the enabled rules, sorted by priority
+func NewRuleSet(rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		trigger, err := CompileTrigger(rule.TriggerType, rule.Keywords, rule.Pattern, rule.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		set.rules = append(set.rules, compiledRule{Rule: rule, trigger: trigger})
+	}
+	slices.SortStableFunc(set.rules, func(a, b compiledRule) int {
+		return a.Priority - b.Priority
+	})
+	return set, nil
+}
+
+// CompileTrigger turns every trigger type into one regular expression
+func CompileTrigger(triggerType string, keywords []string, pattern string, caseSensitive bool) (*regexp.Regexp, error) {
+	var expr string
+	switch triggerType {
+	case TriggerKeyword:
+		quoted := make([]string, 0, len(keywords))
+		for _, keyword := range keywords {
+			quoted = append(quoted, regexp.QuoteMeta(strings.TrimSpace(keyword)))
+		}
+		expr = `(^|[^\p{L}\p{N}_])(` + strings.Join(quoted, "|") + `)($|[^\p{L}\p{N}_])`
+	case TriggerExact:
+		expr = `^\s*` + regexp.QuoteMeta(strings.TrimSpace(pattern)) + `\s*$`
+	default:
+		expr = pattern
+	}
+	if !caseSensitive {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// Len returns the number of enabled rules
+func (s *RuleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+// Match returns the first rule that answers the message, or nil
+func (s *RuleSet) Match(message Incoming) *Rule {
+	if s == nil {
+		return nil
+	}
+	for _, rule := range s.rules {
+		if rule.matches(message) {
+			matched := rule.Rule
+			return &matched
+		}
+	}
+	return nil
+}
+
+func (r compiledRule) matches(message Incoming) bool {
+	if r.AccountID != "" && r.AccountID != message.AccountID {
+		return false
+	}
+	if r.ChatJID == "" {
+		if message.IsGroup {
+			return false
+		}
+	} else if !sameChat(r.ChatJID, message.ChatJID) {
+		return false
+	}
+	return r.trigger.MatchString(message.Text)
+}
+
+// sameChat compares a configured chat, a full JID or only its user part, with the JID of a message
+func sameChat(configured, jid string) bool {
+	if strings.Contains(configured, "@") {
+		return configured == jid
+	}
+	user, _, _ := strings.Cut(jid, "@")
+	return configured == user
+}
@@ -0,0 +1,67 @@
+package autoreply
+
+import "testing"
+
+func TestRuleSetMatch(t *testing.T) {
+	rules := []Rule{
+		{ID: "disabled", Enabled: false, TriggerType: TriggerRegex, Pattern: ".*"},
+		{ID: "price", Enabled: true, Priority: 10, TriggerType: TriggerKeyword, Keywords: []string{"price", "harga"}},
+		{ID: "hours", Enabled: true, Priority: 20, TriggerType: TriggerRegex, Pattern: `open\s+(today|tomorrow)`},
+		{ID: "menu", Enabled: true, Priority: 5, TriggerType: TriggerExact, Pattern: "MENU", CaseSensitive: true},
+		{ID: "sales-only", Enabled: true, Priority: 0, AccountID: "sales", TriggerType: TriggerKeyword, Keywords: []string{"price"}},
+		{ID: "group", Enabled: true, Priority: 30, ChatJID: "120363025@g.us", TriggerType: TriggerKeyword, Keywords: []string{"rules"}},
+	}
+	set, err := NewRuleSet(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Len() != 5 {
+		t.Fatalf("expected disabled rules to be skipped, got %d rules", set.Len())
+	}
+
+	direct := Incoming{AccountID: "default", ChatJID: "6281@s.whatsapp.net", SenderJID: "6281@s.whatsapp.net"}
+	group := Incoming{AccountID: "default", ChatJID: "120363025@g.us", SenderJID: "6281@s.whatsapp.net", IsGroup: true}
+
+	tests := []struct {
+		name    string
+		message Incoming
+		text    string
+		want    string
+	}{
+		{name: "keyword as a word", message: direct, text: "What's the PRICE?", want: "price"},
+		{name: "keyword inside another word", message: direct, text: "priceless", want: ""},
+		{name: "regex", message: direct, text: "are you open today", want: "hours"},
+		{name: "exact ignores surrounding spaces", message: direct, text: "  MENU ", want: "menu"},
+		{name: "exact is case sensitive", message: direct, text: "menu", want: ""},
+		{name: "exact needs the whole message", message: direct, text: "MENU please", want: ""},
+		{name: "account scoped rule goes first", message: Incoming{AccountID: "sales", ChatJID: direct.ChatJID}, text: "price", want: "sales-only"},
+		{name: "groups need a rule scoped to them", message: group, text: "price", want: ""},
+		{name: "group scoped rule", message: group, text: "group rules", want: "group"},
+		{name: "chat scoped rule ignores other chats", message: direct, text: "rules", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.message.Text = tt.text
+			got := ""
+			if rule := set.Match(tt.message); rule != nil {
+				got = rule.ID
+			}
+			if got != tt.want {
+				t.Errorf("Match() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderReply(t *testing.T) {
+	message := Incoming{SenderJID: "628123@s.whatsapp.net", PushName: "Dina", Text: "hi"}
+	if got := RenderReply("Hello {{pushname}} ({{phone}}), you said {{message}}", message); got != "Hello Dina (628123), you said hi" {
+		t.Errorf("RenderReply() = %q", got)
+	}
+
+	message.PushName = ""
+	if got := RenderReply("Hello {{pushname}}", message); got != "Hello 628123" {
+		t.Errorf("expected the phone number without a push name, got %q", got)
+	}
+}
@@ -0,0 +1,203 @@
+package autoreply
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Greetings and away messages are recorded as matches of these pseudo rules
+const (
+	RuleGreeting = "greeting"
+	RuleAway     = "away"
+)
+
+// DateLayout is the format of holiday dates
+const DateLayout = "2006-01-02"
+
+// Weekdays are the day names of business hours, indexed by time.Weekday
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// closedLookbackDays bounds how far back the start of a closed period is searched, longer closures start a new
+// away window every two weeks
+const closedLookbackDays = 14
+
+// Schedule holds the business hours of an account with the messages sent around them
+type Schedule struct {
+	// AccountID is the account the schedule belongs to, "default" is the single-device client
+	AccountID string `json:"account_id"`
+	Enabled   bool   `json:"enabled"`
+	// TimeZone is an IANA time zone name, e.g. Asia/Jakarta
+	TimeZone string          `json:"time_zone"`
+	Hours    []BusinessHours `json:"hours"`
+	Holidays []Holiday       `json:"holidays"`
+	// AwayMessage is sent outside business hours, at most once per contact per closed period
+	AwayMessage string `json:"away_message,omitempty"`
+	// GreetingMessage is sent the first time a new contact writes
+	GreetingMessage string    `json:"greeting_message,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// BusinessHours opens the business on a day of the week. A close time before the open time runs past midnight.
+type BusinessHours struct {
+	Day   string `json:"day"`
+	Open  string `json:"open"`  // HH:MM
+	Close string `json:"close"` // HH:MM, 24:00 is the end of the day
+}
+
+// Holiday closes the business on a date, or opens it with other hours when Open and Close are set
+type Holiday struct {
+	Date  string `json:"date"` // YYYY-MM-DD in the time zone of the schedule
+	Name  string `json:"name,omitempty"`
+	Open  string `json:"open,omitempty"`
+	Close string `json:"close,omitempty"`
+}
+
+// ScheduleRequest creates or replaces the schedule of an account
+type ScheduleRequest struct {
+	Enabled         *bool           `json:"enabled"`
+	TimeZone        string          `json:"time_zone"`
+	Hours           []BusinessHours `json:"hours"`
+	Holidays        []Holiday       `json:"holidays"`
+	AwayMessage     string          `json:"away_message"`
+	GreetingMessage string          `json:"greeting_message"`
+}
+
+// ParseClock converts HH:MM into minutes since midnight, 24:00 is allowed to end a day
+func ParseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, fmt.Errorf("%q is not in HH:MM format", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in HH:MM format", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in HH:MM format", value)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%q is not a time of day", value)
+	}
+	return h*60 + m, nil
+}
+
+// interval is an opening in minutes since the midnight of its day, close can run into the next day
+type interval struct {
+	open, close int
+}
+
+func parseInterval(open, close string) (interval, error) {
+	o, err := ParseClock(open)
+	if err != nil {
+		return interval{}, err
+	}
+	c, err := ParseClock(close)
+	if err != nil {
+		return interval{}, err
+	}
+	if o == c || o == 24*60 {
+		return interval{}, fmt.Errorf("opening %s-%s is empty", open, close)
+	}
+	if c < o {
+		c += 24 * 60
+	}
+	return interval{open: o, close: c}, nil
+}
+
+// CheckOpening reports whether open and close, in HH:MM, form a non-empty opening
+func CheckOpening(open, close string) error {
+	_, err := parseInterval(open, close)
+	return err
+}
+
+// Calendar is a compiled schedule that tells when the business is open
+type Calendar struct {
+	Schedule Schedule
+
+	location *time.Location
+	days     [7][]interval
+	holidays map[string][]interval
+}
+
+// NewCalendar compiles the schedule, an empty time zone is UTC
+func NewCalendar(schedule Schedule) (*Calendar, error) {
+	location, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("schedule of %s: unknown time zone %q", schedule.AccountID, schedule.TimeZone)
+	}
+
+	calendar := &Calendar{Schedule: schedule, location: location, holidays: make(map[string][]interval)}
+	for _, hours := range schedule.Hours {
+		day := weekdayIndex(hours.Day)
+		if day < 0 {
+			return nil, fmt.Errorf("schedule of %s: unknown day %q", schedule.AccountID, hours.Day)
+		}
+		opening, err := parseInterval(hours.Open, hours.Close)
+		if err != nil {
+			return nil, fmt.Errorf("schedule of %s: %w", schedule.AccountID, err)
+		}
+		calendar.days[day] = append(calendar.days[day], opening)
+	}
+	for _, holiday := range schedule.Holidays {
+		if _, err := time.ParseInLocation(DateLayout, holiday.Date, location); err != nil {
+			return nil, fmt.Errorf("schedule of %s: invalid holiday date %q", schedule.AccountID, holiday.Date)
+		}
+		openings := calendar.holidays[holiday.Date]
+		if holiday.Open != "" || holiday.Close != "" {
+			opening, err := parseInterval(holiday.Open, holiday.Close)
+			if err != nil {
+				return nil, fmt.Errorf("schedule of %s: %w", schedule.AccountID, err)
+			}
+			openings = append(openings, opening)
+		}
+		calendar.holidays[holiday.Date] = openings
+	}
+	return calendar, nil
+}
+
+// Window reports whether the business is open at t. When it is closed, since is when the closed period started,
+// it identifies the period a contact receives the away message for.
+func (c *Calendar) Window(t time.Time) (open bool, since time.Time) {
+	t = t.In(c.location)
+	year, month, day := t.Date()
+
+	// Openings of earlier days can run past midnight, so every day of the lookback is checked
+	for offset := 0; offset >= -closedLookbackDays; offset-- {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, c.location)
+		for _, opening := range c.openings(date) {
+			start := time.Date(year, month, day+offset, 0, opening.open, 0, 0, c.location)
+			end := time.Date(year, month, day+offset, 0, opening.close, 0, 0, c.location)
+			if !t.Before(start) && t.Before(end) {
+				return true, time.Time{}
+			}
+			if !end.After(t) && end.After(since) {
+				since = end
+			}
+		}
+	}
+
+	if since.IsZero() {
+		since = time.Date(year, month, day-closedLookbackDays, 0, 0, 0, 0, c.location)
+	}
+	return false, since
+}
+
+// openings returns the hours of a date, holidays replace the hours of their weekday
+func (c *Calendar) openings(date time.Time) []interval {
+	if openings, ok := c.holidays[date.Format(DateLayout)]; ok {
+		return openings
+	}
+	return c.days[date.Weekday()]
+}
+
+func weekdayIndex(name string) int {
+	for i, weekday := range Weekdays {
+		if strings.EqualFold(weekday, name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,96 @@
+package autoreply
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarWindow(t *testing.T) {
+	calendar, err := NewCalendar(Schedule{
+		AccountID: "default",
+		TimeZone:  "Asia/Jakarta",
+		Hours: []BusinessHours{
+			{Day: "monday", Open: "09:00", Close: "17:00"},
+			{Day: "tuesday", Open: "09:00", Close: "17:00"},
+			{Day: "friday", Open: "20:00", Close: "02:00"},
+		},
+		Holidays: []Holiday{
+			{Date: "2025-01-07", Name: "closed tuesday"},
+			{Date: "2025-01-11", Name: "special saturday", Open: "10:00", Close: "12:00"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, jakarta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		wantOpen  bool
+		wantSince time.Time
+	}{
+		{name: "open on monday", at: at("2025-01-06 10:00"), wantOpen: true},
+		{name: "opening is inclusive", at: at("2025-01-06 09:00"), wantOpen: true},
+		{name: "before opening", at: at("2025-01-06 08:59"), wantSince: at("2025-01-04 02:00")},
+		{name: "after closing", at: at("2025-01-06 17:00"), wantSince: at("2025-01-06 17:00")},
+		{name: "other time zone", at: at("2025-01-06 10:00").UTC(), wantOpen: true},
+		{name: "holiday is closed", at: at("2025-01-07 10:00"), wantSince: at("2025-01-06 17:00")},
+		{name: "open past midnight", at: at("2025-01-11 01:00"), wantOpen: true},
+		{name: "closed after the overnight opening", at: at("2025-01-11 03:00"), wantSince: at("2025-01-11 02:00")},
+		{name: "holiday with special hours", at: at("2025-01-11 11:00"), wantOpen: true},
+		{name: "after the special hours", at: at("2025-01-11 13:00"), wantSince: at("2025-01-11 12:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, since := calendar.Window(tt.at)
+			if open != tt.wantOpen {
+				t.Fatalf("open = %v, want %v", open, tt.wantOpen)
+			}
+			if !since.Equal(tt.wantSince) {
+				t.Errorf("since = %v, want %v", since, tt.wantSince)
+			}
+		})
+	}
+}
+
+func TestCalendarWindow_AlwaysClosed(t *testing.T) {
+	calendar, err := NewCalendar(Schedule{AccountID: "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	open, since := calendar.Window(now)
+	if open {
+		t.Fatal("expected a schedule without hours to be closed")
+	}
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !since.Equal(want) {
+		t.Errorf("since = %v, want the start of the lookback %v", since, want)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := map[string]int{"00:00": 0, "09:30": 570, "24:00": 1440, "24:30": -1, "9:30": -1, "12:60": -1, "ab:cd": -1}
+	for value, want := range tests {
+		got, err := ParseClock(value)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("ParseClock(%q) expected an error", value)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("ParseClock(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// MaxRecipients is the largest number of recipients of a job
+const MaxRecipients = 10000
+
+// Job statuses
+const (
+	JobRunning   = "running"
+	JobPaused    = "paused"
+	JobCompleted = "completed"
+	JobCancelled = "cancelled"
+)
+
+// Recipient statuses
+const (
+	RecipientPending   = "pending"
+	RecipientSent      = "sent"
+	RecipientFailed    = "failed"
+	RecipientCancelled = "cancelled"
+)
+
+// Job sends the same message to its recipients one after another, pausing between two messages
+type Job struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id,omitempty"`
+	Type      string `json:"type"`
+	// Payload is the request of the send endpoint, every recipient gets it with its own phone
+	Payload    json.RawMessage `json:"payload"`
+	Status     string          `json:"status"`
+	IntervalMs int             `json:"interval_ms"`
+	JitterMs   int             `json:"jitter_ms"`
+	Total      int             `json:"total"`
+	Sent       int             `json:"sent"`
+	Failed     int             `json:"failed"`
+	Cancelled  int             `json:"cancelled"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Pending returns how many recipients are still waiting for their message
+func (j Job) Pending() int {
+	return j.Total - j.Sent - j.Failed - j.Cancelled
+}
+
+// Delay returns the pause before the next message, the interval plus a random part of the jitter
+func (j Job) Delay() time.Duration {
+	delay := time.Duration(j.IntervalMs) * time.Millisecond
+	if j.JitterMs > 0 {
+		delay += time.Duration(rand.IntN(j.JitterMs+1)) * time.Millisecond
+	}
+	return delay
+}
+
+// JobRequest creates a job. IntervalMs and JitterMs default to --bulk-interval and --bulk-jitter.
+type JobRequest struct {
+	AccountID  string          `json:"account_id"`
+	Type       string          `json:"type"`
+	Recipients []string        `json:"recipients"`
+	Payload    json.RawMessage `json:"payload"`
+	IntervalMs *int            `json:"interval_ms"`
+	JitterMs   *int            `json:"jitter_ms"`
+}
+
+// Recipient is a phone number or group of a job with the outcome of its message
+type Recipient struct {
+	JobID     string     `json:"job_id"`
+	Position  int        `json:"position"`
+	Phone     string     `json:"phone"`
+	Status    string     `json:"status"`
+	MessageID string     `json:"message_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+type JobFilter struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type RecipientFilter struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+// ParseRecipientsCSV reads the recipients from the first column of a CSV file. A first row that isn't a phone
+// number or JID, like "phone", is taken for a header and skipped. Blank rows are ignored.
+func ParseRecipientsCSV(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var recipients []string
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		phone := strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff"))
+		if phone == "" || (line == 1 && !looksLikeRecipient(phone)) {
+			continue
+		}
+		recipients = append(recipients, phone)
+	}
+	return recipients, nil
+}
+
+func looksLikeRecipient(value string) bool {
+	if strings.Contains(value, "@") {
+		return true
+	}
+	value = strings.TrimPrefix(value, "+")
+	return value != "" && strings.Trim(value, "0123456789") == ""
+}
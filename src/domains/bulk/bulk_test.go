@@ -0,0 +1,67 @@
+package bulk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecipientsCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []string
+	}{
+		{
+			name: "header and extra columns",
+			csv:  "phone,name\n6281234567890,Dina\n+6289876543210,Budi\n",
+			want: []string{"6281234567890", "+6289876543210"},
+		},
+		{
+			name: "no header with blank rows and a group",
+			csv:  "6281234567890\n\n120363025246125486@g.us\n",
+			want: []string{"6281234567890", "120363025246125486@g.us"},
+		},
+		{
+			name: "byte order mark",
+			csv:  "\ufeffphone\r\n6281234567890\r\n",
+			want: []string{"6281234567890"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRecipientsCSV(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRecipientsCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseRecipientsCSV(strings.NewReader("\"6281")); err == nil {
+		t.Error("expected an error for malformed CSV")
+	}
+}
+
+func TestJobDelay(t *testing.T) {
+	job := Job{IntervalMs: 1000, JitterMs: 500}
+	for i := 0; i < 100; i++ {
+		if delay := job.Delay(); delay < time.Second || delay > 1500*time.Millisecond {
+			t.Fatalf("Delay() = %s, want between 1s and 1.5s", delay)
+		}
+	}
+	if delay := (Job{IntervalMs: 250}).Delay(); delay != 250*time.Millisecond {
+		t.Errorf("Delay() without jitter = %s, want 250ms", delay)
+	}
+}
+
+func TestJobPending(t *testing.T) {
+	job := Job{Total: 10, Sent: 4, Failed: 1, Cancelled: 2}
+	if got := job.Pending(); got != 3 {
+		t.Errorf("Pending() = %d, want 3", got)
+	}
+}
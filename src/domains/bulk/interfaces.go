@@ -0,0 +1,36 @@
+package bulk
+
+import (
+	"context"
+	"time"
+)
+
+type IBulkUsecase interface {
+	// CreateJob stores the job with its recipients and starts sending
+	CreateJob(ctx context.Context, request JobRequest) (job Job, err error)
+	ListJobs(ctx context.Context, filter JobFilter) (jobs []Job, err error)
+	GetJob(ctx context.Context, jobID string) (job Job, err error)
+	ListRecipients(ctx context.Context, jobID string, filter RecipientFilter) (recipients []Recipient, err error)
+	PauseJob(ctx context.Context, jobID string) (job Job, err error)
+	ResumeJob(ctx context.Context, jobID string) (job Job, err error)
+	// CancelJob stops the job for good, its pending recipients are cancelled
+	CancelJob(ctx context.Context, jobID string) (job Job, err error)
+	// ResumeJobs restarts the jobs that were running when the gateway stopped, called once the client connected
+	ResumeJobs(ctx context.Context)
+}
+
+type IBulkRepository interface {
+	InitializeSchema() error
+	// CreateJob stores the job with one pending recipient per phone, in order
+	CreateJob(job *Job, phones []string) error
+	GetJob(jobID string) (*Job, error)
+	ListJobs(filter JobFilter) ([]Job, error)
+	UpdateJobStatus(jobID, status string, finishedAt *time.Time) error
+	// NextRecipient returns the first pending recipient of the job, a not found error when none is left
+	NextRecipient(jobID string) (*Recipient, error)
+	// RecordResult stores the outcome of a recipient and counts it on its job
+	RecordResult(recipient *Recipient) error
+	// CancelPending cancels the pending recipients of the job
+	CancelPending(jobID string) error
+	ListRecipients(jobID string, filter RecipientFilter) ([]Recipient, error)
+}
@@ -14,21 +14,24 @@ type Chat struct {
 
 // Message represents a WhatsApp message
 type Message struct {
-	ID            string    `db:"id"`
-	ChatJID       string    `db:"chat_jid"`
-	Sender        string    `db:"sender"`
-	Content       string    `db:"content"`
-	Timestamp     time.Time `db:"timestamp"`
-	IsFromMe      bool      `db:"is_from_me"`
-	MediaType     string    `db:"media_type"`
-	Filename      string    `db:"filename"`
-	URL           string    `db:"url"`
-	MediaKey      []byte    `db:"media_key"`
-	FileSHA256    []byte    `db:"file_sha256"`
-	FileEncSHA256 []byte    `db:"file_enc_sha256"`
-	FileLength    uint64    `db:"file_length"`
-	CreatedAt     time.Time `db:"created_at"`
-	UpdatedAt     time.Time `db:"updated_at"`
+	ID              string    `db:"id"`
+	ChatJID         string    `db:"chat_jid"`
+	Sender          string    `db:"sender"`
+	Content         string    `db:"content"`
+	Timestamp       time.Time `db:"timestamp"`
+	IsFromMe        bool      `db:"is_from_me"`
+	MediaType       string    `db:"media_type"`
+	Filename        string    `db:"filename"`
+	URL             string    `db:"url"`
+	MediaKey        []byte    `db:"media_key"`
+	FileSHA256      []byte    `db:"file_sha256"`
+	FileEncSHA256   []byte    `db:"file_enc_sha256"`
+	FileLength      uint64    `db:"file_length"`
+	Mimetype        string    `db:"mimetype"`
+	DirectPath      string    `db:"direct_path"`
+	ForwardingScore uint32    `db:"forwarding_score"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
 }
 
 // MediaInfo represents downloadable media information
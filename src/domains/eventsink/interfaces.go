@@ -0,0 +1,21 @@
+package eventsink
+
+import "context"
+
+// DefaultAccountID names the single-device (non multi-account) client in subjects and stream keys
+const DefaultAccountID = "default"
+
+// Event is a WhatsApp event published to every configured sink
+type Event struct {
+	AccountID string
+	Type      string
+	// Payload is marshalled to JSON as is: a typed webhook payload or its CloudEvents envelope
+	Payload any
+}
+
+type IEventSink interface {
+	// Name identifies the sink in logs
+	Name() string
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
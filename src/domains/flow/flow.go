@@ -0,0 +1,212 @@
+package flow
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Input types a state can expect, states with options expect one of them
+const (
+	InputText   = "text"
+	InputNumber = "number"
+)
+
+// DefaultTimeout ends sessions that are inactive for longer when the flow sets no timeout
+const DefaultTimeout = 30 * time.Minute
+
+// maxTransitions stops states that pass through to each other without waiting for input from looping forever
+const maxTransitions = 20
+
+// Flow is a menu-driven conversation. A contact starts it by sending one of the triggers, every state sends its
+// prompt and waits for the input it expects before moving to the next state.
+type Flow struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// AccountID limits the flow to one account, "default" is the single-device client and empty is every account
+	AccountID string `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	// Triggers are the messages that start the flow, compared case-insensitively without surrounding spaces
+	Triggers []string `json:"triggers" yaml:"triggers"`
+	Start    string   `json:"start" yaml:"start"`
+	// TimeoutSeconds ends sessions without input for longer, 0 uses DefaultTimeout
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
+	TimeoutMessage string `json:"timeout_message,omitempty" yaml:"timeout_message,omitempty"`
+	// HandoffTimeoutSeconds gives sessions handed off to a human back to automation, 0 keeps them until released
+	HandoffTimeoutSeconds int              `json:"handoff_timeout_seconds,omitempty" yaml:"handoff_timeout_seconds,omitempty"`
+	States                map[string]State `json:"states" yaml:"states"`
+	CreatedAt             time.Time        `json:"created_at" yaml:"-"`
+	UpdatedAt             time.Time        `json:"updated_at" yaml:"-"`
+}
+
+// State sends its prompt when entered. With options it waits for one of them, with an input type it waits for
+// that input and moves to Next, without either it moves to Next right away or ends the session when Next is empty.
+type State struct {
+	// Prompt is a template, see Render
+	Prompt  string   `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Options []Option `json:"options,omitempty" yaml:"options,omitempty"`
+	Input   string   `json:"input,omitempty" yaml:"input,omitempty"`
+	// Pattern is a regular expression text input has to match
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// SaveAs stores the input in the session variables under this name
+	SaveAs string `json:"save_as,omitempty" yaml:"save_as,omitempty"`
+	Next   string `json:"next,omitempty" yaml:"next,omitempty"`
+	// Invalid is sent when the input is not expected, the prompt is repeated when it is empty
+	Invalid string `json:"invalid,omitempty" yaml:"invalid,omitempty"`
+	// Handoff hands the conversation to a human, automation stops for the contact until the session is released
+	Handoff bool `json:"handoff,omitempty" yaml:"handoff,omitempty"`
+}
+
+// Option moves to the next state when the contact replies with the input
+type Option struct {
+	Input string `json:"input" yaml:"input"`
+	Next  string `json:"next" yaml:"next"`
+}
+
+// ParseFlow reads a flow definition in YAML or JSON
+func ParseFlow(data []byte) (Flow, error) {
+	var flow Flow
+	// JSON is valid YAML, so one decoder reads both
+	if err := yaml.Unmarshal(data, &flow); err != nil {
+		return flow, fmt.Errorf("invalid flow definition: %w", err)
+	}
+	return flow, nil
+}
+
+// IsEnabled reports whether contacts can start the flow
+func (f Flow) IsEnabled() bool {
+	return f.Enabled == nil || *f.Enabled
+}
+
+// Timeout returns how long a session may wait for input
+func (f Flow) Timeout() time.Duration {
+	if f.TimeoutSeconds <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(f.TimeoutSeconds) * time.Second
+}
+
+// IsTriggeredBy reports whether the message starts the flow for the account
+func (f Flow) IsTriggeredBy(accountID, text string) bool {
+	if !f.IsEnabled() || (f.AccountID != "" && f.AccountID != accountID) {
+		return false
+	}
+	text = strings.TrimSpace(text)
+	for _, trigger := range f.Triggers {
+		if strings.EqualFold(strings.TrimSpace(trigger), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enter moves the session into the state and returns the prompts to send, following the states that expect no
+// input. The session ends when a state without input has nowhere to go.
+func (f Flow) Enter(session *Session, name string) []string {
+	var replies []string
+	for range maxTransitions {
+		state, ok := f.States[name]
+		if !ok {
+			session.Status = SessionCompleted
+			return replies
+		}
+
+		session.State = name
+		if state.Prompt != "" {
+			replies = append(replies, Render(state.Prompt, session.Vars))
+		}
+
+		switch {
+		case state.Handoff:
+			session.Status = SessionHandoff
+			return replies
+		case len(state.Options) > 0 || state.Input != "":
+			session.Status = SessionActive
+			return replies
+		case state.Next == "":
+			session.Status = SessionCompleted
+			return replies
+		}
+		name = state.Next
+	}
+
+	session.Status = SessionCompleted
+	return replies
+}
+
+// Advance handles a message of the contact in the current state of the session and returns the replies to send
+func (f Flow) Advance(session *Session, text string) []string {
+	state, ok := f.States[session.State]
+	if !ok {
+		session.Status = SessionCompleted
+		return nil
+	}
+
+	text = strings.TrimSpace(text)
+	session.Vars["message"] = text
+
+	next, valid := state.accept(text)
+	if !valid {
+		reply := state.Invalid
+		if reply == "" {
+			reply = state.Prompt
+		}
+		if reply == "" {
+			return nil
+		}
+		return []string{Render(reply, session.Vars)}
+	}
+
+	if state.SaveAs != "" {
+		session.Vars[state.SaveAs] = text
+	}
+	if next == "" {
+		session.Status = SessionCompleted
+		return nil
+	}
+	return f.Enter(session, next)
+}
+
+// accept returns the state the input leads to and whether the state expects it
+func (s State) accept(text string) (next string, valid bool) {
+	if len(s.Options) > 0 {
+		for _, option := range s.Options {
+			if strings.EqualFold(strings.TrimSpace(option.Input), text) {
+				return option.Next, true
+			}
+		}
+		return "", false
+	}
+
+	switch s.Input {
+	case InputNumber:
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return "", false
+		}
+	case InputText:
+		if text == "" {
+			return "", false
+		}
+		if s.Pattern != "" {
+			if matched, err := regexp.MatchString(s.Pattern, text); err != nil || !matched {
+				return "", false
+			}
+		}
+	}
+	return s.Next, true
+}
+
+// Render replaces {{name}} with the session variable of that name. Sessions start with pushname and phone,
+// message is the last input and every state with save_as adds its input.
+func Render(template string, vars map[string]string) string {
+	pairs := make([]string, 0, len(vars)*2)
+	for name, value := range vars {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -0,0 +1,118 @@
+package flow
+
+import (
+	"reflect"
+	"testing"
+)
+
+const supportFlow = `
+id: support
+triggers: [menu, help]
+start: main
+states:
+  main:
+    prompt: "Hi {{pushname}}! Reply 1 for billing or 0 for a human"
+    options:
+      - input: "1"
+        next: billing
+      - input: "0"
+        next: human
+    invalid: Please reply 1 or 0
+  billing:
+    prompt: Send your invoice number
+    input: number
+    save_as: invoice
+    next: received
+  received:
+    prompt: "Thanks, invoice {{invoice}} is being checked"
+    next: goodbye
+  goodbye:
+    prompt: Bye!
+  human:
+    prompt: A human will answer shortly
+    handoff: true
+`
+
+func TestFlowConversation(t *testing.T) {
+	flow, err := ParseFlow([]byte(supportFlow))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &Session{Vars: map[string]string{"pushname": "Dina"}}
+	steps := []struct {
+		name        string
+		input       string
+		wantReplies []string
+		wantState   string
+		wantStatus  string
+	}{
+		{name: "unknown option", input: "2", wantReplies: []string{"Please reply 1 or 0"}, wantState: "main", wantStatus: SessionActive},
+		{name: "option", input: " 1 ", wantReplies: []string{"Send your invoice number"}, wantState: "billing", wantStatus: SessionActive},
+		{name: "not a number repeats the prompt", input: "abc", wantReplies: []string{"Send your invoice number"}, wantState: "billing", wantStatus: SessionActive},
+		{
+			name:        "saved input and pass-through states",
+			input:       "42",
+			wantReplies: []string{"Thanks, invoice 42 is being checked", "Bye!"},
+			wantState:   "goodbye",
+			wantStatus:  SessionCompleted,
+		},
+	}
+
+	if got := flow.Enter(session, flow.Start); !reflect.DeepEqual(got, []string{"Hi Dina! Reply 1 for billing or 0 for a human"}) {
+		t.Fatalf("Enter() = %q", got)
+	}
+	for _, step := range steps {
+		got := flow.Advance(session, step.input)
+		if !reflect.DeepEqual(got, step.wantReplies) {
+			t.Errorf("%s: replies = %q, want %q", step.name, got, step.wantReplies)
+		}
+		if session.State != step.wantState || session.Status != step.wantStatus {
+			t.Errorf("%s: session is %s/%s, want %s/%s", step.name, session.State, session.Status, step.wantState, step.wantStatus)
+		}
+	}
+}
+
+func TestFlowHandoff(t *testing.T) {
+	flow, err := ParseFlow([]byte(supportFlow))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &Session{Vars: map[string]string{}}
+	flow.Enter(session, flow.Start)
+	if got := flow.Advance(session, "0"); !reflect.DeepEqual(got, []string{"A human will answer shortly"}) {
+		t.Errorf("Advance() = %q", got)
+	}
+	if session.Status != SessionHandoff {
+		t.Errorf("status = %s, want %s", session.Status, SessionHandoff)
+	}
+}
+
+func TestParseFlow_JSON(t *testing.T) {
+	flow, err := ParseFlow([]byte(`{"id": "survey", "triggers": ["survey"], "start": "ask", "enabled": false,
+		"states": {"ask": {"prompt": "How are we doing?", "input": "text", "pattern": "^[1-5]$"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flow.ID != "survey" || flow.States["ask"].Pattern != "^[1-5]$" {
+		t.Errorf("unexpected flow %+v", flow)
+	}
+	if flow.IsEnabled() || flow.IsTriggeredBy("default", "survey") {
+		t.Error("expected a disabled flow not to start")
+	}
+}
+
+func TestFlowIsTriggeredBy(t *testing.T) {
+	flow := Flow{AccountID: "sales", Triggers: []string{"Menu"}}
+
+	if !flow.IsTriggeredBy("sales", "  menu ") {
+		t.Error("expected triggers to ignore case and surrounding spaces")
+	}
+	if flow.IsTriggeredBy("default", "menu") {
+		t.Error("expected the flow to be limited to its account")
+	}
+	if flow.IsTriggeredBy("sales", "show me the menu") {
+		t.Error("expected triggers to match the whole message")
+	}
+}
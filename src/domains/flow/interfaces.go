@@ -0,0 +1,35 @@
+package flow
+
+import (
+	"context"
+	"time"
+)
+
+type IFlowUsecase interface {
+	ListFlows(ctx context.Context) (flows []Flow, err error)
+	GetFlow(ctx context.Context, flowID string) (flow Flow, err error)
+	// CreateFlow and UpdateFlow take the definition in YAML or JSON
+	CreateFlow(ctx context.Context, definition []byte) (flow Flow, err error)
+	UpdateFlow(ctx context.Context, flowID string, definition []byte) (flow Flow, err error)
+	DeleteFlow(ctx context.Context, flowID string) (err error)
+
+	ListSessions(ctx context.Context, filter SessionFilter) (sessions []Session, err error)
+	// ReleaseSession ends the session of a contact, automation answers the contact again afterwards
+	ReleaseSession(ctx context.Context, accountID, contactJID string) (err error)
+}
+
+type IFlowRepository interface {
+	InitializeSchema() error
+	ListFlows() ([]Flow, error)
+	GetFlow(flowID string) (*Flow, error)
+	CreateFlow(flow *Flow) error
+	UpdateFlow(flow *Flow) error
+	DeleteFlow(flowID string) error
+
+	GetSession(accountID, contactJID string) (*Session, error)
+	// SaveSession creates or replaces the session of the contact
+	SaveSession(session *Session) error
+	ListSessions(filter SessionFilter) ([]Session, error)
+	// ListExpiredSessions returns the open sessions that timed out at now
+	ListExpiredSessions(now time.Time) ([]Session, error)
+}
@@ -0,0 +1,45 @@
+package flow
+
+import "time"
+
+// Session statuses
+const (
+	SessionActive    = "active"    // waiting for input of the contact
+	SessionHandoff   = "handoff"   // a human answers, automation is stopped for the contact
+	SessionCompleted = "completed" // the flow reached a state without a next one
+	SessionExpired   = "expired"   // the contact didn't answer in time or the handoff timed out
+	SessionReleased  = "released"  // ended through the API
+)
+
+// Session is the progress of a contact through a flow, there is one per contact and account
+type Session struct {
+	AccountID  string            `json:"account_id"`
+	ContactJID string            `json:"contact_jid"`
+	FlowID     string            `json:"flow_id"`
+	State      string            `json:"state"`
+	Status     string            `json:"status"`
+	Vars       map[string]string `json:"vars"`
+	StartedAt  time.Time         `json:"started_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	// ExpiresAt is when an active or handed off session times out, nil when it doesn't
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// IsOpen reports whether the session still takes part in the conversation
+func (s Session) IsOpen() bool {
+	return s.Status == SessionActive || s.Status == SessionHandoff
+}
+
+// IsExpired reports whether an open session timed out at now
+func (s Session) IsExpired(now time.Time) bool {
+	return s.IsOpen() && s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// SessionFilter narrows down the listed sessions
+type SessionFilter struct {
+	AccountID string `query:"account_id"`
+	FlowID    string `query:"flow_id"`
+	Status    string `query:"status"`
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+}
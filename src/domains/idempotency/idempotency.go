@@ -0,0 +1,25 @@
+package idempotency
+
+import "time"
+
+// Header is the request header that carries the key
+const Header = "Idempotency-Key"
+
+// MaxKeyLength is the longest key accepted
+const MaxKeyLength = 255
+
+// Record is a request made with an idempotency key. A record without a status code is still being handled.
+type Record struct {
+	Key string
+	// Fingerprint is a hash of the method, path and payload of the request
+	Fingerprint string
+	StatusCode  int
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Pending reports whether the request of the record has not finished yet
+func (r Record) Pending() bool {
+	return r.StatusCode == 0
+}
@@ -0,0 +1,15 @@
+package idempotency
+
+import "time"
+
+type IIdempotencyRepository interface {
+	InitializeSchema() error
+	// Reserve claims the key for a request. When the key is taken and not expired, the record holding it is
+	// returned instead and reserved is false.
+	Reserve(record *Record) (existing *Record, reserved bool, err error)
+	// Complete stores the response of the request that reserved the key
+	Complete(key string, statusCode int, response []byte) error
+	// Release frees a key whose request failed, so it can be retried
+	Release(key string) error
+	DeleteExpired(now time.Time) error
+}
@@ -0,0 +1,18 @@
+package poll
+
+import "context"
+
+type IPollUsecase interface {
+	// GetResults returns the tally of a poll sent by the gateway
+	GetResults(ctx context.Context, messageID string) (results Results, err error)
+}
+
+type IPollRepository interface {
+	InitializeSchema() error
+	CreatePoll(poll *Poll) error
+	GetPoll(messageID string) (*Poll, error)
+	// PutVote stores the vote as the current choice of its voter, unless a later vote of them is stored already.
+	// changed is false when the vote was older or the same as the stored one.
+	PutVote(vote *Vote) (changed bool, err error)
+	ListVotes(messageID string) ([]Vote, error)
+}
@@ -0,0 +1,91 @@
+package poll
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"time"
+)
+
+// Poll is a poll sent by the gateway with the secret its votes are encrypted with
+type Poll struct {
+	MessageID string    `json:"message_id"`
+	AccountID string    `json:"account_id,omitempty"`
+	ChatJID   string    `json:"chat_jid"`
+	Question  string    `json:"question"`
+	Options   []string  `json:"options"`
+	MaxAnswer int       `json:"max_answer"`
+	Secret    []byte    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Vote is the current choice of a voter, no options means the vote was taken back
+type Vote struct {
+	MessageID string    `json:"message_id"`
+	VoterJID  string    `json:"voter_jid"`
+	Options   []string  `json:"options"`
+	VotedAt   time.Time `json:"voted_at"`
+}
+
+// Results is the tally of a poll
+type Results struct {
+	MessageID string         `json:"message_id"`
+	ChatJID   string         `json:"chat_jid"`
+	Question  string         `json:"question"`
+	Options   []OptionResult `json:"options"`
+	// Voters is how many voters have at least one option selected
+	Voters int `json:"voters"`
+}
+
+type OptionResult struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+// OptionNames maps the SHA-256 hashes a vote selects options by to the options of the poll. Hashes that match no
+// option are left out.
+func (p Poll) OptionNames(hashes [][]byte) []string {
+	names := make([]string, 0, len(hashes))
+	for _, option := range p.Options {
+		hash := sha256.Sum256([]byte(option))
+		for _, selected := range hashes {
+			if bytes.Equal(hash[:], selected) {
+				names = append(names, option)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Tally counts the votes per option in the order of the poll, voters are listed in the order the votes are given
+func (p Poll) Tally(votes []Vote) Results {
+	results := Results{
+		MessageID: p.MessageID,
+		ChatJID:   p.ChatJID,
+		Question:  p.Question,
+		Options:   make([]OptionResult, len(p.Options)),
+	}
+	index := make(map[string]int, len(p.Options))
+	for i, option := range p.Options {
+		results.Options[i] = OptionResult{Name: option, Voters: []string{}}
+		index[option] = i
+	}
+
+	for _, vote := range votes {
+		counted := false
+		for _, option := range vote.Options {
+			i, ok := index[option]
+			if !ok {
+				continue
+			}
+			results.Options[i].Votes++
+			results.Options[i].Voters = append(results.Options[i].Voters, vote.VoterJID)
+			counted = true
+		}
+		if counted {
+			results.Voters++
+		}
+	}
+	return results
+}
@@ -0,0 +1,35 @@
+package poll
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollOptionNames(t *testing.T) {
+	poll := Poll{Options: []string{"Pizza", "Sushi", "Tacos"}}
+	tacos := sha256.Sum256([]byte("Tacos"))
+	pizza := sha256.Sum256([]byte("Pizza"))
+	unknown := sha256.Sum256([]byte("Burgers"))
+
+	assert.Equal(t, []string{"Pizza", "Tacos"}, poll.OptionNames([][]byte{tacos[:], unknown[:], pizza[:]}))
+	assert.Equal(t, []string{}, poll.OptionNames(nil))
+}
+
+func TestPollTally(t *testing.T) {
+	poll := Poll{MessageID: "3EB0POLL", ChatJID: "120363402106XXXXX@g.us", Question: "Lunch?", Options: []string{"Pizza", "Sushi", "Tacos"}}
+	votes := []Vote{
+		{VoterJID: "6281111111111@s.whatsapp.net", Options: []string{"Pizza", "Tacos"}},
+		{VoterJID: "6282222222222@s.whatsapp.net", Options: []string{"Tacos"}},
+		{VoterJID: "6283333333333@s.whatsapp.net"},
+	}
+
+	results := poll.Tally(votes)
+	assert.Equal(t, 2, results.Voters)
+	assert.Equal(t, []OptionResult{
+		{Name: "Pizza", Votes: 1, Voters: []string{"6281111111111@s.whatsapp.net"}},
+		{Name: "Sushi", Votes: 0, Voters: []string{}},
+		{Name: "Tacos", Votes: 2, Voters: []string{"6281111111111@s.whatsapp.net", "6282222222222@s.whatsapp.net"}},
+	}, results.Options)
+}
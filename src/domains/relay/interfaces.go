@@ -0,0 +1,39 @@
+package relay
+
+import (
+	"context"
+	"time"
+)
+
+type IRelayUsecase interface {
+	ListRules(ctx context.Context) (rules []Rule, err error)
+	GetRule(ctx context.Context, ruleID string) (rule Rule, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (rule Rule, err error)
+	UpdateRule(ctx context.Context, ruleID string, request RuleRequest) (rule Rule, err error)
+	DeleteRule(ctx context.Context, ruleID string) (err error)
+
+	// ListMessages returns the messages sent by the relay, newest first
+	ListMessages(ctx context.Context, filter MessageFilter) (messages []Message, err error)
+}
+
+type IRelayRepository interface {
+	InitializeSchema() error
+	ListRules() ([]Rule, error)
+	GetRule(ruleID string) (*Rule, error)
+	CreateRule(rule *Rule) error
+	UpdateRule(rule *Rule) error
+	DeleteRule(ruleID string) error
+
+	SaveMessage(message *Message) error
+	// GetMessage returns the relayed message sent with the ID
+	GetMessage(messageID string) (*Message, error)
+	ListMessages(filter MessageFilter) ([]Message, error)
+	// DeleteMessage forgets a message recorded before it was sent, when sending it failed
+	DeleteMessage(messageID string) error
+	DeleteMessagesBefore(before time.Time) (int64, error)
+}
+
+// IRelay relays incoming messages according to the relay rules
+type IRelay interface {
+	Relay(ctx context.Context, message Incoming)
+}
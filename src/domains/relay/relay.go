@@ -0,0 +1,135 @@
+package relay
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DefaultHeader attributes relayed messages to their sender when a rule has no header of its own
+const DefaultHeader = "*{{pushname}}* (+{{phone}}):"
+
+// Directions of a relayed message
+const (
+	DirectionRelay  = "relay"
+	DirectionBridge = "bridge"
+)
+
+// Rule relays the messages an account receives, optionally only those of one chat, to a destination chat
+type Rule struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Enabled         bool   `json:"enabled"`
+	SourceAccountID string `json:"source_account_id"`
+	// SourceChatJID limits the rule to one chat, a JID or a phone number
+	SourceChatJID        string    `json:"source_chat_jid,omitempty"`
+	DestinationAccountID string    `json:"destination_account_id,omitempty"`
+	DestinationChatJID   string    `json:"destination_chat_jid"`
+	Header               string    `json:"header"`
+	BridgeReplies        bool      `json:"bridge_replies"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// RuleRequest creates or updates a relay rule. Enabled defaults to true.
+type RuleRequest struct {
+	Name                 string `json:"name"`
+	Enabled              *bool  `json:"enabled"`
+	SourceAccountID      string `json:"source_account_id"`
+	SourceChatJID        string `json:"source_chat_jid"`
+	DestinationAccountID string `json:"destination_account_id"`
+	DestinationChatJID   string `json:"destination_chat_jid"`
+	Header               string `json:"header"`
+	BridgeReplies        bool   `json:"bridge_replies"`
+}
+
+// Matches reports whether the rule relays a message received by the account in the chat
+func (r Rule) Matches(accountID, chatJID string) bool {
+	if !r.Enabled || r.SourceAccountID != accountID {
+		return false
+	}
+	if r.SourceChatJID != "" && !sameChat(r.SourceChatJID, chatJID) {
+		return false
+	}
+	// Never relay the destination into itself
+	return !(r.DestinationAccount() == accountID && sameChat(r.DestinationChatJID, chatJID))
+}
+
+// sameChat compares a configured chat, either a JID or a phone number, to the JID of a chat
+func sameChat(configured, jid string) bool {
+	if strings.Contains(configured, "@") {
+		return configured == jid
+	}
+	user, _, _ := strings.Cut(jid, "@")
+	return configured == user
+}
+
+// DestinationAccount returns the account that sends the relayed messages, the source account by default
+func (r Rule) DestinationAccount() string {
+	if r.DestinationAccountID == "" {
+		return r.SourceAccountID
+	}
+	return r.DestinationAccountID
+}
+
+// Attribution renders the header of a relayed message
+func (r Rule) Attribution(message Incoming) string {
+	header := r.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+
+	phone, _, _ := strings.Cut(message.SenderJID, "@")
+	pushName := message.PushName
+	if pushName == "" {
+		pushName = phone
+	}
+	chat, _, _ := strings.Cut(message.ChatJID, "@")
+	return strings.NewReplacer(
+		"{{pushname}}", pushName,
+		"{{phone}}", phone,
+		"{{chat}}", chat,
+		"{{account}}", message.AccountID,
+	).Replace(header)
+}
+
+// Incoming is a message received by an account, Text is the caption of media
+type Incoming struct {
+	AccountID       string
+	ChatJID         string
+	SenderJID       string
+	PushName        string
+	MessageID       string
+	QuotedMessageID string
+	Text            string
+	Media           *Media
+}
+
+// Media is an attachment of an incoming message, downloaded only when a rule relays it
+type Media struct {
+	Type     string
+	MimeType string
+	FileName string
+	Download func(ctx context.Context) ([]byte, error)
+}
+
+// Message is a message sent by the relay, kept to bridge replies back and to recognize relayed messages
+// when another account of the gateway receives them
+type Message struct {
+	MessageID       string    `json:"message_id"`
+	AccountID       string    `json:"account_id"`
+	ChatJID         string    `json:"chat_jid"`
+	RuleID          string    `json:"rule_id"`
+	Direction       string    `json:"direction"`
+	SourceAccountID string    `json:"source_account_id"`
+	SourceChatJID   string    `json:"source_chat_jid"`
+	SourceMessageID string    `json:"source_message_id"`
+	SourceSenderJID string    `json:"source_sender_jid"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type MessageFilter struct {
+	RuleID string `json:"rule_id" query:"rule_id"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,73 @@
+package relay
+
+import "testing"
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		account string
+		chat    string
+		want    bool
+	}{
+		{
+			name:    "every chat of the account",
+			rule:    Rule{Enabled: true, SourceAccountID: "default", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "6281@s.whatsapp.net", want: true,
+		},
+		{
+			name:    "disabled",
+			rule:    Rule{SourceAccountID: "default", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "6281@s.whatsapp.net", want: false,
+		},
+		{
+			name:    "other account",
+			rule:    Rule{Enabled: true, SourceAccountID: "sales", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "6281@s.whatsapp.net", want: false,
+		},
+		{
+			name:    "source chat by phone",
+			rule:    Rule{Enabled: true, SourceAccountID: "default", SourceChatJID: "6281", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "6281@s.whatsapp.net", want: true,
+		},
+		{
+			name:    "other source chat",
+			rule:    Rule{Enabled: true, SourceAccountID: "default", SourceChatJID: "6282@s.whatsapp.net", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "6281@s.whatsapp.net", want: false,
+		},
+		{
+			name:    "destination chat of the same account",
+			rule:    Rule{Enabled: true, SourceAccountID: "default", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "120363025@g.us", want: false,
+		},
+		{
+			name:    "destination chat on another account",
+			rule:    Rule{Enabled: true, SourceAccountID: "default", DestinationAccountID: "support", DestinationChatJID: "120363025@g.us"},
+			account: "default", chat: "120363025@g.us", want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.account, tt.chat); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleAttribution(t *testing.T) {
+	message := Incoming{AccountID: "default", ChatJID: "120363025@g.us", SenderJID: "6281@s.whatsapp.net", PushName: "Dina"}
+
+	if got := (Rule{}).Attribution(message); got != "*Dina* (+6281):" {
+		t.Errorf("default header = %q", got)
+	}
+	if got := (Rule{Header: "[{{account}}/{{chat}}] {{pushname}}"}).Attribution(message); got != "[default/120363025] Dina" {
+		t.Errorf("custom header = %q", got)
+	}
+
+	message.PushName = ""
+	if got := (Rule{Header: "{{pushname}}:"}).Attribution(message); got != "6281:" {
+		t.Errorf("header without push name = %q", got)
+	}
+}
@@ -0,0 +1,27 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+type IScheduleUsecase interface {
+	// CreateSchedule stores a send request to be sent at its send_at
+	CreateSchedule(ctx context.Context, request CreateRequest) (schedule Schedule, err error)
+	ListSchedules(ctx context.Context, filter Filter) (schedules []Schedule, err error)
+	GetSchedule(ctx context.Context, scheduleID string) (schedule Schedule, err error)
+	// UpdateSchedule edits a schedule that hasn't finished yet
+	UpdateSchedule(ctx context.Context, scheduleID string, request UpdateRequest) (schedule Schedule, err error)
+	CancelSchedule(ctx context.Context, scheduleID string) (schedule Schedule, err error)
+}
+
+type IScheduleRepository interface {
+	InitializeSchema() error
+	CreateSchedule(schedule *Schedule) error
+	GetSchedule(scheduleID string) (*Schedule, error)
+	ListSchedules(filter Filter) ([]Schedule, error)
+	UpdateSchedule(schedule *Schedule) error
+	// ListDue returns the scheduled schedules whose next run is at or before now, the oldest first. Runs waiting
+	// for their client are left out until their retry time.
+	ListDue(now time.Time, limit int) ([]Schedule, error)
+}
@@ -0,0 +1,148 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// MissedGrace is how late a run may start before it counts as missed, the gateway was down or busy
+const MissedGrace = time.Minute
+
+// Missed run policies
+const (
+	// MissedFire sends a missed message once as soon as the gateway is back
+	MissedFire = "fire"
+	// MissedSkip drops a missed message, a recurring schedule waits for its next occurrence
+	MissedSkip = "skip"
+)
+
+// Schedule statuses, a recurring schedule stays scheduled until its recurrence ends
+const (
+	StatusScheduled = "scheduled"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+// Schedule is a send request waiting for its time
+type Schedule struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id,omitempty"`
+	Type      string `json:"type"`
+	Phone     string `json:"phone"`
+	// Payload is the request of the send endpoint without account, phone and scheduling fields
+	Payload json.RawMessage `json:"payload"`
+	// SendAt is the first occurrence and the start of the recurrence, it keeps the offset it was given in
+	SendAt     time.Time `json:"send_at"`
+	Recurrence string    `json:"recurrence,omitempty"`
+	// MissedPolicy is empty to follow --schedule-missed-policy
+	MissedPolicy string `json:"missed_policy,omitempty"`
+	Status       string `json:"status"`
+	// NextRunAt is unset once the schedule is done
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	// RetryAt is when a due run that found its client offline is tried again
+	RetryAt       *time.Time `json:"retry_at,omitempty"`
+	Runs          int        `json:"runs"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastStatus    string     `json:"last_status,omitempty"`
+	LastMessageID string     `json:"last_message_id,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// NextRun returns the first occurrence after the given time, or at it when inclusive. A schedule without
+// recurrence only occurs at SendAt. ok is false when no occurrence is left.
+func (s Schedule) NextRun(after time.Time, inclusive bool) (next time.Time, ok bool, err error) {
+	if s.Recurrence == "" {
+		if s.SendAt.After(after) || (inclusive && s.SendAt.Equal(after)) {
+			return s.SendAt, true, nil
+		}
+		return next, false, nil
+	}
+
+	rule, err := ParseRecurrence(s.Recurrence, s.SendAt)
+	if err != nil {
+		return next, false, err
+	}
+	// Occurrences before SendAt don't exist, even if the rule would match them
+	if after.Before(s.SendAt) {
+		after, inclusive = s.SendAt, true
+	}
+	next = rule.After(after, inclusive)
+	return next, !next.IsZero(), nil
+}
+
+// Policy returns the missed run policy of the schedule, the default when it has none
+func (s Schedule) Policy(defaultPolicy string) string {
+	if s.MissedPolicy != "" {
+		return s.MissedPolicy
+	}
+	return defaultPolicy
+}
+
+// Missed reports whether a run due at the given time starts too late at now
+func Missed(due, now time.Time) bool {
+	return now.Sub(due) > MissedGrace
+}
+
+// ParseRecurrence parses an RRULE, with or without the "RRULE:" prefix, starting at start. Occurrences are
+// computed in the offset of start. Rules repeating more often than every minute are rejected.
+func ParseRecurrence(recurrence string, start time.Time) (*rrule.RRule, error) {
+	value := strings.TrimSpace(recurrence)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if strings.Contains(strings.ToUpper(value), "DTSTART") {
+		return nil, errors.New("DTSTART is taken from send_at")
+	}
+
+	option, err := rrule.StrToROption(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RRULE: %w", err)
+	}
+	if option.Freq == rrule.SECONDLY {
+		return nil, errors.New("FREQ=SECONDLY is not supported")
+	}
+	option.Dtstart = start
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RRULE: %w", err)
+	}
+	return rule, nil
+}
+
+// CreateRequest schedules a send request, built from a send endpoint call with send_at
+type CreateRequest struct {
+	AccountID    string          `json:"account_id"`
+	Type         string          `json:"type"`
+	Phone        string          `json:"phone"`
+	SendAt       string          `json:"send_at"`
+	Recurrence   string          `json:"recurrence"`
+	MissedPolicy string          `json:"missed_policy"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+// UpdateRequest edits a scheduled message, fields left out keep their value. An empty recurrence makes the
+// schedule a one-off, an empty missed_policy follows the default again.
+type UpdateRequest struct {
+	Phone        string          `json:"phone"`
+	SendAt       string          `json:"send_at"`
+	Recurrence   *string         `json:"recurrence"`
+	MissedPolicy *string         `json:"missed_policy"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+type Filter struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	Status    string `json:"status" query:"status"`
+	Phone     string `json:"phone" query:"phone"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNextRun(t *testing.T) {
+	jakarta := time.FixedZone("+07:00", 7*60*60)
+	// A Monday
+	sendAt := time.Date(2026, 10, 19, 9, 0, 0, 0, jakarta)
+
+	tests := []struct {
+		name       string
+		recurrence string
+		after      time.Time
+		inclusive  bool
+		want       time.Time
+		wantOK     bool
+	}{
+		{
+			name:      "one-off at send_at",
+			after:     sendAt,
+			inclusive: true,
+			want:      sendAt,
+			wantOK:    true,
+		},
+		{
+			name:   "one-off after send_at",
+			after:  sendAt,
+			wantOK: false,
+		},
+		{
+			name:       "weekly first occurrence",
+			recurrence: "FREQ=WEEKLY;BYDAY=MO",
+			after:      sendAt.Add(-48 * time.Hour),
+			want:       sendAt,
+			wantOK:     true,
+		},
+		{
+			name:       "weekly after a run",
+			recurrence: "RRULE:FREQ=WEEKLY;BYDAY=MO",
+			after:      sendAt.Add(time.Minute),
+			want:       sendAt.AddDate(0, 0, 7),
+			wantOK:     true,
+		},
+		{
+			name:       "count ended",
+			recurrence: "FREQ=DAILY;COUNT=2",
+			after:      sendAt.AddDate(0, 0, 1),
+			wantOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := Schedule{SendAt: sendAt, Recurrence: tt.recurrence}
+			got, ok, err := schedule.NextRun(tt.after, tt.inclusive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK || (ok && !got.Equal(tt.want)) {
+				t.Errorf("NextRun() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	for _, recurrence := range []string{"FREQ=SECONDLY", "FREQ=SOMETIMES", "DTSTART:20261019T090000Z\nRRULE:FREQ=DAILY"} {
+		if _, err := ParseRecurrence(recurrence, start); err == nil {
+			t.Errorf("ParseRecurrence(%q) should fail", recurrence)
+		}
+	}
+	if _, err := ParseRecurrence("FREQ=MONTHLY;BYMONTHDAY=1,15;UNTIL=20271231T000000Z", start); err != nil {
+		t.Errorf("ParseRecurrence() = %v", err)
+	}
+}
+
+func TestMissed(t *testing.T) {
+	due := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	if Missed(due, due.Add(30*time.Second)) {
+		t.Error("a run 30s late should not be missed")
+	}
+	if !Missed(due, due.Add(2*time.Hour)) {
+		t.Error("a run 2h late should be missed")
+	}
+}
@@ -0,0 +1,39 @@
+package script
+
+import (
+	"context"
+)
+
+type IScriptUsecase interface {
+	ListScripts(ctx context.Context) (scripts []Script, err error)
+	GetScript(ctx context.Context, scriptID string) (script Script, err error)
+	CreateScript(ctx context.Context, request ScriptRequest) (script Script, err error)
+	UpdateScript(ctx context.Context, scriptID string, request ScriptRequest) (script Script, err error)
+	DeleteScript(ctx context.Context, scriptID string) (err error)
+
+	// ListErrors returns the latest failed runs of a script, newest first
+	ListErrors(ctx context.Context, scriptID string, filter ErrorFilter) (runErrors []RunError, err error)
+	ListChatTags(ctx context.Context, filter ChatTagFilter) (tags []ChatTag, err error)
+}
+
+type IScriptRepository interface {
+	InitializeSchema() error
+	ListScripts() ([]Script, error)
+	GetScript(scriptID string) (*Script, error)
+	CreateScript(script *Script) error
+	UpdateScript(script *Script) error
+	DeleteScript(scriptID string) error
+
+	// RecordRun counts a run of the script, runErr is nil for successful runs and kept otherwise
+	RecordRun(scriptID string, runErr *RunError) error
+	ListErrors(scriptID string, filter ErrorFilter) ([]RunError, error)
+
+	AddChatTag(tag *ChatTag) error
+	RemoveChatTag(accountID, chatJID, tag string) error
+	ListChatTags(filter ChatTagFilter) ([]ChatTag, error)
+}
+
+// IRunner runs the scripts subscribed to an incoming event
+type IRunner interface {
+	Run(ctx context.Context, event Event)
+}
@@ -0,0 +1,253 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Actions is what scripts can do, every call acts on the chat of the event being handled
+type Actions interface {
+	Reply(ctx context.Context, text string) error
+	React(ctx context.Context, emoji string) error
+	Tag(ctx context.Context, tag string) error
+	Untag(ctx context.Context, tag string) error
+	// HTTP calls an allow-listed endpoint
+	HTTP(ctx context.Context, request HTTPRequest) (HTTPResponse, error)
+	// Log receives what the script prints
+	Log(message string)
+}
+
+type HTTPRequest struct {
+	Method  string
+	URL     string
+	Body    string
+	Headers map[string]string
+}
+
+type HTTPResponse struct {
+	Status int
+	Body   string
+}
+
+// Limits bound a single run of a script
+type Limits struct {
+	Timeout  time.Duration
+	MaxSteps uint64
+}
+
+// fileOptions allow loops since runs are bounded by steps and time, recursion stays disabled
+var fileOptions = &syntax.FileOptions{Set: true, While: true, TopLevelControl: true, GlobalReassign: true}
+
+// builtins are the names scripts can use besides the Starlark universe
+var builtins = map[string]bool{"reply": true, "react": true, "tag": true, "untag": true, "http": true, "json": true}
+
+// Program is a compiled script, it can run concurrently for several events
+type Program struct {
+	program *starlark.Program
+	defined map[string]bool
+}
+
+// Compile parses and resolves the source of a script without running it
+func Compile(name, source string) (*Program, error) {
+	file, program, err := starlark.SourceProgramOptions(fileOptions, name+".star", source, func(name string) bool {
+		return builtins[name]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defined := make(map[string]bool)
+	for _, stmt := range file.Stmts {
+		if def, ok := stmt.(*syntax.DefStmt); ok {
+			defined[def.Name.Name] = true
+		}
+	}
+	return &Program{program: program, defined: defined}, nil
+}
+
+// Defines reports whether the script defines a top-level function
+func (p *Program) Defines(function string) bool {
+	return p.defined[function]
+}
+
+// Run calls the handler of the event. Scripts without a handler for the event are skipped.
+// Every run starts from fresh globals, scripts cannot keep state between events.
+func (p *Program) Run(ctx context.Context, event Event, actions Actions, limits Limits) error {
+	handler := Handlers[event.Type]
+	if !p.Defines(handler) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+
+	thread := &starlark.Thread{
+		Name: handler,
+		Print: func(_ *starlark.Thread, message string) {
+			actions.Log(message)
+		},
+	}
+	if limits.MaxSteps > 0 {
+		thread.SetMaxExecutionSteps(limits.MaxSteps)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			thread.Cancel(fmt.Sprintf("timed out after %s", limits.Timeout))
+			return
+		}
+		thread.Cancel("canceled")
+	})
+	defer stop()
+
+	globals, err := p.program.Init(thread, predeclared(ctx, actions))
+	if err != nil {
+		return evalError(err)
+	}
+	function, ok := globals[handler].(starlark.Callable)
+	if !ok {
+		return fmt.Errorf("%s is not a function", handler)
+	}
+	if _, err = starlark.Call(thread, function, starlark.Tuple{eventValue(event)}, nil); err != nil {
+		return evalError(err)
+	}
+	return nil
+}
+
+// evalError keeps the Starlark backtrace so script errors point at the failing line
+func evalError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+func predeclared(ctx context.Context, actions Actions) starlark.StringDict {
+	budget := MaxActions
+	spend := func(name string) error {
+		if budget == 0 {
+			return fmt.Errorf("%s: only %d actions are allowed per event", name, MaxActions)
+		}
+		budget--
+		return nil
+	}
+
+	text := func(name string, call func(context.Context, string) error) *starlark.Builtin {
+		return starlark.NewBuiltin(name, func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var value string
+			if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &value); err != nil {
+				return nil, err
+			}
+			if err := spend(b.Name()); err != nil {
+				return nil, err
+			}
+			if err := call(ctx, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", b.Name(), err)
+			}
+			return starlark.None, nil
+		})
+	}
+
+	request := func(method string) *starlark.Builtin {
+		return starlark.NewBuiltin(method, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var (
+				url     string
+				body    string
+				payload starlark.Value
+				headers *starlark.Dict
+			)
+			if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &url, "body?", &body, "json?", &payload, "headers?", &headers); err != nil {
+				return nil, err
+			}
+			if err := spend("http." + b.Name()); err != nil {
+				return nil, err
+			}
+
+			request := HTTPRequest{Method: method, URL: url, Body: body, Headers: make(map[string]string)}
+			if headers != nil {
+				for _, item := range headers.Items() {
+					key, keyOK := starlark.AsString(item[0])
+					value, valueOK := starlark.AsString(item[1])
+					if !keyOK || !valueOK {
+						return nil, fmt.Errorf("http.%s: headers must map strings to strings", b.Name())
+					}
+					request.Headers[key] = value
+				}
+			}
+			if payload != nil && payload != starlark.None {
+				encoded, err := starlark.Call(thread, json.Module.Members["encode"], starlark.Tuple{payload}, nil)
+				if err != nil {
+					return nil, err
+				}
+				request.Body, _ = starlark.AsString(encoded)
+				request.Headers["Content-Type"] = "application/json"
+			}
+
+			response, err := actions.HTTP(ctx, request)
+			if err != nil {
+				return nil, fmt.Errorf("http.%s: %w", b.Name(), err)
+			}
+			return starlarkstruct.FromStringDict(starlark.String("response"), starlark.StringDict{
+				"status": starlark.MakeInt(response.Status),
+				"body":   starlark.String(response.Body),
+			}), nil
+		})
+	}
+
+	return starlark.StringDict{
+		"reply": text("reply", actions.Reply),
+		"react": text("react", actions.React),
+		"tag":   text("tag", actions.Tag),
+		"untag": text("untag", actions.Untag),
+		"http": &starlarkstruct.Module{Name: "http", Members: starlark.StringDict{
+			"get":  request("GET"),
+			"post": request("POST"),
+		}},
+		"json": json.Module,
+	}
+}
+
+// eventValue exposes the event to scripts as a struct, e.g. event.chat_jid or event.text
+func eventValue(event Event) starlark.Value {
+	fields := starlark.StringDict{
+		"type":       starlark.String(event.Type),
+		"account_id": starlark.String(event.AccountID),
+		"chat_jid":   starlark.String(event.ChatJID),
+		"sender_jid": starlark.String(event.SenderJID),
+		"message_id": starlark.String(event.MessageID),
+		"timestamp":  starlark.MakeInt64(event.Timestamp.Unix()),
+	}
+	for name, value := range event.Fields {
+		fields[name] = toValue(value)
+	}
+	return starlarkstruct.FromStringDict(starlark.String("event"), fields)
+}
+
+func toValue(value any) starlark.Value {
+	switch v := value.(type) {
+	case string:
+		return starlark.String(v)
+	case bool:
+		return starlark.Bool(v)
+	case int:
+		return starlark.MakeInt(v)
+	case int64:
+		return starlark.MakeInt64(v)
+	case []string:
+		list := make([]starlark.Value, len(v))
+		for i, item := range v {
+			list[i] = starlark.String(item)
+		}
+		return starlark.NewList(list)
+	case nil:
+		return starlark.None
+	}
+	return starlark.String(fmt.Sprint(value))
+}
@@ -0,0 +1,151 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordedActions struct {
+	calls    []string
+	requests []HTTPRequest
+}
+
+func (a *recordedActions) Reply(_ context.Context, text string) error {
+	a.calls = append(a.calls, "reply:"+text)
+	return nil
+}
+
+func (a *recordedActions) React(_ context.Context, emoji string) error {
+	a.calls = append(a.calls, "react:"+emoji)
+	return nil
+}
+
+func (a *recordedActions) Tag(_ context.Context, tag string) error {
+	a.calls = append(a.calls, "tag:"+tag)
+	return nil
+}
+
+func (a *recordedActions) Untag(_ context.Context, tag string) error {
+	return errors.New("not tagged")
+}
+
+func (a *recordedActions) HTTP(_ context.Context, request HTTPRequest) (HTTPResponse, error) {
+	a.requests = append(a.requests, request)
+	return HTTPResponse{Status: 200, Body: `{"plan": "gold"}`}, nil
+}
+
+func (a *recordedActions) Log(string) {}
+
+var limits = Limits{Timeout: time.Second, MaxSteps: 100000}
+
+func TestProgramRun(t *testing.T) {
+	program, err := Compile("vip", `
+def on_message(event):
+    if event.is_group or "price" not in event.text.lower():
+        return
+    customer = json.decode(http.post("https://crm.example.com/lookup", json={"jid": event.sender_jid}).body)
+    tag(customer["plan"])
+    react("👍")
+    reply("Hi %s, our %s prices are attached" % (event.push_name, customer["plan"]))
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := &recordedActions{}
+	event := Event{
+		Type:      EventMessage,
+		SenderJID: "628123@s.whatsapp.net",
+		Fields:    map[string]any{"text": "What is the PRICE?", "push_name": "Dina", "is_group": false},
+	}
+	if err := program.Run(context.Background(), event, actions, limits); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCalls := []string{"tag:gold", "react:👍", "reply:Hi Dina, our gold prices are attached"}
+	if !reflect.DeepEqual(actions.calls, wantCalls) {
+		t.Errorf("calls = %q, want %q", actions.calls, wantCalls)
+	}
+	if len(actions.requests) != 1 || actions.requests[0].Body != `{"jid":"628123@s.whatsapp.net"}` ||
+		actions.requests[0].Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected requests %+v", actions.requests)
+	}
+}
+
+func TestProgramRun_SkipsEventsWithoutHandler(t *testing.T) {
+	program, err := Compile("receipts", "def on_receipt(event):\n    fail('should not run')\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := program.Run(context.Background(), Event{Type: EventMessage}, &recordedActions{}, limits); err != nil {
+		t.Errorf("Run() = %v, want nil", err)
+	}
+}
+
+func TestProgramRun_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		limits  Limits
+		wantErr string
+	}{
+		{
+			name:    "steps",
+			source:  "def on_message(event):\n    while True:\n        pass\n",
+			limits:  Limits{Timeout: 10 * time.Second, MaxSteps: 1000},
+			wantErr: "too many steps",
+		},
+		{
+			name:    "timeout",
+			source:  "def on_message(event):\n    while True:\n        pass\n",
+			limits:  Limits{Timeout: 50 * time.Millisecond},
+			wantErr: "timed out after 50ms",
+		},
+		{
+			name:    "actions",
+			source:  "def on_message(event):\n    for i in range(20):\n        tag(str(i))\n",
+			limits:  limits,
+			wantErr: "only 10 actions are allowed per event",
+		},
+		{
+			name:    "failing action",
+			source:  "def on_message(event):\n    untag('vip')\n",
+			limits:  limits,
+			wantErr: "untag: not tagged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Compile(tt.name, tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = program.Run(context.Background(), Event{Type: EventMessage}, &recordedActions{}, tt.limits)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if _, err := Compile("syntax", "def on_message(event)\n    pass\n"); err == nil {
+		t.Error("expected a syntax error")
+	}
+	if _, err := Compile("unknown", "def on_message(event):\n    send_email(event.text)\n"); err == nil {
+		t.Error("expected undefined names to be rejected")
+	}
+
+	program, err := Compile("handlers", "def on_group(event):\n    pass\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !program.Defines("on_group") || program.Defines("on_message") {
+		t.Error("unexpected defined handlers")
+	}
+}
@@ -0,0 +1,121 @@
+package script
+
+import (
+	"time"
+)
+
+// Events a script can subscribe to
+const (
+	EventMessage = "message"
+	EventReceipt = "receipt"
+	EventGroup   = "group"
+)
+
+// Handlers maps every event to the function a script defines to handle it
+var Handlers = map[string]string{
+	EventMessage: "on_message",
+	EventReceipt: "on_receipt",
+	EventGroup:   "on_group",
+}
+
+const (
+	// MaxTimeout is the longest a script may run for one event
+	MaxTimeout = 30 * time.Second
+	// MaxActions is how many replies, reactions and HTTP calls a script may make for one event
+	MaxActions = 10
+	// MaxErrorsKept is how many errors are kept per script, older ones are dropped
+	MaxErrorsKept = 100
+)
+
+// Script is a Starlark program run on the events of an account
+type Script struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	AccountID   string     `json:"account_id"`
+	Events      []string   `json:"events"`
+	Source      string     `json:"source"`
+	Enabled     bool       `json:"enabled"`
+	TimeoutMs   int        `json:"timeout_ms"`
+	Runs        int64      `json:"runs"`
+	Failures    int64      `json:"failures"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Timeout returns how long the script may run for one event, fallback is used when it has no own timeout
+func (s Script) Timeout(fallback time.Duration) time.Duration {
+	if s.TimeoutMs <= 0 {
+		return fallback
+	}
+	return time.Duration(s.TimeoutMs) * time.Millisecond
+}
+
+// Handles reports whether the script runs on the event
+func (s Script) Handles(event Event) bool {
+	if !s.Enabled || s.AccountID != event.AccountID {
+		return false
+	}
+	for _, subscribed := range s.Events {
+		if subscribed == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptRequest creates or updates a script. Enabled defaults to true.
+type ScriptRequest struct {
+	Name      string   `json:"name"`
+	AccountID string   `json:"account_id"`
+	Events    []string `json:"events"`
+	Source    string   `json:"source"`
+	Enabled   *bool    `json:"enabled"`
+	TimeoutMs int      `json:"timeout_ms"`
+}
+
+// Event is what a script handler receives, Fields holds the values of the event type:
+// message: text, push_name, is_group, media_type; receipt: message_ids, receipt_type;
+// group: join, leave, promote, demote, name, topic
+type Event struct {
+	Type      string         `json:"type"`
+	AccountID string         `json:"account_id"`
+	ChatJID   string         `json:"chat_jid"`
+	SenderJID string         `json:"sender_jid"`
+	MessageID string         `json:"message_id"`
+	Timestamp time.Time      `json:"timestamp"`
+	Fields    map[string]any `json:"fields"`
+}
+
+// RunError is a failed run of a script, kept to surface script bugs through the API
+type RunError struct {
+	ID        int64     `json:"id"`
+	ScriptID  string    `json:"script_id"`
+	EventType string    `json:"event_type"`
+	ChatJID   string    `json:"chat_jid"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ErrorFilter struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+}
+
+// ChatTag is a label a script put on a chat
+type ChatTag struct {
+	AccountID string    `json:"account_id"`
+	ChatJID   string    `json:"chat_jid"`
+	Tag       string    `json:"tag"`
+	ScriptID  string    `json:"script_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChatTagFilter struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	ChatJID   string `json:"chat_jid" query:"chat_jid"`
+	Tag       string `json:"tag" query:"tag"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,46 @@
+package send
+
+import "mime/multipart"
+
+// Album sizes WhatsApp groups into one album
+const (
+	MinAlbumItems = 2
+	MaxAlbumItems = 30
+)
+
+// AlbumRequest sends images and videos grouped as one album. In a multipart body Items is a JSON array in the items
+// field and the upload of item i is in the media_<i> field, e.g. media_0.
+type AlbumRequest struct {
+	BaseRequest
+	Items    []AlbumItem `json:"items" form:"-"`
+	Compress bool        `json:"compress" form:"compress"`
+}
+
+// AlbumItem is one image or video of an album, its media comes from any of the usual media sources
+type AlbumItem struct {
+	MediaInput
+	// Type is image or video
+	Type    string                `json:"type"`
+	Caption string                `json:"caption,omitempty"`
+	URL     *string               `json:"url,omitempty"`
+	File    *multipart.FileHeader `json:"-"`
+}
+
+// MediaSource returns the source the item is sent from
+func (item AlbumItem) MediaSource() MediaSource {
+	return newMediaSource(item.File, item.URL, item.MediaInput)
+}
+
+type AlbumResponse struct {
+	// MessageID is the ID of the album message the items are grouped under
+	MessageID string            `json:"message_id"`
+	Status    string            `json:"status"`
+	Items     []AlbumItemResult `json:"items"`
+}
+
+// AlbumItemResult is the outcome of one item in the order of the request, either MessageID or Error is set
+type AlbumItemResult struct {
+	Index     int    `json:"index"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
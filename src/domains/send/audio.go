@@ -4,6 +4,12 @@ import "mime/multipart"
 
 type AudioRequest struct {
 	BaseRequest
+	MediaInput
 	Audio    *multipart.FileHeader `json:"audio" form:"audio"`
 	AudioURL *string               `json:"audio_url" form:"audio_url"`
 }
+
+// MediaSource returns the source the audio is sent from
+func (request AudioRequest) MediaSource() MediaSource {
+	return newMediaSource(request.Audio, request.AudioURL, request.MediaInput)
+}
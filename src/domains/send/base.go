@@ -5,4 +5,22 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// ReplyMessageID quotes a message of the chat storage, media is quoted as media
+	ReplyMessageID *string `json:"reply_message_id,omitempty" form:"reply_message_id"`
+	// Mentions are phone numbers or JIDs mentioned besides the @numbers in the text or caption. MentionEveryone,
+	// or @everyone in the text, mentions every participant of the group the message is sent to
+	Mentions        []string `json:"mentions,omitempty" form:"mentions"`
+	MentionEveryone bool     `json:"mention_everyone,omitempty" form:"mention_everyone"`
+	// SendAt schedules the message instead of sending it right away, an RFC 3339 time
+	SendAt string `json:"send_at,omitempty" form:"send_at"`
+	// Recurrence repeats a scheduled message, an RRULE like FREQ=WEEKLY;BYDAY=MO starting at SendAt
+	Recurrence string `json:"recurrence,omitempty" form:"recurrence"`
+	// MissedPolicy is fire or skip, what to do when the gateway was down at SendAt
+	MissedPolicy string `json:"missed_policy,omitempty" form:"missed_policy"`
+	// TemplateID renders the message from a stored template instead of the message fields of the request,
+	// Variables can only be given in a JSON body
+	TemplateID       string         `json:"template_id,omitempty" form:"template_id"`
+	TemplateVersion  int            `json:"template_version,omitempty" form:"template_version"`
+	TemplateLanguage string         `json:"template_language,omitempty" form:"template_language"`
+	Variables        map[string]any `json:"variables,omitempty" form:"-"`
 }
@@ -4,6 +4,13 @@ import "mime/multipart"
 
 type FileRequest struct {
 	BaseRequest
+	MediaInput
 	File    *multipart.FileHeader `json:"file" form:"file"`
+	FileURL *string               `json:"file_url" form:"file_url"`
 	Caption string                `json:"caption" form:"caption"`
 }
+
+// MediaSource returns the source the file is sent from
+func (request FileRequest) MediaSource() MediaSource {
+	return newMediaSource(request.File, request.FileURL, request.MediaInput)
+}
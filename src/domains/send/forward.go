@@ -0,0 +1,27 @@
+package send
+
+// MaxForwardTargets is the largest number of chats a message can be forwarded to at once
+const MaxForwardTargets = 50
+
+// ForwardRequest forwards a message of the chat storage to one or more chats. Media is sent with its stored media
+// key and hashes, it is only downloaded and uploaded again for newsletters.
+type ForwardRequest struct {
+	AccountID string   `json:"account_id" form:"account_id"`
+	MessageID string   `json:"message_id" uri:"message_id"`
+	Phones    []string `json:"phones" form:"phones"`
+	Duration  *int     `json:"duration,omitempty" form:"duration"`
+}
+
+type ForwardResponse struct {
+	// MessageID is the ID of the forwarded message, the IDs of the sent copies are in Results
+	MessageID string          `json:"message_id"`
+	Status    string          `json:"status"`
+	Results   []ForwardResult `json:"results"`
+}
+
+// ForwardResult is the outcome of forwarding to one chat, either MessageID or Error is set
+type ForwardResult struct {
+	Phone     string `json:"phone"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
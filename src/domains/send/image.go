@@ -4,9 +4,15 @@ import "mime/multipart"
 
 type ImageRequest struct {
 	BaseRequest
+	MediaInput
 	Caption  string                `json:"caption" form:"caption"`
 	Image    *multipart.FileHeader `json:"image" form:"image"`
 	ImageURL *string               `json:"image_url" form:"image_url"`
 	ViewOnce bool                  `json:"view_once" form:"view_once"`
 	Compress bool                  `json:"compress"`
 }
+
+// MediaSource returns the source the image is sent from
+func (request ImageRequest) MediaSource() MediaSource {
+	return newMediaSource(request.Image, request.ImageURL, request.MediaInput)
+}
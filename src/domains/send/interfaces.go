@@ -16,6 +16,7 @@ type IMediaSender interface {
 	SendVideo(ctx context.Context, request VideoRequest) (response GenericResponse, err error)
 	SendAudio(ctx context.Context, request AudioRequest) (response GenericResponse, err error)
 	SendSticker(ctx context.Context, request StickerRequest) (response GenericResponse, err error)
+	SendAlbum(ctx context.Context, request AlbumRequest) (response AlbumResponse, err error)
 }
 
 // IInteractionSender handles interaction message sending operations
@@ -26,6 +27,16 @@ type IInteractionSender interface {
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
 }
 
+// IRelaySender handles re-sending messages received elsewhere
+type IRelaySender interface {
+	SendRelay(ctx context.Context, request RelayRequest) (response GenericResponse, err error)
+}
+
+// IForwardSender handles forwarding messages of the chat storage
+type IForwardSender interface {
+	ForwardMessage(ctx context.Context, request ForwardRequest) (response ForwardResponse, err error)
+}
+
 // IPresenceSender handles presence-related operations
 type IPresenceSender interface {
 	SendPresence(ctx context.Context, request PresenceRequest) (response GenericResponse, err error)
@@ -37,5 +48,7 @@ type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
+	IRelaySender
+	IForwardSender
 	IPresenceSender
 }
@@ -0,0 +1,84 @@
+package send
+
+import (
+	"encoding/base64"
+	"errors"
+	"mime/multipart"
+	"strings"
+)
+
+// MediaKind selects the size and MIME rules a resolved media source has to pass
+type MediaKind string
+
+const (
+	MediaImage    MediaKind = "image"
+	MediaVideo    MediaKind = "video"
+	MediaAudio    MediaKind = "audio"
+	MediaSticker  MediaKind = "sticker"
+	MediaDocument MediaKind = "document"
+)
+
+// MediaInput holds the media sources every media request accepts besides its own upload and URL fields
+type MediaInput struct {
+	// Base64 is a data URI like data:image/png;base64,iVBORw0KGgo...
+	Base64 string `json:"base64,omitempty" form:"base64"`
+	// FromMessageID sends the media of a stored message again, it is downloaded with the stored media key
+	FromMessageID string `json:"from_message_id,omitempty" form:"from_message_id"`
+}
+
+// MediaSource is where the media of a send comes from, exactly one of the fields is expected to be set
+type MediaSource struct {
+	File          *multipart.FileHeader
+	URL           string
+	Base64        string
+	FromMessageID string
+}
+
+func newMediaSource(file *multipart.FileHeader, url *string, input MediaInput) MediaSource {
+	source := MediaSource{File: file, Base64: input.Base64, FromMessageID: input.FromMessageID}
+	if url != nil {
+		source.URL = *url
+	}
+	return source
+}
+
+// Count returns how many sources are set
+func (source MediaSource) Count() (count int) {
+	for _, set := range []bool{source.File != nil, source.URL != "", source.Base64 != "", source.FromMessageID != ""} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// Media is a media source resolved to its content
+type Media struct {
+	Data     []byte
+	FileName string
+	MimeType string
+}
+
+// ParseDataURI decodes a base64 data URI into its MIME type and content
+func ParseDataURI(uri string) (mimeType string, data []byte, err error) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return "", nil, errors.New("must be a data URI like data:image/png;base64,...")
+	}
+	header, encoded, ok := strings.Cut(rest, ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return "", nil, errors.New("must be a base64 data URI like data:image/png;base64,...")
+	}
+	mimeType = strings.TrimSpace(strings.Split(header, ";")[0])
+	if mimeType == "" {
+		return "", nil, errors.New("must name the MIME type of the data")
+	}
+	data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", nil, errors.New("is not valid base64")
+	}
+	if len(data) == 0 {
+		return "", nil, errors.New("has no data")
+	}
+	return strings.ToLower(mimeType), data, nil
+}
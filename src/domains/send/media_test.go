@@ -0,0 +1,39 @@
+package send
+
+import "testing"
+
+func TestParseDataURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		wantMime string
+		wantData string
+		wantErr  bool
+	}{
+		{name: "image", uri: "data:image/png;base64,aGVsbG8=", wantMime: "image/png", wantData: "hello"},
+		{name: "parameters and upper case", uri: "data:Audio/OGG;codecs=opus;base64,aGVsbG8=", wantMime: "audio/ogg", wantData: "hello"},
+		{name: "raw base64", uri: "aGVsbG8=", wantErr: true},
+		{name: "not base64 encoded", uri: "data:text/plain,hello", wantErr: true},
+		{name: "no MIME type", uri: "data:;base64,aGVsbG8=", wantErr: true},
+		{name: "invalid base64", uri: "data:image/png;base64,***", wantErr: true},
+		{name: "empty data", uri: "data:image/png;base64,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, data, err := ParseDataURI(tt.uri)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseDataURI(%q) returned no error", tt.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mimeType != tt.wantMime || string(data) != tt.wantData {
+				t.Errorf("ParseDataURI() = %q, %q, want %q, %q", mimeType, data, tt.wantMime, tt.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package send
+
+// Media types of a relayed message
+const (
+	RelayMediaImage    = "image"
+	RelayMediaVideo    = "video"
+	RelayMediaAudio    = "audio"
+	RelayMediaDocument = "document"
+	RelayMediaSticker  = "sticker"
+)
+
+// RelayRequest sends again a message received in another chat or by another account. Media is uploaded again
+// with the client of the sending account, Text is the caption of media that has one.
+type RelayRequest struct {
+	BaseRequest
+	Text  string      `json:"text"`
+	Media *RelayMedia `json:"media,omitempty"`
+	// BeforeSend is called with the ID the message will be sent with, the message is not sent when it fails
+	BeforeSend func(messageID string) error `json:"-"`
+}
+
+type RelayMedia struct {
+	Type     string `json:"type"`
+	Data     []byte `json:"-"`
+	MimeType string `json:"mime_type"`
+	FileName string `json:"file_name"`
+}
@@ -1,6 +1,23 @@
 package send
 
+// Message types of send requests stored to be sent later, like bulk and scheduled sends. The stored payload is
+// the body of the matching /send endpoint.
+const (
+	TypeText     = "text"
+	TypeImage    = "image"
+	TypeVideo    = "video"
+	TypeAudio    = "audio"
+	TypeFile     = "file"
+	TypeSticker  = "sticker"
+	TypeContact  = "contact"
+	TypeLink     = "link"
+	TypeLocation = "location"
+	TypePoll     = "poll"
+)
+
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
+	// ScheduleID is set instead of MessageID when the message was scheduled
+	ScheduleID string `json:"schedule_id,omitempty"`
 }
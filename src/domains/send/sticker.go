@@ -4,6 +4,12 @@ import "mime/multipart"
 
 type StickerRequest struct {
 	BaseRequest
+	MediaInput
 	Sticker    *multipart.FileHeader `json:"sticker" form:"sticker"`
 	StickerURL *string               `json:"sticker_url" form:"sticker_url"`
-}
\ No newline at end of file
+}
+
+// MediaSource returns the source the sticker is sent from
+func (request StickerRequest) MediaSource() MediaSource {
+	return newMediaSource(request.Sticker, request.StickerURL, request.MediaInput)
+}
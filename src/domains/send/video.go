@@ -4,9 +4,15 @@ import "mime/multipart"
 
 type VideoRequest struct {
 	BaseRequest
+	MediaInput
 	Caption  string                `json:"caption" form:"caption"`
 	Video    *multipart.FileHeader `json:"video" form:"video"`
 	ViewOnce bool                  `json:"view_once" form:"view_once"`
 	Compress bool                  `json:"compress"`
 	VideoURL *string               `json:"video_url" form:"video_url"`
 }
+
+// MediaSource returns the source the video is sent from
+func (request VideoRequest) MediaSource() MediaSource {
+	return newMediaSource(request.Video, request.VideoURL, request.MediaInput)
+}
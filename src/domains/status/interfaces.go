@@ -0,0 +1,16 @@
+package status
+
+import "context"
+
+type IStatusUsecase interface {
+	// PostStatus posts a text, image or video status to the audience of the request
+	PostStatus(ctx context.Context, request PostRequest) (status Status, err error)
+	ListStatuses(ctx context.Context, filter Filter) (statuses []Status, err error)
+}
+
+type IStatusRepository interface {
+	InitializeSchema() error
+	CreateStatus(status *Status) error
+	// ListStatuses returns the posted statuses, the newest first
+	ListStatuses(filter Filter) ([]Status, error)
+}
@@ -0,0 +1,109 @@
+package status
+
+import (
+	"errors"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// Status types
+const (
+	TypeText  = "text"
+	TypeImage = "image"
+	TypeVideo = "video"
+)
+
+// Audiences a status is posted to
+const (
+	// AudienceContacts posts to the contacts allowed by the status privacy of the account
+	AudienceContacts = "contacts"
+	// AudienceList posts to the phones of the request only
+	AudienceList = "list"
+)
+
+// MaxAudience is how many phones a status can be posted to with the list audience
+const MaxAudience = 1024
+
+// Fonts of text statuses by name
+var Fonts = map[string]waE2E.ExtendedTextMessage_FontType{
+	"system":         waE2E.ExtendedTextMessage_SYSTEM,
+	"system_text":    waE2E.ExtendedTextMessage_SYSTEM_TEXT,
+	"fb_script":      waE2E.ExtendedTextMessage_FB_SCRIPT,
+	"system_bold":    waE2E.ExtendedTextMessage_SYSTEM_BOLD,
+	"morning_breeze": waE2E.ExtendedTextMessage_MORNINGBREEZE_REGULAR,
+	"calistoga":      waE2E.ExtendedTextMessage_CALISTOGA_REGULAR,
+	"exo2":           waE2E.ExtendedTextMessage_EXO2_EXTRABOLD,
+	"courier_prime":  waE2E.ExtendedTextMessage_COURIERPRIME_BOLD,
+}
+
+// ParseColor parses a #RRGGBB or #AARRGGBB color into the ARGB value WhatsApp expects, colors without alpha are opaque
+func ParseColor(color string) (uint32, error) {
+	hex, ok := strings.CutPrefix(color, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return 0, errors.New("must be a color like #RRGGBB or #AARRGGBB")
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, errors.New("must be a color like #RRGGBB or #AARRGGBB")
+	}
+	if len(hex) == 6 {
+		value |= 0xFF000000
+	}
+	return uint32(value), nil
+}
+
+// PostRequest posts a status. Text statuses take text with optional colors and font, image and video statuses
+// take the same media sources as /send/image and /send/video with an optional caption.
+type PostRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
+	Type      string `json:"type" form:"type"`
+	Text      string `json:"text" form:"text"`
+	// BackgroundColor and TextColor are #RRGGBB or #AARRGGBB colors
+	BackgroundColor string `json:"background_color" form:"background_color"`
+	TextColor       string `json:"text_color" form:"text_color"`
+	Font            string `json:"font" form:"font"`
+	domainSend.MediaInput
+	Caption  string                `json:"caption" form:"caption"`
+	Media    *multipart.FileHeader `json:"-" form:"media"`
+	MediaURL *string               `json:"media_url" form:"media_url"`
+	Compress bool                  `json:"compress" form:"compress"`
+	// Audience is contacts or list, Phones are the viewers of a list status
+	Audience string   `json:"audience" form:"audience"`
+	Phones   []string `json:"phones" form:"phones"`
+}
+
+// MediaSource returns the source the image or video of the status is sent from
+func (request PostRequest) MediaSource() domainSend.MediaSource {
+	source := domainSend.MediaSource{File: request.Media, Base64: request.Base64, FromMessageID: request.FromMessageID}
+	if request.MediaURL != nil {
+		source.URL = *request.MediaURL
+	}
+	return source
+}
+
+// Status is a posted status
+type Status struct {
+	ID              string    `json:"id"`
+	AccountID       string    `json:"account_id,omitempty"`
+	Type            string    `json:"type"`
+	Text            string    `json:"text,omitempty"`
+	Caption         string    `json:"caption,omitempty"`
+	BackgroundColor string    `json:"background_color,omitempty"`
+	TextColor       string    `json:"text_color,omitempty"`
+	Font            string    `json:"font,omitempty"`
+	Audience        string    `json:"audience"`
+	Phones          []string  `json:"phones,omitempty"`
+	PostedAt        time.Time `json:"posted_at"`
+}
+
+type Filter struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	Type      string `json:"type" query:"type"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,29 @@
+package template
+
+import "context"
+
+type ITemplateUsecase interface {
+	ListTemplates(ctx context.Context, filter Filter) (templates []Template, err error)
+	GetTemplate(ctx context.Context, templateID string) (template Template, err error)
+	CreateTemplate(ctx context.Context, request TemplateRequest) (template Template, err error)
+	// UpdateTemplate stores the request as the new version of the template
+	UpdateTemplate(ctx context.Context, templateID string, request TemplateRequest) (template Template, err error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+	ListVersions(ctx context.Context, templateID string) (versions []Version, err error)
+	// Render fills in a template, missing variables are reported by name
+	Render(ctx context.Context, request RenderRequest) (template Template, rendered Rendered, err error)
+}
+
+type ITemplateRepository interface {
+	InitializeSchema() error
+	// CreateTemplate stores the template with its first version
+	CreateTemplate(template *Template) error
+	// UpdateTemplate stores the content of the template as its version
+	UpdateTemplate(template *Template) error
+	GetTemplate(templateID string) (*Template, error)
+	// GetVersion returns the template with the content of one of its versions
+	GetVersion(templateID string, version int) (*Template, error)
+	ListTemplates(filter Filter) ([]Template, error)
+	ListVersions(templateID string) ([]Version, error)
+	DeleteTemplate(templateID string) error
+}
@@ -0,0 +1,190 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Rendered is a variant with its placeholders filled in
+type Rendered struct {
+	Language string   `json:"language"`
+	Text     string   `json:"text,omitempty"`
+	Caption  string   `json:"caption,omitempty"`
+	MediaURL string   `json:"media_url,omitempty"`
+	Question string   `json:"question,omitempty"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// MissingVariablesError names the variables a render was not given
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e MissingVariablesError) Error() string {
+	return "missing " + strings.Join(e.Names, ", ")
+}
+
+// fields returns the template fields of the variant with a name for error messages
+func (v Variant) fields() map[string]string {
+	fields := map[string]string{
+		"text":      v.Text,
+		"caption":   v.Caption,
+		"media_url": v.MediaURL,
+		"question":  v.Question,
+	}
+	for i, option := range v.Options {
+		fields[fmt.Sprintf("options.%d", i)] = option
+	}
+	return fields
+}
+
+// Check parses the fields of the variant, a syntax error is returned with the name of its field
+func (v Variant) Check() error {
+	for name, text := range v.fields() {
+		if _, err := parseField(name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Variables returns the sorted names of the variables the variant uses. Variables used inside range and with
+// blocks are not included, the dot is something else there.
+func (v Variant) Variables() ([]string, error) {
+	names := make(map[string]bool)
+	for name, text := range v.fields() {
+		tmpl, err := parseField(name, text)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl.Tree != nil {
+			collectNode(tmpl.Tree.Root, names)
+		}
+	}
+
+	variables := make([]string, 0, len(names))
+	for name := range names {
+		variables = append(variables, name)
+	}
+	slices.Sort(variables)
+	return variables, nil
+}
+
+// Render fills in the placeholders of the variant. Variables the variant uses but that are not given fail
+// with a MissingVariablesError naming all of them.
+func (v Variant) Render(variables map[string]any) (rendered Rendered, err error) {
+	used, err := v.Variables()
+	if err != nil {
+		return rendered, err
+	}
+	var missing []string
+	for _, name := range used {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return rendered, MissingVariablesError{Names: missing}
+	}
+	if variables == nil {
+		variables = map[string]any{}
+	}
+
+	rendered.Language = v.Language
+	for name, target := range map[string]*string{
+		"text":      &rendered.Text,
+		"caption":   &rendered.Caption,
+		"media_url": &rendered.MediaURL,
+		"question":  &rendered.Question,
+	} {
+		if *target, err = renderField(name, v.fields()[name], variables); err != nil {
+			return rendered, err
+		}
+	}
+	for i, option := range v.Options {
+		text, err := renderField(fmt.Sprintf("options.%d", i), option, variables)
+		if err != nil {
+			return rendered, err
+		}
+		rendered.Options = append(rendered.Options, text)
+	}
+	return rendered, nil
+}
+
+func parseField(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderField(name, text string, variables map[string]any) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := parseField(name, text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, variables); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+func collectNode(node parse.Node, names map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectNode(child, names)
+		}
+	case *parse.ActionNode:
+		collectPipe(n.Pipe, names)
+	case *parse.IfNode:
+		collectPipe(n.Pipe, names)
+		collectNode(n.List, names)
+		collectNode(n.ElseList, names)
+	case *parse.RangeNode:
+		collectPipe(n.Pipe, names)
+		collectNode(n.ElseList, names)
+	case *parse.WithNode:
+		collectPipe(n.Pipe, names)
+		collectNode(n.ElseList, names)
+	case *parse.TemplateNode:
+		collectPipe(n.Pipe, names)
+	}
+}
+
+func collectPipe(pipe *parse.PipeNode, names map[string]bool) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				names[a.Ident[0]] = true
+			case *parse.VariableNode:
+				// $.name refers to the variables from anywhere
+				if len(a.Ident) > 1 && a.Ident[0] == "$" {
+					names[a.Ident[1]] = true
+				}
+			case *parse.ChainNode:
+				if pipe, ok := a.Node.(*parse.PipeNode); ok {
+					collectPipe(pipe, names)
+				}
+			case *parse.PipeNode:
+				collectPipe(a, names)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package template
+
+import (
+	"strings"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+)
+
+// Types is the message types a template can render, they match the send endpoints that accept template_id
+var Types = []string{domainSend.TypeText, domainSend.TypeImage, domainSend.TypeVideo, domainSend.TypePoll}
+
+// Template is a stored message with placeholders, in one or more languages. Every edit stores a new version,
+// older versions can still be sent.
+type Template struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	DefaultLanguage string    `json:"default_language"`
+	Version         int       `json:"version"`
+	Variants        []Variant `json:"variants"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Variant is the template in one language. Which fields are used depends on the type of the template: text
+// for text, media_url and caption for image and video, question and options for poll. Every field is a Go
+// text/template, like "Hi {{.name}}".
+type Variant struct {
+	Language string   `json:"language"`
+	Text     string   `json:"text,omitempty"`
+	Caption  string   `json:"caption,omitempty"`
+	MediaURL string   `json:"media_url,omitempty"`
+	Question string   `json:"question,omitempty"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// Version is an earlier or the current content of a template
+type Version struct {
+	TemplateID      string    `json:"template_id"`
+	Version         int       `json:"version"`
+	DefaultLanguage string    `json:"default_language"`
+	Variants        []Variant `json:"variants"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Variant returns the variant of the language. A regional language like pt-BR falls back to pt, an unknown
+// or empty language to the default language.
+func (t Template) Variant(language string) (Variant, bool) {
+	candidates := []string{language}
+	if base, _, found := strings.Cut(language, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, t.DefaultLanguage)
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		for _, variant := range t.Variants {
+			if strings.EqualFold(variant.Language, candidate) {
+				return variant, true
+			}
+		}
+	}
+	return Variant{}, false
+}
+
+// TemplateRequest creates a template or stores a new version of it. DefaultLanguage defaults to the language
+// of the first variant.
+type TemplateRequest struct {
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	DefaultLanguage string    `json:"default_language"`
+	Variants        []Variant `json:"variants"`
+}
+
+// RenderRequest renders a template, Version 0 is the current version
+type RenderRequest struct {
+	TemplateID string         `json:"template_id"`
+	Version    int            `json:"version"`
+	Language   string         `json:"language"`
+	Variables  map[string]any `json:"variables"`
+}
+
+type Filter struct {
+	Type   string `json:"type" query:"type"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,80 @@
+package template
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var orderTemplate = Template{
+	Type:            "text",
+	DefaultLanguage: "en",
+	Variants: []Variant{
+		{Language: "en", Text: "Hi {{.name}}, order {{.order_id}} ships {{.date}}"},
+		{Language: "pt", Text: "Olá {{.name}}, o pedido {{.order_id}} será enviado em {{.date}}"},
+		{Language: "id", Text: "Halo {{.name}}{{range .items}}, {{.}}{{end}}"},
+	},
+}
+
+func TestTemplateVariant(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "pt", want: "pt"},
+		{language: "pt-BR", want: "pt"},
+		{language: "ID", want: "id"},
+		{language: "de", want: "en"},
+		{language: "", want: "en"},
+	}
+
+	for _, tt := range tests {
+		variant, ok := orderTemplate.Variant(tt.language)
+		if !ok || variant.Language != tt.want {
+			t.Errorf("Variant(%q) = %q, %v, want %q", tt.language, variant.Language, ok, tt.want)
+		}
+	}
+}
+
+func TestVariantRender(t *testing.T) {
+	variant, _ := orderTemplate.Variant("en")
+	rendered, err := variant.Render(map[string]any{"name": "Dina", "order_id": 1042, "date": "Monday"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.Text != "Hi Dina, order 1042 ships Monday" {
+		t.Errorf("Text = %q", rendered.Text)
+	}
+
+	_, err = variant.Render(map[string]any{"name": "Dina"})
+	var missing MissingVariablesError
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Names, []string{"date", "order_id"}) {
+		t.Errorf("Render() = %v, want missing date and order_id", err)
+	}
+
+	variant, _ = orderTemplate.Variant("id")
+	rendered, err = variant.Render(map[string]any{"name": "Budi", "items": []any{"kopi", "teh"}})
+	if err != nil || rendered.Text != "Halo Budi, kopi, teh" {
+		t.Errorf("Render() = %q, %v", rendered.Text, err)
+	}
+}
+
+func TestVariantVariables(t *testing.T) {
+	variant := Variant{
+		MediaURL: "https://cdn.example.com/{{.sku}}.jpg",
+		Caption:  `{{if .discount}}{{printf "%d%% off" .discount}} {{end}}{{with .note}}{{.}}{{end}}`,
+		Options:  []string{"{{.first}}", "{{$.second | printf \"%s!\"}}"},
+	}
+	got, err := variant.Variables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"discount", "first", "note", "second", "sku"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+
+	if err := (Variant{Text: "Hi {{.name"}).Check(); err == nil {
+		t.Error("expected a syntax error")
+	}
+}
@@ -0,0 +1,46 @@
+package webhook
+
+// Reply actions a webhook can answer with
+const (
+	ActionReply     = "reply"      // send a text message to the chat
+	ActionReact     = "react"      // react to the message that triggered the event
+	ActionSendMedia = "send_media" // send an image, video or audio by URL to the chat
+	ActionMarkRead  = "mark_read"  // mark the message that triggered the event as read
+)
+
+// Outcome of a reply action
+const (
+	ActionStatusSuccess = "success"
+	ActionStatusFailed  = "failed"
+	ActionStatusInvalid = "invalid"
+)
+
+// MaxReplyActions is the maximum number of actions executed from a single webhook response
+const MaxReplyActions = 10
+
+// ActionResponse is the body a webhook may answer with when reply actions are enabled
+type ActionResponse struct {
+	Actions []Action `json:"actions"`
+}
+
+// Action is executed against the chat of the event that was delivered
+type Action struct {
+	Type string `json:"type"`
+	// Text is the message of a reply action
+	Text string `json:"text,omitempty"`
+	// Quote replies to the message that triggered the event
+	Quote bool   `json:"quote,omitempty"`
+	Emoji string `json:"emoji,omitempty"`
+	// MediaType is "image", "video" or "audio"
+	MediaType string `json:"media_type,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+}
+
+// ActionResult is recorded in the delivery log for every executed action
+type ActionResult struct {
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsContentType = "application/cloudevents+json"
+	// CloudEventsBatchContentType is used when a batch of CloudEvents is delivered in one request
+	CloudEventsBatchContentType = "application/cloudevents-batch+json"
+	JSONContentType             = "application/json"
+
+	// cloudEventTypePrefix namespaces event types, e.g. "whatsapp.message.ack"
+	cloudEventTypePrefix = "whatsapp."
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON mode
+type CloudEvent struct {
+	SpecVersion     string  `json:"specversion"`
+	ID              string  `json:"id"`
+	Source          string  `json:"source"`
+	Type            string  `json:"type"`
+	Time            string  `json:"time"`
+	DataContentType string  `json:"datacontenttype"`
+	DataSchema      string  `json:"dataschema"`
+	Data            Payload `json:"data"`
+}
+
+// NewCloudEvent wraps a payload of an account in a CloudEvents envelope
+func NewCloudEvent(accountID string, payload Payload) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              uuid.NewString(),
+		Source:          "/whatsapp/" + accountID,
+		Type:            cloudEventTypePrefix + payload.EventType(),
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: JSONContentType,
+		DataSchema:      SchemaURL(payload.EventType()),
+		Data:            payload,
+	}
+}
+
+// ContentType returns the media type a body is delivered with
+func ContentType(body any) string {
+	switch v := body.(type) {
+	case CloudEvent:
+		return CloudEventsContentType
+	case []any:
+		if len(v) > 0 && ContentType(v[0]) == CloudEventsContentType {
+			return CloudEventsBatchContentType
+		}
+	}
+	return JSONContentType
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import "time"
+
+// Delivery statuses
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Delivery is the record of one event sent to one webhook URL
+type Delivery struct {
+	ID         string `json:"id"`
+	AccountID  string `json:"account_id"`
+	EventType  string `json:"event_type"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	// Actions are the reply actions returned by the webhook and their outcome
+	Actions []ActionResult `json:"actions,omitempty"`
+	// ActionError is set when the response body could not be parsed as reply actions or they were not run
+	ActionError string `json:"action_error,omitempty"`
+	// BatchID groups the records of events delivered together in one request, BatchIndex is the position of the
+	// event in the array
+	BatchID    string    `json:"batch_id,omitempty"`
+	BatchIndex int       `json:"batch_index"`
+	BatchSize  int       `json:"batch_size,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BatchResponse is the optional body a batch endpoint answers with to reject single events of a delivered batch
+type BatchResponse struct {
+	// Failed are the positions of the events in the batch that could not be processed
+	Failed []int `json:"failed"`
+}
+
+// DeliveryFilter narrows down the delivery log, empty fields match everything
+type DeliveryFilter struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	EventType string `json:"event_type" query:"event_type"`
+	BatchID   string `json:"batch_id" query:"batch_id"`
+	Status    string `json:"status" query:"status"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+type IWebhookUsecase interface {
+	ListRules(ctx context.Context) (rules []Rule, err error)
+	GetRule(ctx context.Context, ruleID string) (rule Rule, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (rule Rule, err error)
+	UpdateRule(ctx context.Context, ruleID string, request RuleRequest) (rule Rule, err error)
+	DeleteRule(ctx context.Context, ruleID string) (err error)
+	// TestRules evaluates the stored rules against a sample event without publishing anything
+	TestRules(ctx context.Context, subject Subject) (decision Decision, err error)
+
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) (deliveries []Delivery, err error)
+	GetDelivery(ctx context.Context, deliveryID string) (delivery Delivery, err error)
+}
+
+type IRuleRepository interface {
+	InitializeSchema() error
+	ListRules() ([]Rule, error)
+	GetRule(ruleID string) (*Rule, error)
+	CreateRule(rule *Rule) error
+	UpdateRule(rule *Rule) error
+	DeleteRule(ruleID string) error
+}
+
+type IDeliveryRepository interface {
+	InitializeSchema() error
+	CreateDelivery(delivery *Delivery) error
+	ListDeliveries(filter DeliveryFilter) ([]Delivery, error)
+	GetDelivery(deliveryID string) (*Delivery, error)
+	// DeleteDeliveriesBefore prunes the log and returns the number of removed records
+	DeleteDeliveriesBefore(before time.Time) (int64, error)
+}
+
+// IActionExecutor runs the reply actions of a webhook response against the chat of the delivered event
+type IActionExecutor interface {
+	ExecuteActions(ctx context.Context, subject Subject, actions []Action) []ActionResult
+}
@@ -0,0 +1,306 @@
+package webhook
+
+import "go.mau.fi/whatsmeow/proto/waE2E"
+
+// EventVersion is bumped whenever a payload changes in a way that is not backwards compatible. Version 2 sends
+// media as an object in every media mode, version 1 sent the local path as a string once downloaded.
+const EventVersion = "2"
+
+// Event types, used as the "event" field of payloads and in broker subjects/streams
+const (
+	EventMessage            = "message"
+	EventMessageRevoked     = "message.revoked"
+	EventMessageEdited      = "message.edited"
+	EventMessageAck         = "message.ack"
+	EventMessageDeleteForMe = "message.delete_for_me"
+	EventGroupParticipants  = "group.participants"
+	EventStatusViewed       = "status.viewed"
+	EventPollVote           = "poll.vote"
+
+	EventHistorySyncStarted   = "history_sync.started"
+	EventHistorySyncProgress  = "history_sync.progress"
+	EventHistorySyncCompleted = "history_sync.completed"
+
+	EventBulkStatus   = "bulk.status"
+	EventBulkProgress = "bulk.progress"
+)
+
+// Payload is the body of a webhook event
+type Payload interface {
+	EventType() string
+	AddTags(tags ...string)
+}
+
+// Meta holds the fields every payload starts with
+type Meta struct {
+	Event        string `json:"event" jsonschema:"required"`
+	EventVersion string `json:"event_version" jsonschema:"required"`
+	// Tags are added by webhook routing rules
+	Tags []string `json:"tags,omitempty"`
+}
+
+func NewMeta(event string) Meta {
+	return Meta{Event: event, EventVersion: EventVersion}
+}
+
+func (m Meta) EventType() string {
+	return m.Event
+}
+
+func (m *Meta) AddTags(tags ...string) {
+	m.Tags = append(m.Tags, tags...)
+}
+
+// MessagePayload is sent for incoming and outgoing messages, including revokes and edits. Like every payload it
+// names chats and users by their full JID in the *_jid fields, sender_id and chat_id are kept for existing receivers.
+type MessagePayload struct {
+	Meta
+	SenderJID string    `json:"sender_jid" jsonschema:"required,description=Full sender JID"`
+	ChatJID   string    `json:"chat_jid" jsonschema:"required,description=Full chat JID"`
+	SenderID  string    `json:"sender_id" jsonschema:"required,description=User part of the sender JID (legacy)"`
+	ChatID    string    `json:"chat_id" jsonschema:"required,description=User part of the chat JID (legacy)"`
+	From      string    `json:"from,omitempty" jsonschema:"description=Sender JID or 'sender JID in group JID'"`
+	FromLID   string    `json:"from_lid,omitempty"`
+	Message   *Message  `json:"message,omitempty"`
+	Pushname  string    `json:"pushname,omitempty"`
+	Reaction  *Reaction `json:"reaction,omitempty"`
+	ViewOnce  bool      `json:"view_once,omitempty"`
+	Forwarded bool      `json:"forwarded,omitempty"`
+	Timestamp string    `json:"timestamp,omitempty" jsonschema:"format=date-time"`
+	IsHistory bool      `json:"is_history,omitempty" jsonschema:"description=Backfilled by a history sync instead of received live"`
+
+	// Protocol messages
+	Action            string `json:"action,omitempty" jsonschema:"enum=message_revoked,enum=message_edited"`
+	RevokedMessageID  string `json:"revoked_message_id,omitempty"`
+	RevokedFromMe     *bool  `json:"revoked_from_me,omitempty"`
+	RevokedChat       string `json:"revoked_chat,omitempty"`
+	OriginalMessageID string `json:"original_message_id,omitempty"`
+	EditedText        string `json:"edited_text,omitempty"`
+
+	// Media
+	Audio    *Media `json:"audio,omitempty"`
+	Document *Media `json:"document,omitempty"`
+	Image    *Media `json:"image,omitempty"`
+	Sticker  *Media `json:"sticker,omitempty"`
+	Video    *Media `json:"video,omitempty"`
+
+	// Structured messages are forwarded as WhatsApp sends them
+	Contact      *waE2E.ContactMessage      `json:"contact,omitempty"`
+	List         *waE2E.ListMessage         `json:"list,omitempty"`
+	LiveLocation *waE2E.LiveLocationMessage `json:"live_location,omitempty"`
+	Location     *waE2E.LocationMessage     `json:"location,omitempty"`
+	Order        *waE2E.OrderMessage        `json:"order,omitempty"`
+}
+
+type Message struct {
+	Text          string `json:"text" jsonschema:"required"`
+	ID            string `json:"id" jsonschema:"required"`
+	RepliedID     string `json:"replied_id" jsonschema:"required"`
+	QuotedMessage string `json:"quoted_message" jsonschema:"required"`
+}
+
+type Reaction struct {
+	Message string `json:"message" jsonschema:"required"`
+	ID      string `json:"id" jsonschema:"required"`
+}
+
+// Webhook media modes, see config.WhatsappWebhookMediaMode
+const (
+	MediaModePath   = "path"   // local path only, for receivers on the same host
+	MediaModeInline = "inline" // base64 file content up to a size cap, larger files fall back to a signed URL
+	MediaModeURL    = "url"    // time-limited signed download URL served by the gateway
+)
+
+// Media is a downloaded attachment, or its remote details when auto download is disabled
+type Media struct {
+	MediaPath string `json:"media_path,omitempty" jsonschema:"description=Local path of the downloaded file"`
+	MimeType  string `json:"mime_type,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	URL       string `json:"url,omitempty" jsonschema:"description=Remote WhatsApp URL (encrypted), when auto download is disabled"`
+	Filename  string `json:"filename,omitempty"`
+	Size      int64  `json:"size,omitempty" jsonschema:"description=File size in bytes"`
+	SHA256    string `json:"sha256,omitempty" jsonschema:"description=Hex encoded SHA-256 of the decrypted file"`
+	// Data and DownloadURL depend on the webhook media mode
+	Data                 string `json:"data,omitempty" jsonschema:"description=Base64 encoded file (media mode inline)"`
+	DownloadURL          string `json:"download_url,omitempty" jsonschema:"description=Signed download URL (media mode url or inline above the size cap)"`
+	DownloadURLExpiresAt string `json:"download_url_expires_at,omitempty" jsonschema:"format=date-time"`
+}
+
+// ReceiptPayload is sent when a message is delivered or read
+type ReceiptPayload struct {
+	Meta
+	Timestamp string      `json:"timestamp" jsonschema:"required,format=date-time"`
+	Payload   ReceiptData `json:"payload" jsonschema:"required"`
+}
+
+type ReceiptData struct {
+	IDs                    []string `json:"ids,omitempty"`
+	ChatJID                string   `json:"chat_jid" jsonschema:"required,description=Full chat JID"`
+	SenderJID              string   `json:"sender_jid" jsonschema:"required,description=Full sender JID"`
+	ChatID                 string   `json:"chat_id" jsonschema:"required,description=Full chat JID (legacy)"`
+	SenderID               string   `json:"sender_id" jsonschema:"required,description=Full sender JID (legacy)"`
+	From                   string   `json:"from" jsonschema:"required"`
+	ReceiptType            string   `json:"receipt_type" jsonschema:"required"`
+	ReceiptTypeDescription string   `json:"receipt_type_description" jsonschema:"required"`
+}
+
+// StatusViewedPayload is sent when a contact views a status posted by the account
+type StatusViewedPayload struct {
+	Meta
+	Timestamp string           `json:"timestamp" jsonschema:"required,format=date-time"`
+	Payload   StatusViewedData `json:"payload" jsonschema:"required"`
+}
+
+type StatusViewedData struct {
+	IDs       []string `json:"ids" jsonschema:"required,description=IDs of the viewed statuses"`
+	ViewerJID string   `json:"viewer_jid" jsonschema:"required,description=Full JID of the viewer"`
+	// ViewType is played when the viewer played a video status
+	ViewType string `json:"view_type" jsonschema:"required,enum=read,enum=played"`
+}
+
+// PollVotePayload is sent when a voter changes their vote on a poll sent by the account
+type PollVotePayload struct {
+	Meta
+	Timestamp string       `json:"timestamp" jsonschema:"required,format=date-time"`
+	Payload   PollVoteData `json:"payload" jsonschema:"required"`
+}
+
+type PollVoteData struct {
+	PollID   string `json:"poll_id" jsonschema:"required,description=Message ID of the poll"`
+	ChatJID  string `json:"chat_jid" jsonschema:"required,description=Full chat JID"`
+	VoterJID string `json:"voter_jid" jsonschema:"required,description=Full JID of the voter"`
+	// Options are the options the voter selects now, empty when they took their vote back
+	Options []string `json:"options" jsonschema:"required"`
+}
+
+// GroupParticipantsPayload is sent when members join, leave, are promoted or demoted
+type GroupParticipantsPayload struct {
+	Meta
+	Timestamp string                `json:"timestamp" jsonschema:"required,format=date-time"`
+	Payload   GroupParticipantsData `json:"payload" jsonschema:"required"`
+}
+
+type GroupParticipantsData struct {
+	ChatJID string   `json:"chat_jid" jsonschema:"required,description=Full group JID"`
+	ChatID  string   `json:"chat_id" jsonschema:"required,description=Full group JID (legacy)"`
+	Type    string   `json:"type" jsonschema:"required,enum=join,enum=leave,enum=promote,enum=demote"`
+	JIDs    []string `json:"jids" jsonschema:"required"`
+}
+
+// HistorySyncPayload is sent when WhatsApp starts, continues and finishes backfilling chat history
+type HistorySyncPayload struct {
+	Meta
+	SyncType   string `json:"sync_type" jsonschema:"required,enum=initial_bootstrap,enum=recent"`
+	ChunkOrder uint32 `json:"chunk_order" jsonschema:"description=Position of the last received chunk"`
+	Progress   uint32 `json:"progress" jsonschema:"description=Percentage reported by WhatsApp"`
+	// Conversations and Messages count the last received chunk, the totals every chunk of the sync so far
+	Conversations      int    `json:"conversations"`
+	Messages           int    `json:"messages"`
+	TotalConversations int    `json:"total_conversations"`
+	TotalMessages      int    `json:"total_messages"`
+	Timestamp          string `json:"timestamp" jsonschema:"required,format=date-time"`
+}
+
+// BulkStatusPayload is sent when a bulk send job starts, pauses, resumes, completes or is cancelled
+type BulkStatusPayload struct {
+	Meta
+	JobID  string `json:"job_id" jsonschema:"required"`
+	Status string `json:"status" jsonschema:"required,enum=running,enum=paused,enum=completed,enum=cancelled"`
+	BulkCounts
+	Timestamp string `json:"timestamp" jsonschema:"required,format=date-time"`
+}
+
+// BulkProgressPayload is sent after the message to a recipient of a bulk send job was sent or failed
+type BulkProgressPayload struct {
+	Meta
+	JobID     string `json:"job_id" jsonschema:"required"`
+	Phone     string `json:"phone" jsonschema:"required,description=JID of the recipient"`
+	Status    string `json:"status" jsonschema:"required,enum=sent,enum=failed"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	BulkCounts
+	Timestamp string `json:"timestamp" jsonschema:"required,format=date-time"`
+}
+
+// BulkCounts are the recipients of a bulk send job by status
+type BulkCounts struct {
+	Total     int `json:"total"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+	Pending   int `json:"pending"`
+}
+
+// DeleteForMePayload is sent when a message is deleted for the current user
+type DeleteForMePayload struct {
+	Meta
+	Action           string `json:"action" jsonschema:"required,enum=event.delete_for_me"`
+	DeletedMessageID string `json:"deleted_message_id" jsonschema:"required"`
+	SenderJID        string `json:"sender_jid" jsonschema:"required,description=Full sender JID"`
+	SenderID         string `json:"sender_id" jsonschema:"required,description=User part of the sender JID (legacy)"`
+	From             string `json:"from,omitempty"`
+	Timestamp        string `json:"timestamp" jsonschema:"required,format=date-time"`
+	*DeletedMessage
+}
+
+// DeletedMessage is the stored copy of a deleted message, when it was still in chat storage
+type DeletedMessage struct {
+	ChatJID           string `json:"chat_jid" jsonschema:"description=Full chat JID"`
+	ChatID            string `json:"chat_id" jsonschema:"description=Full chat JID (legacy)"`
+	OriginalContent   string `json:"original_content"`
+	OriginalSender    string `json:"original_sender"`
+	OriginalTimestamp string `json:"original_timestamp" jsonschema:"format=date-time"`
+	WasFromMe         bool   `json:"was_from_me"`
+	OriginalMediaType string `json:"original_media_type,omitempty"`
+	OriginalFilename  string `json:"original_filename,omitempty"`
+}
+
+// MessageType classifies the message for routing rules
+func (p *MessagePayload) MessageType() string {
+	switch {
+	case p.Action == "message_revoked":
+		return MessageTypeRevoked
+	case p.Action == "message_edited":
+		return MessageTypeEdited
+	case p.Reaction != nil:
+		return MessageTypeReaction
+	case p.Image != nil:
+		return MessageTypeImage
+	case p.Video != nil:
+		return MessageTypeVideo
+	case p.Audio != nil:
+		return MessageTypeAudio
+	case p.Document != nil:
+		return MessageTypeDocument
+	case p.Sticker != nil:
+		return MessageTypeSticker
+	case p.Contact != nil:
+		return MessageTypeContact
+	case p.LiveLocation != nil:
+		return MessageTypeLiveLocation
+	case p.Location != nil:
+		return MessageTypeLocation
+	case p.List != nil:
+		return MessageTypeList
+	case p.Order != nil:
+		return MessageTypeOrder
+	default:
+		return MessageTypeText
+	}
+}
+
+// Content returns the text, caption or edited text of the message
+func (p *MessagePayload) Content() string {
+	switch {
+	case p.EditedText != "":
+		return p.EditedText
+	case p.Message != nil && p.Message.Text != "":
+		return p.Message.Text
+	}
+	for _, media := range []*Media{p.Image, p.Video, p.Document} {
+		if media != nil && media.Caption != "" {
+			return media.Caption
+		}
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Rule actions
+const (
+	RuleActionRoute = "route" // deliver to the rule endpoints instead of the configured webhooks
+	RuleActionDrop  = "drop"  // do not publish the event at all
+	RuleActionTag   = "tag"   // add tags to the payload
+)
+
+// Chat types a rule can be scoped to
+const (
+	ChatTypeGroup    = "group"
+	ChatTypePersonal = "personal"
+)
+
+// Message types a rule can match on, derived from the payload
+const (
+	MessageTypeText         = "text"
+	MessageTypeImage        = "image"
+	MessageTypeVideo        = "video"
+	MessageTypeAudio        = "audio"
+	MessageTypeDocument     = "document"
+	MessageTypeSticker      = "sticker"
+	MessageTypeContact      = "contact"
+	MessageTypeLocation     = "location"
+	MessageTypeLiveLocation = "live_location"
+	MessageTypeList         = "list"
+	MessageTypeOrder        = "order"
+	MessageTypeReaction     = "reaction"
+	MessageTypeRevoked      = "revoked"
+	MessageTypeEdited       = "edited"
+)
+
+// Rule decides where an event is delivered. Rules are evaluated in ascending priority order before an event is published.
+type Rule struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Priority int       `json:"priority"`
+	Enabled  bool      `json:"enabled"`
+	Match    RuleMatch `json:"match"`
+	Action   string    `json:"action"`
+	// Endpoints are the webhook URLs of a route action
+	Endpoints []string `json:"endpoints,omitempty"`
+	// Tags are added to the payload by a tag action
+	Tags []string `json:"tags,omitempty"`
+	// Final stops the evaluation of the remaining rules once this rule matched
+	Final     bool      `json:"final"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RuleMatch holds the conditions of a rule, empty conditions match everything
+type RuleMatch struct {
+	// AccountID is "default" for the single-device client
+	AccountID string `json:"account_id,omitempty"`
+	// ChatType is "group" or "personal"
+	ChatType string `json:"chat_type,omitempty"`
+	// ChatJID and Sender are glob patterns matched against the full JID or its user part, e.g. "1203630*@g.us"
+	ChatJID      string   `json:"chat_jid,omitempty"`
+	Sender       string   `json:"sender,omitempty"`
+	EventTypes   []string `json:"event_types,omitempty"`
+	MessageTypes []string `json:"message_types,omitempty"`
+	// ContentRegex is matched against the message text or caption
+	ContentRegex string `json:"content_regex,omitempty"`
+}
+
+// RuleRequest creates or replaces a rule
+type RuleRequest struct {
+	Name      string    `json:"name"`
+	Priority  int       `json:"priority"`
+	Enabled   *bool     `json:"enabled"`
+	Match     RuleMatch `json:"match"`
+	Action    string    `json:"action"`
+	Endpoints []string  `json:"endpoints"`
+	Tags      []string  `json:"tags"`
+	Final     bool      `json:"final"`
+}
+
+// Subject describes an event for rule evaluation
+type Subject struct {
+	AccountID   string `json:"account_id"`
+	EventType   string `json:"event_type"`
+	ChatJID     string `json:"chat_jid"`
+	SenderJID   string `json:"sender_jid"`
+	MessageType string `json:"message_type"`
+	Content     string `json:"content"`
+	// MessageID is the message that triggered the event, reply actions react to or quote it
+	MessageID string `json:"message_id,omitempty"`
+	// IsHistory marks messages backfilled by a history sync, reply actions are not run for them
+	IsHistory bool `json:"is_history,omitempty"`
+}
+
+func (s Subject) IsGroup() bool {
+	return strings.HasSuffix(s.ChatJID, "@g.us")
+}
+
+// Decision is the outcome of evaluating the rules for an event
+type Decision struct {
+	Drop bool `json:"drop"`
+	// Endpoints is empty when the event goes to the configured webhooks
+	Endpoints    []string `json:"endpoints"`
+	Tags         []string `json:"tags"`
+	MatchedRules []string `json:"matched_rules"`
+}
+
+// Routed reports whether a route rule replaced the configured webhooks
+func (d Decision) Routed() bool {
+	return len(d.Endpoints) > 0
+}
+
+// RuleSet is a compiled, ordered set of enabled rules
+type RuleSet struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	content *regexp.Regexp
+}
+
+// NewRuleSet compiles the enabled rules, sorted by priority
+func NewRuleSet(rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		compiled := compiledRule{Rule: rule}
+		if rule.Match.ContentRegex != "" {
+			re, err := regexp.Compile(rule.Match.ContentRegex)
+			if err != nil {
+				return nil, err
+			}
+			compiled.content = re
+		}
+		set.rules = append(set.rules, compiled)
+	}
+	slices.SortStableFunc(set.rules, func(a, b compiledRule) int {
+		return a.Priority - b.Priority
+	})
+	return set, nil
+}
+
+// Len returns the number of enabled rules
+func (s *RuleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+// Evaluate applies every matching rule in priority order. A drop rule ends the evaluation.
+func (s *RuleSet) Evaluate(subject Subject) Decision {
+	var decision Decision
+	if s == nil {
+		return decision
+	}
+
+	for _, rule := range s.rules {
+		if !rule.matches(subject) {
+			continue
+		}
+		decision.MatchedRules = append(decision.MatchedRules, rule.ID)
+
+		switch rule.Action {
+		case RuleActionDrop:
+			decision.Drop = true
+			return decision
+		case RuleActionRoute:
+			for _, endpoint := range rule.Endpoints {
+				if !slices.Contains(decision.Endpoints, endpoint) {
+					decision.Endpoints = append(decision.Endpoints, endpoint)
+				}
+			}
+		case RuleActionTag:
+			for _, tag := range rule.Tags {
+				if !slices.Contains(decision.Tags, tag) {
+					decision.Tags = append(decision.Tags, tag)
+				}
+			}
+		}
+
+		if rule.Final {
+			break
+		}
+	}
+	return decision
+}
+
+func (r compiledRule) matches(subject Subject) bool {
+	m := r.Match
+	if m.AccountID != "" && m.AccountID != subject.AccountID {
+		return false
+	}
+	switch m.ChatType {
+	case ChatTypeGroup:
+		if !subject.IsGroup() {
+			return false
+		}
+	case ChatTypePersonal:
+		if subject.IsGroup() {
+			return false
+		}
+	}
+	if !matchJID(m.ChatJID, subject.ChatJID) || !matchJID(m.Sender, subject.SenderJID) {
+		return false
+	}
+	if len(m.EventTypes) > 0 && !slices.Contains(m.EventTypes, subject.EventType) {
+		return false
+	}
+	if len(m.MessageTypes) > 0 && !slices.Contains(m.MessageTypes, subject.MessageType) {
+		return false
+	}
+	if r.content != nil && !r.content.MatchString(subject.Content) {
+		return false
+	}
+	return true
+}
+
+// matchJID matches a glob pattern against a JID or its user part
+func matchJID(pattern, jid string) bool {
+	if pattern == "" {
+		return true
+	}
+	if ok, _ := path.Match(pattern, jid); ok {
+		return true
+	}
+	user, _, _ := strings.Cut(jid, "@")
+	ok, _ := path.Match(pattern, user)
+	return ok
+}
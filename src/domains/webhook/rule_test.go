@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRuleSetEvaluate(t *testing.T) {
+	rules := []Rule{
+		{ID: "disabled", Enabled: false, Action: RuleActionDrop},
+		{ID: "sales-dm", Enabled: true, Priority: 10, Action: RuleActionRoute, Endpoints: []string{"https://sales"}, Match: RuleMatch{AccountID: "sales", ChatType: ChatTypePersonal}},
+		{ID: "images", Enabled: true, Priority: 20, Action: RuleActionTag, Tags: []string{"media"}, Match: RuleMatch{MessageTypes: []string{MessageTypeImage}}},
+		{ID: "group", Enabled: true, Priority: 5, Action: RuleActionRoute, Endpoints: []string{"https://ops"}, Final: true, Match: RuleMatch{ChatJID: "120363*"}},
+		{ID: "receipts", Enabled: true, Priority: 0, Action: RuleActionDrop, Match: RuleMatch{EventTypes: []string{EventMessageAck}}},
+	}
+	set, err := NewRuleSet(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Len() != 4 {
+		t.Fatalf("expected disabled rules to be skipped, got %d rules", set.Len())
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    Decision
+	}{
+		{
+			name:    "no match keeps the configured webhooks",
+			subject: Subject{AccountID: "default", EventType: EventMessage, ChatJID: "6281@s.whatsapp.net"},
+			want:    Decision{},
+		},
+		{
+			name:    "drop ends the evaluation",
+			subject: Subject{AccountID: "sales", EventType: EventMessageAck, ChatJID: "6281@s.whatsapp.net"},
+			want:    Decision{Drop: true, MatchedRules: []string{"receipts"}},
+		},
+		{
+			name:    "final stops lower priority rules",
+			subject: Subject{AccountID: "default", EventType: EventMessage, ChatJID: "120363025@g.us", MessageType: MessageTypeImage},
+			want:    Decision{Endpoints: []string{"https://ops"}, MatchedRules: []string{"group"}},
+		},
+		{
+			name:    "route and tag combine",
+			subject: Subject{AccountID: "sales", EventType: EventMessage, ChatJID: "6281@s.whatsapp.net", MessageType: MessageTypeImage},
+			want:    Decision{Endpoints: []string{"https://sales"}, Tags: []string{"media"}, MatchedRules: []string{"sales-dm", "images"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := set.Evaluate(tt.subject)
+			if got.Drop != tt.want.Drop || !slices.Equal(got.Endpoints, tt.want.Endpoints) ||
+				!slices.Equal(got.Tags, tt.want.Tags) || !slices.Equal(got.MatchedRules, tt.want.MatchedRules) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchJID(t *testing.T) {
+	tests := []struct {
+		pattern, jid string
+		want         bool
+	}{
+		{"", "6281@s.whatsapp.net", true},
+		{"*@g.us", "120363@g.us", true},
+		{"*@g.us", "6281@s.whatsapp.net", false},
+		{"6281", "6281@s.whatsapp.net", true},
+		{"62812*", "6281@s.whatsapp.net", false},
+	}
+	for _, tt := range tests {
+		if got := matchJID(tt.pattern, tt.jid); got != tt.want {
+			t.Errorf("matchJID(%q, %q) = %v, want %v", tt.pattern, tt.jid, got, tt.want)
+		}
+	}
+}
+
+func TestNewRuleSetRejectsInvalidRegex(t *testing.T) {
+	if _, err := NewRuleSet([]Rule{{ID: "bad", Enabled: true, Match: RuleMatch{ContentRegex: "("}}}); err == nil {
+		t.Fatal("expected an error for an invalid content regex")
+	}
+}
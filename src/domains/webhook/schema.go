@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/invopop/jsonschema"
+	"google.golang.org/protobuf/proto"
+)
+
+// SchemaBaseURL is where the generated schemas are published (docs/schemas in the repository)
+const SchemaBaseURL = "https://raw.githubusercontent.com/aldinokemal/go-whatsapp-web-multidevice/main/docs/schemas/"
+
+// payloadTypes maps every event type to the payload it is sent with
+var payloadTypes = map[string]Payload{
+	EventMessage:            &MessagePayload{},
+	EventMessageRevoked:     &MessagePayload{},
+	EventMessageEdited:      &MessagePayload{},
+	EventMessageAck:         &ReceiptPayload{},
+	EventMessageDeleteForMe: &DeleteForMePayload{},
+	EventGroupParticipants:  &GroupParticipantsPayload{},
+	EventStatusViewed:       &StatusViewedPayload{},
+	EventPollVote:           &PollVotePayload{},
+
+	EventHistorySyncStarted:   &HistorySyncPayload{},
+	EventHistorySyncProgress:  &HistorySyncPayload{},
+	EventHistorySyncCompleted: &HistorySyncPayload{},
+
+	EventBulkStatus:   &BulkStatusPayload{},
+	EventBulkProgress: &BulkProgressPayload{},
+}
+
+// EventTypes returns every event type with a published schema, sorted
+func EventTypes() []string {
+	types := make([]string, 0, len(payloadTypes))
+	for eventType := range payloadTypes {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SchemaURL returns the public URL of the JSON Schema of an event type
+func SchemaURL(eventType string) string {
+	return SchemaBaseURL + eventType + ".json"
+}
+
+// Schema generates the JSON Schema of an event type
+func Schema(eventType string) (*jsonschema.Schema, error) {
+	payload, ok := payloadTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	reflector := &jsonschema.Reflector{
+		DoNotReference:             true,
+		RequiredFromJSONSchemaTags: true,
+		// Payloads only grow within a version, consumers must ignore fields they do not know
+		AllowAdditionalProperties: true,
+		Mapper:                    mapProtoMessage,
+	}
+	schema := reflector.Reflect(payload)
+	schema.ID = jsonschema.ID(SchemaURL(eventType))
+	schema.Title = eventType
+	schema.Description = fmt.Sprintf("Webhook payload of the %s event, version %s", eventType, EventVersion)
+
+	// Pin the event name so consumers can validate which event they received
+	if event, ok := schema.Properties.Get("event"); ok {
+		event.Const = eventType
+	}
+	if version, ok := schema.Properties.Get("event_version"); ok {
+		version.Const = EventVersion
+	}
+	return schema, nil
+}
+
+// MarshalSchema returns the indented JSON Schema of an event type, as committed in docs/schemas
+func MarshalSchema(eventType string) ([]byte, error) {
+	schema, err := Schema(eventType)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// mapProtoMessage describes WhatsApp protobuf messages as plain objects, they are forwarded as received
+func mapProtoMessage(t reflect.Type) *jsonschema.Schema {
+	if !t.Implements(protoMessageType) && !reflect.PointerTo(t).Implements(protoMessageType) {
+		return nil
+	}
+	return &jsonschema.Schema{Type: "object"}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// schemaDir holds the published schemas, regenerate them with UPDATE_SCHEMAS=1 go test ./domains/webhook/
+var schemaDir = filepath.Join("..", "..", "..", "docs", "schemas")
+
+func TestSchemasAreUpToDate(t *testing.T) {
+	update := os.Getenv("UPDATE_SCHEMAS") != ""
+	if update {
+		if err := os.MkdirAll(schemaDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, eventType := range EventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			want, err := MarshalSchema(eventType)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(schemaDir, eventType+".json")
+			if update {
+				if err := os.WriteFile(path, want, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("missing schema, run UPDATE_SCHEMAS=1 go test ./domains/webhook/: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s is outdated, run UPDATE_SCHEMAS=1 go test ./domains/webhook/", path)
+			}
+		})
+	}
+}
+
+func TestSchemaPinsEventAndVersion(t *testing.T) {
+	schema, err := Schema(EventMessageRevoked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, _ := schema.Properties.Get("event")
+	version, _ := schema.Properties.Get("event_version")
+	if event.Const != EventMessageRevoked || version.Const != EventVersion {
+		t.Errorf("event const = %v, version const = %v", event.Const, version.Const)
+	}
+
+	if _, err := Schema("unknown"); err == nil {
+		t.Error("expected an error for an unknown event type")
+	}
+}
+
+func TestMessagePayloadJSON(t *testing.T) {
+	payload := MessagePayload{
+		Meta:      NewMeta(EventMessage),
+		SenderJID: "628123@s.whatsapp.net",
+		ChatJID:   "628123@s.whatsapp.net",
+		SenderID:  "628123",
+		ChatID:    "628123",
+		Image:     &Media{URL: "https://mmg.whatsapp.net/x", Caption: "hi"},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["event"] != EventMessage || got["event_version"] != EventVersion {
+		t.Errorf("missing event metadata: %s", body)
+	}
+	if got["sender_jid"] != "628123@s.whatsapp.net" || got["chat_jid"] != "628123@s.whatsapp.net" {
+		t.Errorf("missing full JIDs: %s", body)
+	}
+	if _, ok := got["revoked_from_me"]; ok {
+		t.Errorf("unset protocol fields must be omitted: %s", body)
+	}
+	image, ok := got["image"].(map[string]any)
+	if !ok || image["url"] != "https://mmg.whatsapp.net/x" || image["caption"] != "hi" {
+		t.Errorf("unexpected image %v", got["image"])
+	}
+}
@@ -0,0 +1,436 @@
+package autoreply
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultMatchLimit = 50
+
+// Repository stores auto-reply rules, the log of their matches, business hours and the contacts that wrote,
+// keywords, hours and holidays are kept as JSON
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainAutoReply.IAutoReplyRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS autoreply_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL DEFAULT '',
+			trigger_type TEXT NOT NULL,
+			keywords_json TEXT NOT NULL DEFAULT '[]',
+			pattern TEXT NOT NULL DEFAULT '',
+			case_sensitive BOOLEAN NOT NULL DEFAULT 0,
+			reply TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			media_url TEXT NOT NULL DEFAULT '',
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS autoreply_matches (
+			id TEXT PRIMARY KEY,
+			rule_id TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL DEFAULT '',
+			reply_message_id TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_autoreply_matches_rule_id ON autoreply_matches(rule_id, created_at);
+		CREATE TABLE IF NOT EXISTS autoreply_schedules (
+			account_id TEXT PRIMARY KEY,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			time_zone TEXT NOT NULL DEFAULT '',
+			hours_json TEXT NOT NULL DEFAULT '[]',
+			holidays_json TEXT NOT NULL DEFAULT '[]',
+			away_message TEXT NOT NULL DEFAULT '',
+			greeting_message TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS autoreply_contacts (
+			account_id TEXT NOT NULL,
+			contact_jid TEXT NOT NULL,
+			first_seen_at DATETIME NOT NULL,
+			away_window INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (account_id, contact_jid)
+		);`)
+	if err != nil {
+		return fmt.Errorf("failed to create auto-reply tables: %w", err)
+	}
+	return nil
+}
+
+const ruleColumns = `id, name, priority, enabled, account_id, chat_jid, trigger_type, keywords_json, pattern, case_sensitive,
+	reply, media_type, media_url, cooldown_seconds, created_at, updated_at`
+
+func (r *Repository) ListRules() ([]domainAutoReply.Rule, error) {
+	rows, err := r.db.Query(`SELECT ` + ruleColumns + ` FROM autoreply_rules ORDER BY priority, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auto-reply rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]domainAutoReply.Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) GetRule(ruleID string) (*domainAutoReply.Rule, error) {
+	row := r.db.QueryRow(`SELECT `+ruleColumns+` FROM autoreply_rules WHERE id = ?`, ruleID)
+
+	rule, err := scanRule(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("auto-reply rule %s not found", ruleID))
+	}
+	return rule, err
+}
+
+func (r *Repository) CreateRule(rule *domainAutoReply.Rule) error {
+	keywords, err := json.Marshal(rule.Keywords)
+	if err != nil {
+		return fmt.Errorf("failed to encode auto-reply keywords: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO autoreply_rules (`+ruleColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID, rule.Name, rule.Priority, rule.Enabled, rule.AccountID, rule.ChatJID, rule.TriggerType, string(keywords),
+		rule.Pattern, rule.CaseSensitive, rule.Reply, rule.MediaType, rule.MediaURL, rule.CooldownSeconds,
+		rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create auto-reply rule: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateRule(rule *domainAutoReply.Rule) error {
+	keywords, err := json.Marshal(rule.Keywords)
+	if err != nil {
+		return fmt.Errorf("failed to encode auto-reply keywords: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE autoreply_rules
+		SET name = ?, priority = ?, enabled = ?, account_id = ?, chat_jid = ?, trigger_type = ?, keywords_json = ?,
+			pattern = ?, case_sensitive = ?, reply = ?, media_type = ?, media_url = ?, cooldown_seconds = ?, updated_at = ?
+		WHERE id = ?`,
+		rule.Name, rule.Priority, rule.Enabled, rule.AccountID, rule.ChatJID, rule.TriggerType, string(keywords),
+		rule.Pattern, rule.CaseSensitive, rule.Reply, rule.MediaType, rule.MediaURL, rule.CooldownSeconds,
+		rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update auto-reply rule: %w", err)
+	}
+	return requireAffected(result, rule.ID)
+}
+
+// DeleteRule removes the rule, its recorded matches are kept for the statistics
+func (r *Repository) DeleteRule(ruleID string) error {
+	result, err := r.db.Exec(`DELETE FROM autoreply_rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete auto-reply rule: %w", err)
+	}
+	return requireAffected(result, ruleID)
+}
+
+func (r *Repository) CreateMatch(match *domainAutoReply.Match) error {
+	_, err := r.db.Exec(`
+		INSERT INTO autoreply_matches (id, rule_id, account_id, chat_jid, sender_jid, message_id, reply_message_id, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		match.ID, match.RuleID, match.AccountID, match.ChatJID, match.SenderJID, match.MessageID,
+		match.ReplyMessageID, match.Status, match.Error, match.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record auto-reply match: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListMatches(filter domainAutoReply.MatchFilter) ([]domainAutoReply.Match, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.RuleID != "" {
+		conditions = append(conditions, "rule_id = ?")
+		args = append(args, filter.RuleID)
+	}
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := `
+		SELECT id, rule_id, account_id, chat_jid, sender_jid, message_id, reply_message_id, status, error, created_at
+		FROM autoreply_matches`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultMatchLimit
+	}
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auto-reply matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]domainAutoReply.Match, 0)
+	for rows.Next() {
+		var match domainAutoReply.Match
+		err := rows.Scan(
+			&match.ID, &match.RuleID, &match.AccountID, &match.ChatJID, &match.SenderJID, &match.MessageID,
+			&match.ReplyMessageID, &match.Status, &match.Error, &match.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auto-reply match: %w", err)
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+func (r *Repository) Stats() ([]domainAutoReply.RuleStats, error) {
+	rows, err := r.db.Query(`
+		SELECT rule_id, COUNT(*),
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END),
+			MAX(created_at)
+		FROM autoreply_matches GROUP BY rule_id ORDER BY rule_id`,
+		domainAutoReply.MatchStatusSent, domainAutoReply.MatchStatusFailed, domainAutoReply.MatchStatusCooldown,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auto-reply matches: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]domainAutoReply.RuleStats, 0)
+	for rows.Next() {
+		var (
+			stat        domainAutoReply.RuleStats
+			lastMatched string
+		)
+		if err := rows.Scan(&stat.RuleID, &stat.Matches, &stat.Sent, &stat.Failed, &stat.Cooldown, &lastMatched); err != nil {
+			return nil, fmt.Errorf("failed to scan auto-reply stats: %w", err)
+		}
+		// MAX() loses the column type, so the timestamp comes back as text
+		if parsed, err := parseTimestamp(lastMatched); err == nil {
+			stat.LastMatchedAt = &parsed
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+const scheduleColumns = `account_id, enabled, time_zone, hours_json, holidays_json, away_message, greeting_message, updated_at`
+
+func (r *Repository) ListSchedules() ([]domainAutoReply.Schedule, error) {
+	rows, err := r.db.Query(`SELECT ` + scheduleColumns + ` FROM autoreply_schedules ORDER BY account_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list business hours: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]domainAutoReply.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *Repository) GetSchedule(accountID string) (*domainAutoReply.Schedule, error) {
+	row := r.db.QueryRow(`SELECT `+scheduleColumns+` FROM autoreply_schedules WHERE account_id = ?`, accountID)
+
+	schedule, err := scanSchedule(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("business hours of account %s not found", accountID))
+	}
+	return schedule, err
+}
+
+// SaveSchedule creates or replaces the schedule of the account
+func (r *Repository) SaveSchedule(schedule *domainAutoReply.Schedule) error {
+	hours, err := json.Marshal(schedule.Hours)
+	if err != nil {
+		return fmt.Errorf("failed to encode business hours: %w", err)
+	}
+	holidays, err := json.Marshal(schedule.Holidays)
+	if err != nil {
+		return fmt.Errorf("failed to encode holidays: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO autoreply_schedules (`+scheduleColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id) DO UPDATE SET
+			enabled = excluded.enabled, time_zone = excluded.time_zone, hours_json = excluded.hours_json,
+			holidays_json = excluded.holidays_json, away_message = excluded.away_message,
+			greeting_message = excluded.greeting_message, updated_at = excluded.updated_at`,
+		schedule.AccountID, schedule.Enabled, schedule.TimeZone, string(hours), string(holidays),
+		schedule.AwayMessage, schedule.GreetingMessage, schedule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save business hours: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteSchedule(accountID string) error {
+	result, err := r.db.Exec(`DELETE FROM autoreply_schedules WHERE account_id = ?`, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete business hours: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("business hours of account %s not found", accountID))
+	}
+	return nil
+}
+
+func (r *Repository) RecordContact(accountID, contactJID string, seenAt time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT OR IGNORE INTO autoreply_contacts (account_id, contact_jid, first_seen_at) VALUES (?, ?, ?)`,
+		accountID, contactJID, seenAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record contact: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ClaimAwayWindow stores the window as unix seconds, the update only succeeds once per window
+func (r *Repository) ClaimAwayWindow(accountID, contactJID string, window time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE autoreply_contacts SET away_window = ?
+		WHERE account_id = ? AND contact_jid = ? AND away_window != ?`,
+		window.Unix(), accountID, contactJID, window.Unix(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim away window: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func requireAffected(result sql.Result, ruleID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("auto-reply rule %s not found", ruleID))
+	}
+	return nil
+}
+
+// parseTimestamp reads the text format the sqlite driver stores time.Time values in
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown timestamp format %q", value)
+}
+
+func scanRule(scanner interface{ Scan(...any) error }) (*domainAutoReply.Rule, error) {
+	var (
+		rule     domainAutoReply.Rule
+		keywords string
+	)
+	err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Priority, &rule.Enabled, &rule.AccountID, &rule.ChatJID, &rule.TriggerType,
+		&keywords, &rule.Pattern, &rule.CaseSensitive, &rule.Reply, &rule.MediaType, &rule.MediaURL,
+		&rule.CooldownSeconds, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan auto-reply rule: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(keywords), &rule.Keywords); err != nil {
+		return nil, fmt.Errorf("failed to decode auto-reply keywords: %w", err)
+	}
+	return &rule, nil
+}
+
+func scanSchedule(scanner interface{ Scan(...any) error }) (*domainAutoReply.Schedule, error) {
+	var (
+		schedule        domainAutoReply.Schedule
+		hours, holidays string
+	)
+	err := scanner.Scan(
+		&schedule.AccountID, &schedule.Enabled, &schedule.TimeZone, &hours, &holidays,
+		&schedule.AwayMessage, &schedule.GreetingMessage, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan business hours: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(hours), &schedule.Hours); err != nil {
+		return nil, fmt.Errorf("failed to decode business hours: %w", err)
+	}
+	if err := json.Unmarshal([]byte(holidays), &schedule.Holidays); err != nil {
+		return nil, fmt.Errorf("failed to decode holidays: %w", err)
+	}
+	return &schedule, nil
+}
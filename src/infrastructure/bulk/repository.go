@@ -0,0 +1,306 @@
+package bulk
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultListLimit = 50
+
+// Repository stores bulk send jobs and their recipients, the counters of a job are kept in step with the
+// outcomes of its recipients
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainBulk.IBulkRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS bulk_jobs (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL,
+			interval_ms INTEGER NOT NULL DEFAULT 0,
+			jitter_ms INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL DEFAULT 0,
+			sent INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0,
+			cancelled INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			finished_at DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_bulk_jobs_status ON bulk_jobs(status);
+		CREATE TABLE IF NOT EXISTS bulk_recipients (
+			job_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			phone TEXT NOT NULL,
+			status TEXT NOT NULL,
+			message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			sent_at DATETIME,
+			PRIMARY KEY (job_id, position)
+		);
+		CREATE INDEX IF NOT EXISTS idx_bulk_recipients_status ON bulk_recipients(job_id, status, position);`)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk send tables: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateJob(job *domainBulk.Job, phones []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO bulk_jobs (id, account_id, type, payload, status, interval_ms, jitter_ms, total, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.AccountID, job.Type, string(job.Payload), job.Status, job.IntervalMs, job.JitterMs, len(phones),
+		job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk send job: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO bulk_recipients (job_id, position, phone, status) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare recipients: %w", err)
+	}
+	defer stmt.Close()
+	for position, phone := range phones {
+		if _, err := stmt.Exec(job.ID, position, phone, domainBulk.RecipientPending); err != nil {
+			return fmt.Errorf("failed to store recipient %s: %w", phone, err)
+		}
+	}
+
+	job.Total = len(phones)
+	return tx.Commit()
+}
+
+const jobColumns = `id, account_id, type, payload, status, interval_ms, jitter_ms, total, sent, failed, cancelled,
+	created_at, updated_at, finished_at`
+
+func (r *Repository) GetJob(jobID string) (*domainBulk.Job, error) {
+	row := r.db.QueryRow(`SELECT `+jobColumns+` FROM bulk_jobs WHERE id = ?`, jobID)
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("bulk send job %s not found", jobID))
+	}
+	return job, err
+}
+
+func (r *Repository) ListJobs(filter domainBulk.JobFilter) ([]domainBulk.Job, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT ` + jobColumns + ` FROM bulk_jobs`
+	var args []any
+	if filter.Status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk send jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]domainBulk.Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *Repository) UpdateJobStatus(jobID, status string, finishedAt *time.Time) error {
+	result, err := r.db.Exec(`UPDATE bulk_jobs SET status = ?, finished_at = ?, updated_at = ? WHERE id = ?`,
+		status, finishedAt, time.Now(), jobID)
+	if err != nil {
+		return fmt.Errorf("failed to update bulk send job: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("bulk send job %s not found", jobID))
+	}
+	return nil
+}
+
+const recipientColumns = `job_id, position, phone, status, message_id, error, sent_at`
+
+func (r *Repository) NextRecipient(jobID string) (*domainBulk.Recipient, error) {
+	row := r.db.QueryRow(`
+		SELECT `+recipientColumns+` FROM bulk_recipients
+		WHERE job_id = ? AND status = ? ORDER BY position LIMIT 1`,
+		jobID, domainBulk.RecipientPending,
+	)
+
+	recipient, err := scanRecipient(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("bulk send job %s has no pending recipient", jobID))
+	}
+	return recipient, err
+}
+
+func (r *Repository) RecordResult(recipient *domainBulk.Recipient) error {
+	counter := map[string]string{
+		domainBulk.RecipientSent:      "sent",
+		domainBulk.RecipientFailed:    "failed",
+		domainBulk.RecipientCancelled: "cancelled",
+	}[recipient.Status]
+	if counter == "" {
+		return fmt.Errorf("invalid recipient status %q", recipient.Status)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE bulk_recipients SET status = ?, message_id = ?, error = ?, sent_at = ?
+		WHERE job_id = ? AND position = ? AND status = ?`,
+		recipient.Status, recipient.MessageID, recipient.Error, recipient.SentAt,
+		recipient.JobID, recipient.Position, domainBulk.RecipientPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record recipient result: %w", err)
+	}
+	// Already recorded, don't count it twice
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE bulk_jobs SET `+counter+` = `+counter+` + 1, updated_at = ? WHERE id = ?`, time.Now(), recipient.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to count recipient result: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) CancelPending(jobID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE bulk_recipients SET status = ? WHERE job_id = ? AND status = ?`,
+		domainBulk.RecipientCancelled, jobID, domainBulk.RecipientPending)
+	if err != nil {
+		return fmt.Errorf("failed to cancel recipients: %w", err)
+	}
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE bulk_jobs SET cancelled = cancelled + ?, updated_at = ? WHERE id = ?`, cancelled, time.Now(), jobID)
+	if err != nil {
+		return fmt.Errorf("failed to count cancelled recipients: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) ListRecipients(jobID string, filter domainBulk.RecipientFilter) ([]domainBulk.Recipient, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT ` + recipientColumns + ` FROM bulk_recipients WHERE job_id = ?`
+	args := []any{jobID}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY position LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := make([]domainBulk.Recipient, 0)
+	for rows.Next() {
+		recipient, err := scanRecipient(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, *recipient)
+	}
+	return recipients, rows.Err()
+}
+
+func scanJob(scanner interface{ Scan(...any) error }) (*domainBulk.Job, error) {
+	var (
+		job        domainBulk.Job
+		payload    string
+		finishedAt sql.NullTime
+	)
+	err := scanner.Scan(
+		&job.ID, &job.AccountID, &job.Type, &payload, &job.Status, &job.IntervalMs, &job.JitterMs, &job.Total,
+		&job.Sent, &job.Failed, &job.Cancelled, &job.CreatedAt, &job.UpdatedAt, &finishedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan bulk send job: %w", err)
+	}
+
+	job.Payload = []byte(payload)
+	if finishedAt.Valid {
+		at := finishedAt.Time
+		job.FinishedAt = &at
+	}
+	return &job, nil
+}
+
+func scanRecipient(scanner interface{ Scan(...any) error }) (*domainBulk.Recipient, error) {
+	var (
+		recipient domainBulk.Recipient
+		sentAt    sql.NullTime
+	)
+	err := scanner.Scan(&recipient.JobID, &recipient.Position, &recipient.Phone, &recipient.Status,
+		&recipient.MessageID, &recipient.Error, &sentAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan recipient: %w", err)
+	}
+
+	if sentAt.Valid {
+		at := sentAt.Time
+		recipient.SentAt = &at
+	}
+	return &recipient, nil
+}
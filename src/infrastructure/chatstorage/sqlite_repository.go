@@ -10,6 +10,7 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -65,7 +66,8 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, mimetype, direct_path, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE id = ?
 		LIMIT 1
@@ -177,8 +179,9 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			file_enc_sha256, file_length, mimetype, direct_path, forwarding_score,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
@@ -191,6 +194,9 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			mimetype = excluded.mimetype,
+			direct_path = excluded.direct_path,
+			forwarding_score = excluded.forwarding_score,
 			updated_at = excluded.updated_at
 	`
 
@@ -198,7 +204,8 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		message.ID, message.ChatJID, message.Sender, message.Content,
 		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-		message.FileLength, message.CreatedAt, message.UpdatedAt,
+		message.FileLength, message.Mimetype, message.DirectPath, message.ForwardingScore,
+		message.CreatedAt, message.UpdatedAt,
 	)
 
 	return err
@@ -221,8 +228,9 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			file_enc_sha256, file_length, mimetype, direct_path, forwarding_score,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
@@ -235,6 +243,9 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			mimetype = excluded.mimetype,
+			direct_path = excluded.direct_path,
+			forwarding_score = excluded.forwarding_score,
 			updated_at = excluded.updated_at
 	`)
 	if err != nil {
@@ -256,7 +267,8 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 			message.ID, message.ChatJID, message.Sender, message.Content,
 			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.CreatedAt, message.UpdatedAt,
+			message.FileLength, message.Mimetype, message.DirectPath, message.ForwardingScore,
+			message.CreatedAt, message.UpdatedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
@@ -296,7 +308,8 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, mimetype, direct_path, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -356,7 +369,8 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, mimetype, direct_path, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -414,7 +428,8 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
 		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
+		&message.FileLength, &message.Mimetype, &message.DirectPath, &message.ForwardingScore,
+		&message.CreatedAt, &message.UpdatedAt,
 	)
 	return message, err
 }
@@ -551,6 +566,7 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 	// Extract message content and media info
 	content := utils.ExtractMessageTextFromProto(evt.Message)
 	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
+	mimetype, directPath := utils.ExtractMediaDetails(evt.Message)
 
 	// Skip if there's no content and no media
 	if content == "" && mediaType == "" {
@@ -560,19 +576,22 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 
 	// Create message object
 	message := &domainChatStorage.Message{
-		ID:            evt.Info.ID,
-		ChatJID:       chatJID,
-		Sender:        sender,
-		Content:       content,
-		Timestamp:     evt.Info.Timestamp,
-		IsFromMe:      evt.Info.IsFromMe,
-		MediaType:     mediaType,
-		Filename:      filename,
-		URL:           url,
-		MediaKey:      mediaKey,
-		FileSHA256:    fileSHA256,
-		FileEncSHA256: fileEncSHA256,
-		FileLength:    fileLength,
+		ID:              evt.Info.ID,
+		ChatJID:         chatJID,
+		Sender:          sender,
+		Content:         content,
+		Timestamp:       evt.Info.Timestamp,
+		IsFromMe:        evt.Info.IsFromMe,
+		MediaType:       mediaType,
+		Filename:        filename,
+		URL:             url,
+		MediaKey:        mediaKey,
+		FileSHA256:      fileSHA256,
+		FileEncSHA256:   fileEncSHA256,
+		FileLength:      fileLength,
+		Mimetype:        mimetype,
+		DirectPath:      directPath,
+		ForwardingScore: utils.ExtractForwardingScore(evt.Message),
 	}
 
 	// Store the message
@@ -627,8 +646,9 @@ func (r *SQLiteRepository) TruncateAllDataWithLogging(logPrefix string) error {
 	return nil
 }
 
-// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
-func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support.
+// msg is the sent message, its media fields are kept so the media can be forwarded or downloaded later. It may be nil.
+func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time, msg *waE2E.Message) error {
 	// Check if context is already cancelled before starting
 	select {
 	case <-ctx.Done():
@@ -692,6 +712,16 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 		Timestamp: timestamp,
 		IsFromMe:  true,
 	}
+	if msg != nil {
+		message.MediaType, message.Filename, message.URL, message.MediaKey, message.FileSHA256,
+			message.FileEncSHA256, message.FileLength = utils.ExtractMediaInfo(msg)
+		message.Mimetype, message.DirectPath = utils.ExtractMediaDetails(msg)
+		message.ForwardingScore = utils.ExtractForwardingScore(msg)
+		if message.MediaType != "" {
+			// Keep the caption instead of the label of the media, the same as for received media
+			message.Content = utils.ExtractMessageTextFromProto(msg)
+		}
+	}
 
 	return r.StoreMessage(message)
 }
@@ -810,5 +840,12 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Keep what is needed to forward stored media without uploading it again
+		`
+		ALTER TABLE messages ADD COLUMN mimetype TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN direct_path TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN forwarding_score INTEGER NOT NULL DEFAULT 0;
+		`,
 	}
 }
@@ -0,0 +1,32 @@
+package eventsink
+
+import (
+	"strings"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+)
+
+// tokenReplacer strips characters that act as separators or wildcards in NATS subjects and Redis keys
+var tokenReplacer = strings.NewReplacer(".", "_", ":", "_", "*", "_", ">", "_", " ", "_")
+
+// typeReplacer keeps the dots of event types but strips wildcards and whitespace
+var typeReplacer = strings.NewReplacer(":", "_", "*", "_", ">", "_", " ", "_")
+
+// accountToken returns the account part of a subject or stream key
+func accountToken(accountID string) string {
+	if accountID == "" {
+		return domainEventSink.DefaultAccountID
+	}
+	return tokenReplacer.Replace(accountID)
+}
+
+// Subject builds the NATS subject "<prefix>.<account>.<event_type>".
+// Event types keep their dots (message.ack -> whatsapp.default.message.ack) so consumers can use wildcards.
+func Subject(prefix, accountID, eventType string) string {
+	return prefix + "." + accountToken(accountID) + "." + typeReplacer.Replace(eventType)
+}
+
+// StreamKey builds the Redis stream key "<prefix>:<account>:<event_type>"
+func StreamKey(prefix, accountID, eventType string) string {
+	return prefix + ":" + accountToken(accountID) + ":" + typeReplacer.Replace(eventType)
+}
@@ -0,0 +1,46 @@
+package eventsink
+
+import "testing"
+
+func TestSubject(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		eventType string
+		want      string
+	}{
+		{name: "DefaultAccount", accountID: "", eventType: "message", want: "whatsapp.default.message"},
+		{name: "DottedEventType", accountID: "sales", eventType: "message.ack", want: "whatsapp.sales.message.ack"},
+		{name: "AccountWithSeparators", accountID: "team.a:1", eventType: "message", want: "whatsapp.team_a_1.message"},
+		{name: "Wildcards", accountID: "*", eventType: "group >", want: "whatsapp._.group__"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Subject("whatsapp", tt.accountID, tt.eventType); got != tt.want {
+				t.Errorf("Subject() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		eventType string
+		want      string
+	}{
+		{name: "DefaultAccount", accountID: "", eventType: "message", want: "whatsapp:default:message"},
+		{name: "DottedEventType", accountID: "sales", eventType: "message.ack", want: "whatsapp:sales:message.ack"},
+		{name: "AccountWithSeparators", accountID: "team:a", eventType: "message", want: "whatsapp:team_a:message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StreamKey("whatsapp", tt.accountID, tt.eventType); got != tt.want {
+				t.Errorf("StreamKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+)
+
+type natsSink struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSSink connects to a NATS server and publishes every event on "<prefix>.<account>.<event_type>"
+func NewNATSSink(url, prefix string) (domainEventSink.IEventSink, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("go-whatsapp-web-multidevice"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logrus.Warnf("[EVENT_SINK] NATS disconnected: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logrus.Infof("[EVENT_SINK] NATS reconnected to %s", nc.ConnectedUrlRedacted())
+		}),
+	)
+	if err != nil {
+		return nil, pkgError.EventSinkError(fmt.Sprintf("failed to connect to NATS: %v", err))
+	}
+
+	return &natsSink{conn: conn, prefix: prefix}, nil
+}
+
+func (s *natsSink) Name() string {
+	return "nats"
+}
+
+func (s *natsSink) Publish(_ context.Context, event domainEventSink.Event) error {
+	body, err := json.Marshal(event.Payload)
+	if err != nil {
+		return pkgError.EventSinkError(fmt.Sprintf("failed to marshal event: %v", err))
+	}
+
+	msg := nats.NewMsg(Subject(s.prefix, event.AccountID, event.Type))
+	msg.Data = body
+	msg.Header.Set("Content-Type", domainWebhook.ContentType(event.Payload))
+	msg.Header.Set("X-Event-Type", event.Type)
+	msg.Header.Set("X-Account-Id", accountToken(event.AccountID))
+
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return pkgError.EventSinkError(fmt.Sprintf("failed to publish to NATS subject %s: %v", msg.Subject, err))
+	}
+	return nil
+}
+
+// Close flushes pending messages before closing the connection
+func (s *natsSink) Close() error {
+	return s.conn.Drain()
+}
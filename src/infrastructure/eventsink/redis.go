@@ -0,0 +1,72 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/redis/go-redis/v9"
+)
+
+type redisSink struct {
+	client *redis.Client
+	prefix string
+	maxLen int64
+}
+
+// NewRedisSink connects to Redis and appends every event to the stream "<prefix>:<account>:<event_type>".
+// Streams are approximately trimmed to maxLen entries; zero keeps every entry.
+func NewRedisSink(url, prefix string, maxLen int64) (domainEventSink.IEventSink, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, pkgError.EventSinkError(fmt.Sprintf("invalid Redis URL: %v", err))
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, pkgError.EventSinkError(fmt.Sprintf("failed to connect to Redis: %v", err))
+	}
+
+	return &redisSink{client: client, prefix: prefix, maxLen: maxLen}, nil
+}
+
+func (s *redisSink) Name() string {
+	return "redis"
+}
+
+func (s *redisSink) Publish(ctx context.Context, event domainEventSink.Event) error {
+	body, err := json.Marshal(event.Payload)
+	if err != nil {
+		return pkgError.EventSinkError(fmt.Sprintf("failed to marshal event: %v", err))
+	}
+
+	stream := StreamKey(s.prefix, event.AccountID, event.Type)
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]any{
+			"event":      event.Type,
+			"account_id": accountToken(event.AccountID),
+			"payload":    string(body),
+		},
+	}
+	if s.maxLen > 0 {
+		args.MaxLen = s.maxLen
+		args.Approx = true
+	}
+
+	if err := s.client.XAdd(ctx, args).Err(); err != nil {
+		return pkgError.EventSinkError(fmt.Sprintf("failed to append to Redis stream %s: %v", stream, err))
+	}
+	return nil
+}
+
+func (s *redisSink) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,114 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+)
+
+// These tests run against local brokers, e.g.
+//
+//	docker run -p 4222:4222 nats
+//	docker run -p 6379:6379 redis
+//	EVENT_SINK_TEST_NATS=nats://localhost:4222 EVENT_SINK_TEST_REDIS=redis://localhost:6379/15 go test ./infrastructure/eventsink/
+
+func testEvent() domainEventSink.Event {
+	return domainEventSink.Event{
+		AccountID: "test",
+		Type:      "message.ack",
+		Payload:   map[string]any{"event": "message.ack", "payload": map[string]any{"ids": []string{"3EB0"}}},
+	}
+}
+
+func TestNATSSinkPublish(t *testing.T) {
+	url := os.Getenv("EVENT_SINK_TEST_NATS")
+	if url == "" {
+		t.Skip("EVENT_SINK_TEST_NATS not set")
+	}
+
+	sink, err := NewNATSSink(url, "wa_test")
+	if err != nil {
+		t.Fatalf("NewNATSSink: %v", err)
+	}
+	defer sink.Close()
+
+	consumer, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	sub, err := consumer.SubscribeSync("wa_test.test.>")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := consumer.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if err := sink.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	msg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("NextMsg: %v", err)
+	}
+	if msg.Subject != "wa_test.test.message.ack" {
+		t.Errorf("subject = %q, want %q", msg.Subject, "wa_test.test.message.ack")
+	}
+	if got := msg.Header.Get("X-Event-Type"); got != "message.ack" {
+		t.Errorf("X-Event-Type = %q, want %q", got, "message.ack")
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload["event"] != "message.ack" {
+		t.Errorf("payload event = %v, want message.ack", payload["event"])
+	}
+}
+
+func TestRedisSinkPublish(t *testing.T) {
+	url := os.Getenv("EVENT_SINK_TEST_REDIS")
+	if url == "" {
+		t.Skip("EVENT_SINK_TEST_REDIS not set")
+	}
+
+	ctx := context.Background()
+	sink, err := NewRedisSink(url, "wa_test", 100)
+	if err != nil {
+		t.Fatalf("NewRedisSink: %v", err)
+	}
+	defer sink.Close()
+
+	opts, _ := redis.ParseURL(url)
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	stream := StreamKey("wa_test", "test", "message.ack")
+	client.Del(ctx, stream)
+	defer client.Del(ctx, stream)
+
+	if err := sink.Publish(ctx, testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	entries, err := client.XRange(ctx, stream, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 stream entry, got %d", len(entries))
+	}
+	if entries[0].Values["event"] != "message.ack" || entries[0].Values["account_id"] != "test" {
+		t.Errorf("unexpected entry fields: %v", entries[0].Values)
+	}
+}
@@ -0,0 +1,286 @@
+package flow
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultSessionLimit = 50
+
+// Repository stores flow definitions as JSON and the session of every contact, expires_at is kept as unix
+// seconds with 0 for sessions that don't time out
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainFlow.IFlowRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS flows (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL DEFAULT '',
+			account_id TEXT NOT NULL DEFAULT '',
+			definition_json TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS flow_sessions (
+			account_id TEXT NOT NULL,
+			contact_jid TEXT NOT NULL,
+			flow_id TEXT NOT NULL,
+			state TEXT NOT NULL,
+			status TEXT NOT NULL,
+			vars_json TEXT NOT NULL DEFAULT '{}',
+			started_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			expires_at INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (account_id, contact_jid)
+		);
+		CREATE INDEX IF NOT EXISTS idx_flow_sessions_expiry ON flow_sessions(status, expires_at);`)
+	if err != nil {
+		return fmt.Errorf("failed to create flow tables: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListFlows() ([]domainFlow.Flow, error) {
+	rows, err := r.db.Query(`SELECT definition_json, created_at, updated_at FROM flows ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flows: %w", err)
+	}
+	defer rows.Close()
+
+	flows := make([]domainFlow.Flow, 0)
+	for rows.Next() {
+		flow, err := scanFlow(rows)
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, *flow)
+	}
+	return flows, rows.Err()
+}
+
+func (r *Repository) GetFlow(flowID string) (*domainFlow.Flow, error) {
+	row := r.db.QueryRow(`SELECT definition_json, created_at, updated_at FROM flows WHERE id = ?`, flowID)
+
+	flow, err := scanFlow(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("flow %s not found", flowID))
+	}
+	return flow, err
+}
+
+func (r *Repository) CreateFlow(flow *domainFlow.Flow) error {
+	definition, err := json.Marshal(flow)
+	if err != nil {
+		return fmt.Errorf("failed to encode flow: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO flows (id, name, account_id, definition_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		flow.ID, flow.Name, flow.AccountID, string(definition), flow.CreatedAt, flow.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create flow: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateFlow(flow *domainFlow.Flow) error {
+	definition, err := json.Marshal(flow)
+	if err != nil {
+		return fmt.Errorf("failed to encode flow: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE flows SET name = ?, account_id = ?, definition_json = ?, updated_at = ?
+		WHERE id = ?`,
+		flow.Name, flow.AccountID, string(definition), flow.UpdatedAt, flow.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update flow: %w", err)
+	}
+	return requireAffected(result, flow.ID)
+}
+
+// DeleteFlow removes the flow, sessions still in it end when the contact writes again
+func (r *Repository) DeleteFlow(flowID string) error {
+	result, err := r.db.Exec(`DELETE FROM flows WHERE id = ?`, flowID)
+	if err != nil {
+		return fmt.Errorf("failed to delete flow: %w", err)
+	}
+	return requireAffected(result, flowID)
+}
+
+const sessionColumns = `account_id, contact_jid, flow_id, state, status, vars_json, started_at, updated_at, expires_at`
+
+func (r *Repository) GetSession(accountID, contactJID string) (*domainFlow.Session, error) {
+	row := r.db.QueryRow(`SELECT `+sessionColumns+` FROM flow_sessions WHERE account_id = ? AND contact_jid = ?`,
+		accountID, contactJID)
+
+	session, err := scanSession(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("no flow session for %s", contactJID))
+	}
+	return session, err
+}
+
+func (r *Repository) SaveSession(session *domainFlow.Session) error {
+	vars, err := json.Marshal(session.Vars)
+	if err != nil {
+		return fmt.Errorf("failed to encode session variables: %w", err)
+	}
+
+	var expiresAt int64
+	if session.ExpiresAt != nil {
+		expiresAt = session.ExpiresAt.Unix()
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO flow_sessions (`+sessionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, contact_jid) DO UPDATE SET
+			flow_id = excluded.flow_id, state = excluded.state, status = excluded.status,
+			vars_json = excluded.vars_json, started_at = excluded.started_at, updated_at = excluded.updated_at,
+			expires_at = excluded.expires_at`,
+		session.AccountID, session.ContactJID, session.FlowID, session.State, session.Status, string(vars),
+		session.StartedAt, session.UpdatedAt, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save flow session: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListSessions(filter domainFlow.SessionFilter) ([]domainFlow.Session, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+	if filter.FlowID != "" {
+		conditions = append(conditions, "flow_id = ?")
+		args = append(args, filter.FlowID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := `SELECT ` + sessionColumns + ` FROM flow_sessions`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultSessionLimit
+	}
+	query += " ORDER BY updated_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, filter.Offset)
+
+	return r.querySessions(query, args...)
+}
+
+func (r *Repository) ListExpiredSessions(now time.Time) ([]domainFlow.Session, error) {
+	return r.querySessions(`
+		SELECT `+sessionColumns+` FROM flow_sessions
+		WHERE status IN (?, ?) AND expires_at > 0 AND expires_at <= ?`,
+		domainFlow.SessionActive, domainFlow.SessionHandoff, now.Unix(),
+	)
+}
+
+func (r *Repository) querySessions(query string, args ...any) ([]domainFlow.Session, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flow sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]domainFlow.Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+func requireAffected(result sql.Result, flowID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("flow %s not found", flowID))
+	}
+	return nil
+}
+
+func scanFlow(scanner interface{ Scan(...any) error }) (*domainFlow.Flow, error) {
+	var (
+		flow       domainFlow.Flow
+		definition string
+		createdAt  time.Time
+		updatedAt  time.Time
+	)
+	if err := scanner.Scan(&definition, &createdAt, &updatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan flow: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(definition), &flow); err != nil {
+		return nil, fmt.Errorf("failed to decode flow: %w", err)
+	}
+	flow.CreatedAt = createdAt
+	flow.UpdatedAt = updatedAt
+	return &flow, nil
+}
+
+func scanSession(scanner interface{ Scan(...any) error }) (*domainFlow.Session, error) {
+	var (
+		session   domainFlow.Session
+		vars      string
+		expiresAt int64
+	)
+	err := scanner.Scan(
+		&session.AccountID, &session.ContactJID, &session.FlowID, &session.State, &session.Status, &vars,
+		&session.StartedAt, &session.UpdatedAt, &expiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan flow session: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(vars), &session.Vars); err != nil {
+		return nil, fmt.Errorf("failed to decode session variables: %w", err)
+	}
+	if session.Vars == nil {
+		session.Vars = make(map[string]string)
+	}
+	if expiresAt > 0 {
+		expires := time.Unix(expiresAt, 0)
+		session.ExpiresAt = &expires
+	}
+	return &session, nil
+}
@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+)
+
+// Repository keeps idempotency keys with the response of their request until they expire
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainIdempotency.IIdempotencyRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key TEXT PRIMARY KEY,
+			fingerprint TEXT NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response BLOB,
+			created_at DATETIME NOT NULL,
+			expires_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);`)
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency keys table: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) Reserve(record *domainIdempotency.Record) (*domainIdempotency.Record, bool, error) {
+	// An expired key is claimed like a new one
+	result, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (key, fingerprint, status_code, response, created_at, expires_at) VALUES (?, ?, 0, NULL, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET fingerprint = excluded.fingerprint, status_code = 0, response = NULL,
+			created_at = excluded.created_at, expires_at = excluded.expires_at
+		WHERE idempotency_keys.expires_at <= excluded.created_at`,
+		record.Key, record.Fingerprint, record.CreatedAt.UTC(), record.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows > 0 {
+		return nil, true, nil
+	}
+
+	var existing domainIdempotency.Record
+	err = r.db.QueryRow(`
+		SELECT key, fingerprint, status_code, response, created_at, expires_at FROM idempotency_keys WHERE key = ?`, record.Key,
+	).Scan(&existing.Key, &existing.Fingerprint, &existing.StatusCode, &existing.Response, &existing.CreatedAt, &existing.ExpiresAt)
+	if err == sql.ErrNoRows {
+		// Released between the insert and the select, the caller can try again
+		return nil, false, fmt.Errorf("idempotency key %s was released while reserving it", record.Key)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &existing, false, nil
+}
+
+func (r *Repository) Complete(key string, statusCode int, response []byte) error {
+	_, err := r.db.Exec(`UPDATE idempotency_keys SET status_code = ?, response = ? WHERE key = ?`, statusCode, response, key)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) Release(key string) error {
+	if _, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE key = ? AND status_code = 0`, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteExpired(now time.Time) error {
+	if _, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= ?`, now.UTC()); err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package poll
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// Repository stores the polls sent by the gateway and the current vote of every voter, options are kept as JSON
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainPoll.IPollRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS polls (
+			message_id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			question TEXT NOT NULL,
+			options_json TEXT NOT NULL,
+			max_answer INTEGER NOT NULL DEFAULT 0,
+			secret BLOB NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS poll_votes (
+			message_id TEXT NOT NULL,
+			voter_jid TEXT NOT NULL,
+			options_json TEXT NOT NULL,
+			voted_at DATETIME NOT NULL,
+			PRIMARY KEY (message_id, voter_jid)
+		);`)
+	if err != nil {
+		return fmt.Errorf("failed to create poll tables: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreatePoll(poll *domainPoll.Poll) error {
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode poll options: %w", err)
+	}
+	_, err = r.db.Exec(`
+		INSERT INTO polls (message_id, account_id, chat_jid, question, options_json, max_answer, secret, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		poll.MessageID, poll.AccountID, poll.ChatJID, poll.Question, string(options), poll.MaxAnswer, poll.Secret, poll.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store poll: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetPoll(messageID string) (*domainPoll.Poll, error) {
+	var (
+		poll    domainPoll.Poll
+		options string
+	)
+	err := r.db.QueryRow(`
+		SELECT message_id, account_id, chat_jid, question, options_json, max_answer, secret, created_at
+		FROM polls WHERE message_id = ?`, messageID,
+	).Scan(&poll.MessageID, &poll.AccountID, &poll.ChatJID, &poll.Question, &options, &poll.MaxAnswer, &poll.Secret, &poll.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("poll %s not found", messageID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(options), &poll.Options); err != nil {
+		return nil, fmt.Errorf("failed to decode poll options: %w", err)
+	}
+	return &poll, nil
+}
+
+func (r *Repository) PutVote(vote *domainPoll.Vote) (bool, error) {
+	options, err := json.Marshal(vote.Options)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode vote options: %w", err)
+	}
+	// Votes can arrive out of order after the gateway was offline, an older vote never replaces a later one
+	result, err := r.db.Exec(`
+		INSERT INTO poll_votes (message_id, voter_jid, options_json, voted_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (message_id, voter_jid) DO UPDATE SET options_json = excluded.options_json, voted_at = excluded.voted_at
+		WHERE excluded.voted_at >= poll_votes.voted_at AND excluded.options_json != poll_votes.options_json`,
+		vote.MessageID, vote.VoterJID, string(options), vote.VotedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to store vote: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *Repository) ListVotes(messageID string) ([]domainPoll.Vote, error) {
+	rows, err := r.db.Query(`
+		SELECT message_id, voter_jid, options_json, voted_at FROM poll_votes
+		WHERE message_id = ? ORDER BY voted_at, voter_jid`, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+	defer rows.Close()
+
+	votes := make([]domainPoll.Vote, 0)
+	for rows.Next() {
+		var (
+			vote    domainPoll.Vote
+			options string
+		)
+		if err := rows.Scan(&vote.MessageID, &vote.VoterJID, &options, &vote.VotedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		if err := json.Unmarshal([]byte(options), &vote.Options); err != nil {
+			return nil, fmt.Errorf("failed to decode vote options: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
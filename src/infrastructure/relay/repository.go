@@ -0,0 +1,242 @@
+package relay
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultListLimit = 50
+
+// Repository stores the relay rules and the messages the relay sent
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainRelay.IRelayRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS relay_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			source_account_id TEXT NOT NULL,
+			source_chat_jid TEXT NOT NULL DEFAULT '',
+			destination_account_id TEXT NOT NULL DEFAULT '',
+			destination_chat_jid TEXT NOT NULL,
+			header TEXT NOT NULL DEFAULT '',
+			bridge_replies BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS relay_messages (
+			message_id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			rule_id TEXT NOT NULL,
+			direction TEXT NOT NULL,
+			source_account_id TEXT NOT NULL,
+			source_chat_jid TEXT NOT NULL,
+			source_message_id TEXT NOT NULL,
+			source_sender_jid TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_relay_messages_rule_id ON relay_messages(rule_id, created_at);
+		CREATE INDEX IF NOT EXISTS idx_relay_messages_created_at ON relay_messages(created_at);`)
+	if err != nil {
+		return fmt.Errorf("failed to create relay tables: %w", err)
+	}
+	return nil
+}
+
+const ruleColumns = `id, name, enabled, source_account_id, source_chat_jid, destination_account_id, destination_chat_jid,
+	header, bridge_replies, created_at, updated_at`
+
+func (r *Repository) ListRules() ([]domainRelay.Rule, error) {
+	rows, err := r.db.Query(`SELECT ` + ruleColumns + ` FROM relay_rules ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relay rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]domainRelay.Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) GetRule(ruleID string) (*domainRelay.Rule, error) {
+	row := r.db.QueryRow(`SELECT `+ruleColumns+` FROM relay_rules WHERE id = ?`, ruleID)
+
+	rule, err := scanRule(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("relay rule %s not found", ruleID))
+	}
+	return rule, err
+}
+
+func (r *Repository) CreateRule(rule *domainRelay.Rule) error {
+	_, err := r.db.Exec(`
+		INSERT INTO relay_rules (`+ruleColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID, rule.Name, rule.Enabled, rule.SourceAccountID, rule.SourceChatJID, rule.DestinationAccountID,
+		rule.DestinationChatJID, rule.Header, rule.BridgeReplies, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create relay rule: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateRule(rule *domainRelay.Rule) error {
+	result, err := r.db.Exec(`
+		UPDATE relay_rules SET name = ?, enabled = ?, source_account_id = ?, source_chat_jid = ?,
+			destination_account_id = ?, destination_chat_jid = ?, header = ?, bridge_replies = ?, updated_at = ?
+		WHERE id = ?`,
+		rule.Name, rule.Enabled, rule.SourceAccountID, rule.SourceChatJID, rule.DestinationAccountID,
+		rule.DestinationChatJID, rule.Header, rule.BridgeReplies, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update relay rule: %w", err)
+	}
+	return requireAffected(result, rule.ID)
+}
+
+// DeleteRule removes the rule, its relayed messages stay until they expire so relayed copies are still
+// recognized as such
+func (r *Repository) DeleteRule(ruleID string) error {
+	result, err := r.db.Exec(`DELETE FROM relay_rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete relay rule: %w", err)
+	}
+	return requireAffected(result, ruleID)
+}
+
+func (r *Repository) SaveMessage(message *domainRelay.Message) error {
+	_, err := r.db.Exec(`
+		INSERT INTO relay_messages (message_id, account_id, chat_jid, rule_id, direction, source_account_id,
+			source_chat_jid, source_message_id, source_sender_jid, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(message_id) DO NOTHING`,
+		message.MessageID, message.AccountID, message.ChatJID, message.RuleID, message.Direction,
+		message.SourceAccountID, message.SourceChatJID, message.SourceMessageID, message.SourceSenderJID,
+		message.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save relayed message: %w", err)
+	}
+	return nil
+}
+
+const messageColumns = `message_id, account_id, chat_jid, rule_id, direction, source_account_id, source_chat_jid,
+	source_message_id, source_sender_jid, created_at`
+
+func (r *Repository) GetMessage(messageID string) (*domainRelay.Message, error) {
+	row := r.db.QueryRow(`SELECT `+messageColumns+` FROM relay_messages WHERE message_id = ?`, messageID)
+
+	message, err := scanMessage(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("relayed message %s not found", messageID))
+	}
+	return message, err
+}
+
+func (r *Repository) ListMessages(filter domainRelay.MessageFilter) ([]domainRelay.Message, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT ` + messageColumns + ` FROM relay_messages`
+	var args []any
+	if filter.RuleID != "" {
+		query += ` WHERE rule_id = ?`
+		args = append(args, filter.RuleID)
+	}
+	query += ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relayed messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]domainRelay.Message, 0)
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+	return messages, rows.Err()
+}
+
+func (r *Repository) DeleteMessage(messageID string) error {
+	if _, err := r.db.Exec(`DELETE FROM relay_messages WHERE message_id = ?`, messageID); err != nil {
+		return fmt.Errorf("failed to delete relayed message: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteMessagesBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM relay_messages WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete relayed messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func requireAffected(result sql.Result, ruleID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("relay rule %s not found", ruleID))
+	}
+	return nil
+}
+
+func scanRule(scanner interface{ Scan(...any) error }) (*domainRelay.Rule, error) {
+	var rule domainRelay.Rule
+	err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Enabled, &rule.SourceAccountID, &rule.SourceChatJID, &rule.DestinationAccountID,
+		&rule.DestinationChatJID, &rule.Header, &rule.BridgeReplies, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan relay rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func scanMessage(scanner interface{ Scan(...any) error }) (*domainRelay.Message, error) {
+	var message domainRelay.Message
+	err := scanner.Scan(
+		&message.MessageID, &message.AccountID, &message.ChatJID, &message.RuleID, &message.Direction,
+		&message.SourceAccountID, &message.SourceChatJID, &message.SourceMessageID, &message.SourceSenderJID,
+		&message.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan relayed message: %w", err)
+	}
+	return &message, nil
+}
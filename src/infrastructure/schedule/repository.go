@@ -0,0 +1,208 @@
+package schedule
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultListLimit = 50
+
+// Repository stores scheduled messages. send_at is kept as RFC 3339 text so a recurrence keeps the offset it
+// was scheduled in, next_run_at is stored in UTC so due schedules can be compared in SQL.
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainSchedule.IScheduleRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS scheduled_messages (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			send_at TEXT NOT NULL,
+			recurrence TEXT NOT NULL DEFAULT '',
+			missed_policy TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			next_run_at DATETIME,
+			retry_at DATETIME,
+			runs INTEGER NOT NULL DEFAULT 0,
+			last_run_at DATETIME,
+			last_status TEXT NOT NULL DEFAULT '',
+			last_message_id TEXT NOT NULL DEFAULT '',
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(status, next_run_at);`)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled messages table: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateSchedule(schedule *domainSchedule.Schedule) error {
+	_, err := r.db.Exec(`
+		INSERT INTO scheduled_messages (id, account_id, type, phone, payload, send_at, recurrence, missed_policy,
+			status, next_run_at, retry_at, runs, last_run_at, last_status, last_message_id, last_error, created_at,
+			updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		schedule.ID, schedule.AccountID, schedule.Type, schedule.Phone, string(schedule.Payload),
+		schedule.SendAt.Format(time.RFC3339Nano), schedule.Recurrence, schedule.MissedPolicy, schedule.Status,
+		utcTime(schedule.NextRunAt), utcTime(schedule.RetryAt), schedule.Runs, schedule.LastRunAt, schedule.LastStatus, schedule.LastMessageID,
+		schedule.LastError, schedule.CreatedAt, schedule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+	return nil
+}
+
+const scheduleColumns = `id, account_id, type, phone, payload, send_at, recurrence, missed_policy, status, next_run_at,
+	retry_at, runs, last_run_at, last_status, last_message_id, last_error, created_at, updated_at`
+
+func (r *Repository) GetSchedule(scheduleID string) (*domainSchedule.Schedule, error) {
+	row := r.db.QueryRow(`SELECT `+scheduleColumns+` FROM scheduled_messages WHERE id = ?`, scheduleID)
+
+	schedule, err := scanSchedule(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("scheduled message %s not found", scheduleID))
+	}
+	return schedule, err
+}
+
+func (r *Repository) ListSchedules(filter domainSchedule.Filter) ([]domainSchedule.Schedule, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT ` + scheduleColumns + ` FROM scheduled_messages WHERE 1 = 1`
+	var args []any
+	if filter.AccountID != "" {
+		query += ` AND account_id = ?`
+		args = append(args, filter.AccountID)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	if filter.Phone != "" {
+		query += ` AND phone = ?`
+		args = append(args, filter.Phone)
+	}
+	query += ` ORDER BY next_run_at IS NULL, next_run_at, created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	return r.querySchedules(query, args...)
+}
+
+func (r *Repository) UpdateSchedule(schedule *domainSchedule.Schedule) error {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages SET phone = ?, payload = ?, send_at = ?, recurrence = ?, missed_policy = ?,
+			status = ?, next_run_at = ?, retry_at = ?, runs = ?, last_run_at = ?, last_status = ?, last_message_id = ?,
+			last_error = ?, updated_at = ?
+		WHERE id = ?`,
+		schedule.Phone, string(schedule.Payload), schedule.SendAt.Format(time.RFC3339Nano), schedule.Recurrence,
+		schedule.MissedPolicy, schedule.Status, utcTime(schedule.NextRunAt), utcTime(schedule.RetryAt), schedule.Runs, schedule.LastRunAt,
+		schedule.LastStatus, schedule.LastMessageID, schedule.LastError, schedule.UpdatedAt, schedule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled message: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("scheduled message %s not found", schedule.ID))
+	}
+	return nil
+}
+
+func (r *Repository) ListDue(now time.Time, limit int) ([]domainSchedule.Schedule, error) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	return r.querySchedules(`
+		SELECT `+scheduleColumns+` FROM scheduled_messages
+		WHERE status = ? AND next_run_at IS NOT NULL AND next_run_at <= ? AND (retry_at IS NULL OR retry_at <= ?)
+		ORDER BY next_run_at LIMIT ?`,
+		domainSchedule.StatusScheduled, now.UTC(), now.UTC(), limit,
+	)
+}
+
+func (r *Repository) querySchedules(query string, args ...any) ([]domainSchedule.Schedule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]domainSchedule.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func scanSchedule(scanner interface{ Scan(...any) error }) (*domainSchedule.Schedule, error) {
+	var (
+		schedule  domainSchedule.Schedule
+		payload   string
+		sendAt    string
+		nextRunAt sql.NullTime
+		retryAt   sql.NullTime
+		lastRunAt sql.NullTime
+	)
+	err := scanner.Scan(
+		&schedule.ID, &schedule.AccountID, &schedule.Type, &schedule.Phone, &payload, &sendAt, &schedule.Recurrence,
+		&schedule.MissedPolicy, &schedule.Status, &nextRunAt, &retryAt, &schedule.Runs, &lastRunAt, &schedule.LastStatus,
+		&schedule.LastMessageID, &schedule.LastError, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan scheduled message: %w", err)
+	}
+
+	schedule.Payload = []byte(payload)
+	if schedule.SendAt, err = time.Parse(time.RFC3339Nano, sendAt); err != nil {
+		return nil, fmt.Errorf("failed to parse send_at of scheduled message %s: %w", schedule.ID, err)
+	}
+	if nextRunAt.Valid {
+		at := nextRunAt.Time
+		schedule.NextRunAt = &at
+	}
+	if retryAt.Valid {
+		at := retryAt.Time
+		schedule.RetryAt = &at
+	}
+	if lastRunAt.Valid {
+		at := lastRunAt.Time
+		schedule.LastRunAt = &at
+	}
+	return &schedule, nil
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
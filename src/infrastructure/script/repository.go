@@ -0,0 +1,329 @@
+package script
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultListLimit = 50
+
+// Repository stores scripts with their run counters, the latest errors of every script and the chat tags
+// scripts set, events are kept as JSON
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainScript.IScriptRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS scripts (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			events_json TEXT NOT NULL DEFAULT '[]',
+			source TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			timeout_ms INTEGER NOT NULL DEFAULT 0,
+			runs INTEGER NOT NULL DEFAULT 0,
+			failures INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			last_error_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS script_errors (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			script_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			chat_jid TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_script_errors_script_id ON script_errors(script_id, id);
+		CREATE TABLE IF NOT EXISTS chat_tags (
+			account_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			script_id TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (account_id, chat_jid, tag)
+		);
+		CREATE INDEX IF NOT EXISTS idx_chat_tags_tag ON chat_tags(account_id, tag);`)
+	if err != nil {
+		return fmt.Errorf("failed to create script tables: %w", err)
+	}
+	return nil
+}
+
+const scriptColumns = `id, name, account_id, events_json, source, enabled, timeout_ms, runs, failures, last_error,
+	last_error_at, created_at, updated_at`
+
+func (r *Repository) ListScripts() ([]domainScript.Script, error) {
+	rows, err := r.db.Query(`SELECT ` + scriptColumns + ` FROM scripts ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts: %w", err)
+	}
+	defer rows.Close()
+
+	scripts := make([]domainScript.Script, 0)
+	for rows.Next() {
+		script, err := scanScript(rows)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, *script)
+	}
+	return scripts, rows.Err()
+}
+
+func (r *Repository) GetScript(scriptID string) (*domainScript.Script, error) {
+	row := r.db.QueryRow(`SELECT `+scriptColumns+` FROM scripts WHERE id = ?`, scriptID)
+
+	script, err := scanScript(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("script %s not found", scriptID))
+	}
+	return script, err
+}
+
+func (r *Repository) CreateScript(script *domainScript.Script) error {
+	events, err := json.Marshal(script.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode script events: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO scripts (id, name, account_id, events_json, source, enabled, timeout_ms, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		script.ID, script.Name, script.AccountID, string(events), script.Source, script.Enabled, script.TimeoutMs,
+		script.CreatedAt, script.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create script: %w", err)
+	}
+	return nil
+}
+
+// UpdateScript replaces the definition of the script, its run counters and errors are kept
+func (r *Repository) UpdateScript(script *domainScript.Script) error {
+	events, err := json.Marshal(script.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode script events: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE scripts SET name = ?, account_id = ?, events_json = ?, source = ?, enabled = ?, timeout_ms = ?, updated_at = ?
+		WHERE id = ?`,
+		script.Name, script.AccountID, string(events), script.Source, script.Enabled, script.TimeoutMs, script.UpdatedAt,
+		script.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update script: %w", err)
+	}
+	return requireAffected(result, script.ID)
+}
+
+// DeleteScript removes the script and its errors, the chat tags it set stay
+func (r *Repository) DeleteScript(scriptID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM scripts WHERE id = ?`, scriptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete script: %w", err)
+	}
+	if err = requireAffected(result, scriptID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM script_errors WHERE script_id = ?`, scriptID); err != nil {
+		return fmt.Errorf("failed to delete script errors: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) RecordRun(scriptID string, runErr *domainScript.RunError) error {
+	if runErr == nil {
+		if _, err := r.db.Exec(`UPDATE scripts SET runs = runs + 1 WHERE id = ?`, scriptID); err != nil {
+			return fmt.Errorf("failed to record script run: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE scripts SET runs = runs + 1, failures = failures + 1, last_error = ?, last_error_at = ?
+		WHERE id = ?`,
+		runErr.Error, runErr.CreatedAt, scriptID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record script run: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO script_errors (script_id, event_type, chat_jid, error, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		scriptID, runErr.EventType, runErr.ChatJID, runErr.Error, runErr.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record script error: %w", err)
+	}
+	_, err = tx.Exec(`
+		DELETE FROM script_errors WHERE script_id = ? AND id NOT IN (
+			SELECT id FROM script_errors WHERE script_id = ? ORDER BY id DESC LIMIT ?
+		)`,
+		scriptID, scriptID, domainScript.MaxErrorsKept,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune script errors: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) ListErrors(scriptID string, filter domainScript.ErrorFilter) ([]domainScript.RunError, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, script_id, event_type, chat_jid, error, created_at FROM script_errors
+		WHERE script_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`,
+		scriptID, limit, filter.Offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list script errors: %w", err)
+	}
+	defer rows.Close()
+
+	runErrors := make([]domainScript.RunError, 0)
+	for rows.Next() {
+		var runErr domainScript.RunError
+		err := rows.Scan(&runErr.ID, &runErr.ScriptID, &runErr.EventType, &runErr.ChatJID, &runErr.Error, &runErr.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan script error: %w", err)
+		}
+		runErrors = append(runErrors, runErr)
+	}
+	return runErrors, rows.Err()
+}
+
+func (r *Repository) AddChatTag(tag *domainScript.ChatTag) error {
+	_, err := r.db.Exec(`
+		INSERT INTO chat_tags (account_id, chat_jid, tag, script_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, chat_jid, tag) DO NOTHING`,
+		tag.AccountID, tag.ChatJID, tag.Tag, tag.ScriptID, tag.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to tag chat: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) RemoveChatTag(accountID, chatJID, tag string) error {
+	_, err := r.db.Exec(`DELETE FROM chat_tags WHERE account_id = ? AND chat_jid = ? AND tag = ?`, accountID, chatJID, tag)
+	if err != nil {
+		return fmt.Errorf("failed to untag chat: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListChatTags(filter domainScript.ChatTagFilter) ([]domainScript.ChatTag, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "tag = ?")
+		args = append(args, filter.Tag)
+	}
+
+	query := `SELECT account_id, chat_jid, tag, script_id, created_at FROM chat_tags`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]domainScript.ChatTag, 0)
+	for rows.Next() {
+		var tag domainScript.ChatTag
+		if err := rows.Scan(&tag.AccountID, &tag.ChatJID, &tag.Tag, &tag.ScriptID, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func requireAffected(result sql.Result, scriptID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("script %s not found", scriptID))
+	}
+	return nil
+}
+
+func scanScript(scanner interface{ Scan(...any) error }) (*domainScript.Script, error) {
+	var (
+		script      domainScript.Script
+		events      string
+		lastErrorAt sql.NullTime
+	)
+	err := scanner.Scan(
+		&script.ID, &script.Name, &script.AccountID, &events, &script.Source, &script.Enabled, &script.TimeoutMs,
+		&script.Runs, &script.Failures, &script.LastError, &lastErrorAt, &script.CreatedAt, &script.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan script: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(events), &script.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode script events: %w", err)
+	}
+	if lastErrorAt.Valid {
+		at := lastErrorAt.Time
+		script.LastErrorAt = &at
+	}
+	return &script, nil
+}
@@ -0,0 +1,104 @@
+package status
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+)
+
+const defaultListLimit = 50
+
+// Repository stores the statuses posted through the API, the phones of a list audience are kept as JSON
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainStatus.IStatusRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS statuses (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			text TEXT NOT NULL DEFAULT '',
+			caption TEXT NOT NULL DEFAULT '',
+			background_color TEXT NOT NULL DEFAULT '',
+			text_color TEXT NOT NULL DEFAULT '',
+			font TEXT NOT NULL DEFAULT '',
+			audience TEXT NOT NULL,
+			phones_json TEXT NOT NULL DEFAULT '[]',
+			posted_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_statuses_posted_at ON statuses(posted_at);`)
+	if err != nil {
+		return fmt.Errorf("failed to create statuses table: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateStatus(status *domainStatus.Status) error {
+	phones, err := json.Marshal(status.Phones)
+	if err != nil {
+		return fmt.Errorf("failed to encode status phones: %w", err)
+	}
+	_, err = r.db.Exec(`
+		INSERT INTO statuses (id, account_id, type, text, caption, background_color, text_color, font, audience, phones_json, posted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		status.ID, status.AccountID, status.Type, status.Text, status.Caption, status.BackgroundColor, status.TextColor,
+		status.Font, status.Audience, string(phones), status.PostedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store status: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListStatuses(filter domainStatus.Filter) ([]domainStatus.Status, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT id, account_id, type, text, caption, background_color, text_color, font, audience, phones_json, posted_at
+		FROM statuses WHERE 1 = 1`
+	var args []any
+	if filter.AccountID != "" {
+		query += ` AND account_id = ?`
+		args = append(args, filter.AccountID)
+	}
+	if filter.Type != "" {
+		query += ` AND type = ?`
+		args = append(args, filter.Type)
+	}
+	query += ` ORDER BY posted_at DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := make([]domainStatus.Status, 0)
+	for rows.Next() {
+		var (
+			status domainStatus.Status
+			phones string
+		)
+		err := rows.Scan(&status.ID, &status.AccountID, &status.Type, &status.Text, &status.Caption, &status.BackgroundColor,
+			&status.TextColor, &status.Font, &status.Audience, &phones, &status.PostedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		if err := json.Unmarshal([]byte(phones), &status.Phones); err != nil {
+			return nil, fmt.Errorf("failed to decode status phones: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
@@ -0,0 +1,237 @@
+package template
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultListLimit = 50
+
+// Repository stores templates and every version of their content, variants are kept as JSON
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domainTemplate.ITemplateRepository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS message_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS message_template_versions (
+			template_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			default_language TEXT NOT NULL,
+			variants_json TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (template_id, version)
+		);`)
+	if err != nil {
+		return fmt.Errorf("failed to create template tables: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateTemplate(template *domainTemplate.Template) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO message_templates (id, name, type, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		template.ID, template.Name, template.Type, template.Version, template.CreatedAt, template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	if err = insertVersion(tx, template); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) UpdateTemplate(template *domainTemplate.Template) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE message_templates SET name = ?, version = ?, updated_at = ? WHERE id = ?`,
+		template.Name, template.Version, template.UpdatedAt, template.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("template %s not found", template.ID))
+	}
+	if err = insertVersion(tx, template); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertVersion(tx *sql.Tx, template *domainTemplate.Template) error {
+	variants, err := json.Marshal(template.Variants)
+	if err != nil {
+		return fmt.Errorf("failed to encode template variants: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO message_template_versions (template_id, version, default_language, variants_json, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		template.ID, template.Version, template.DefaultLanguage, string(variants), template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store template version: %w", err)
+	}
+	return nil
+}
+
+const templateColumns = `t.id, t.name, t.type, v.version, v.default_language, v.variants_json, t.created_at, t.updated_at`
+
+func (r *Repository) GetTemplate(templateID string) (*domainTemplate.Template, error) {
+	row := r.db.QueryRow(`
+		SELECT `+templateColumns+` FROM message_templates t
+		JOIN message_template_versions v ON v.template_id = t.id AND v.version = t.version
+		WHERE t.id = ?`, templateID)
+
+	template, err := scanTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("template %s not found", templateID))
+	}
+	return template, err
+}
+
+func (r *Repository) GetVersion(templateID string, version int) (*domainTemplate.Template, error) {
+	row := r.db.QueryRow(`
+		SELECT `+templateColumns+` FROM message_templates t
+		JOIN message_template_versions v ON v.template_id = t.id
+		WHERE t.id = ? AND v.version = ?`, templateID, version)
+
+	template, err := scanTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("version %d of template %s not found", version, templateID))
+	}
+	return template, err
+}
+
+func (r *Repository) ListTemplates(filter domainTemplate.Filter) ([]domainTemplate.Template, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	query := `SELECT ` + templateColumns + ` FROM message_templates t
+		JOIN message_template_versions v ON v.template_id = t.id AND v.version = t.version`
+	var args []any
+	if filter.Type != "" {
+		query += ` WHERE t.type = ?`
+		args = append(args, filter.Type)
+	}
+	query += ` ORDER BY t.name, t.created_at LIMIT ? OFFSET ?`
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]domainTemplate.Template, 0)
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *Repository) ListVersions(templateID string) ([]domainTemplate.Version, error) {
+	rows, err := r.db.Query(`
+		SELECT template_id, version, default_language, variants_json, created_at FROM message_template_versions
+		WHERE template_id = ? ORDER BY version DESC`, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]domainTemplate.Version, 0)
+	for rows.Next() {
+		var (
+			version  domainTemplate.Version
+			variants string
+		)
+		if err := rows.Scan(&version.TemplateID, &version.Version, &version.DefaultLanguage, &variants, &version.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template version: %w", err)
+		}
+		if err := json.Unmarshal([]byte(variants), &version.Variants); err != nil {
+			return nil, fmt.Errorf("failed to decode template variants: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (r *Repository) DeleteTemplate(templateID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM message_templates WHERE id = ?`, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("template %s not found", templateID))
+	}
+	if _, err = tx.Exec(`DELETE FROM message_template_versions WHERE template_id = ?`, templateID); err != nil {
+		return fmt.Errorf("failed to delete template versions: %w", err)
+	}
+	return tx.Commit()
+}
+
+func scanTemplate(scanner interface{ Scan(...any) error }) (*domainTemplate.Template, error) {
+	var (
+		template domainTemplate.Template
+		variants string
+	)
+	err := scanner.Scan(&template.ID, &template.Name, &template.Type, &template.Version, &template.DefaultLanguage,
+		&variants, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan template: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(variants), &template.Variants); err != nil {
+		return nil, fmt.Errorf("failed to decode template variants: %w", err)
+	}
+	return &template, nil
+}
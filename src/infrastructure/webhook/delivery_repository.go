@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const defaultDeliveryLimit = 50
+
+// DeliveryRepository stores the webhook delivery log, reply action results are kept as JSON
+type DeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) domainWebhook.IDeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+func (r *DeliveryRepository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			url TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			actions_json TEXT NOT NULL DEFAULT '[]',
+			action_error TEXT NOT NULL DEFAULT '',
+			batch_id TEXT NOT NULL DEFAULT '',
+			batch_index INTEGER NOT NULL DEFAULT 0,
+			batch_size INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_batch_id ON webhook_deliveries(batch_id);`)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook_deliveries table: %w", err)
+	}
+	return nil
+}
+
+func (r *DeliveryRepository) CreateDelivery(delivery *domainWebhook.Delivery) error {
+	actions, err := json.Marshal(delivery.Actions)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook delivery actions: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO webhook_deliveries (id, account_id, event_type, url, status, attempts, status_code, error, duration_ms, actions_json, action_error, batch_id, batch_index, batch_size, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.ID, delivery.AccountID, delivery.EventType, delivery.URL, delivery.Status, delivery.Attempts,
+		delivery.StatusCode, delivery.Error, delivery.DurationMs, string(actions), delivery.ActionError,
+		delivery.BatchID, delivery.BatchIndex, delivery.BatchSize, delivery.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *DeliveryRepository) ListDeliveries(filter domainWebhook.DeliveryFilter) ([]domainWebhook.Delivery, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+	if filter.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		args = append(args, filter.EventType)
+	}
+	if filter.BatchID != "" {
+		conditions = append(conditions, "batch_id = ?")
+		args = append(args, filter.BatchID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := `
+		SELECT id, account_id, event_type, url, status, attempts, status_code, error, duration_ms, actions_json, action_error, batch_id, batch_index, batch_size, created_at
+		FROM webhook_deliveries`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domainWebhook.Delivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *DeliveryRepository) GetDelivery(deliveryID string) (*domainWebhook.Delivery, error) {
+	row := r.db.QueryRow(`
+		SELECT id, account_id, event_type, url, status, attempts, status_code, error, duration_ms, actions_json, action_error, batch_id, batch_index, batch_size, created_at
+		FROM webhook_deliveries WHERE id = ?`, deliveryID)
+
+	delivery, err := scanDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("webhook delivery %s not found", deliveryID))
+	}
+	return delivery, err
+}
+
+func (r *DeliveryRepository) DeleteDeliveriesBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM webhook_deliveries WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func scanDelivery(scanner interface{ Scan(...any) error }) (*domainWebhook.Delivery, error) {
+	var (
+		delivery domainWebhook.Delivery
+		actions  string
+	)
+	err := scanner.Scan(
+		&delivery.ID, &delivery.AccountID, &delivery.EventType, &delivery.URL, &delivery.Status, &delivery.Attempts,
+		&delivery.StatusCode, &delivery.Error, &delivery.DurationMs, &actions, &delivery.ActionError,
+		&delivery.BatchID, &delivery.BatchIndex, &delivery.BatchSize, &delivery.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(actions), &delivery.Actions); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook delivery actions: %w", err)
+	}
+	return &delivery, nil
+}
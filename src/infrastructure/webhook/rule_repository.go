@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// RuleRepository stores webhook routing rules, conditions and lists are kept as JSON
+type RuleRepository struct {
+	db *sql.DB
+}
+
+func NewRuleRepository(db *sql.DB) domainWebhook.IRuleRepository {
+	return &RuleRepository{db: db}
+}
+
+func (r *RuleRepository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS webhook_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			match_json TEXT NOT NULL,
+			action TEXT NOT NULL,
+			endpoints_json TEXT NOT NULL DEFAULT '[]',
+			tags_json TEXT NOT NULL DEFAULT '[]',
+			final BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook_rules table: %w", err)
+	}
+	return nil
+}
+
+func (r *RuleRepository) ListRules() ([]domainWebhook.Rule, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, priority, enabled, match_json, action, endpoints_json, tags_json, final, created_at, updated_at
+		FROM webhook_rules ORDER BY priority, created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]domainWebhook.Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *RuleRepository) GetRule(ruleID string) (*domainWebhook.Rule, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, priority, enabled, match_json, action, endpoints_json, tags_json, final, created_at, updated_at
+		FROM webhook_rules WHERE id = ?`, ruleID)
+
+	rule, err := scanRule(row)
+	if err == sql.ErrNoRows {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("webhook rule %s not found", ruleID))
+	}
+	return rule, err
+}
+
+func (r *RuleRepository) CreateRule(rule *domainWebhook.Rule) error {
+	match, endpoints, tags, err := marshalRule(rule)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO webhook_rules (id, name, priority, enabled, match_json, action, endpoints_json, tags_json, final, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID, rule.Name, rule.Priority, rule.Enabled, match, rule.Action, endpoints, tags, rule.Final, rule.CreatedAt, rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook rule: %w", err)
+	}
+	return nil
+}
+
+func (r *RuleRepository) UpdateRule(rule *domainWebhook.Rule) error {
+	match, endpoints, tags, err := marshalRule(rule)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE webhook_rules
+		SET name = ?, priority = ?, enabled = ?, match_json = ?, action = ?, endpoints_json = ?, tags_json = ?, final = ?, updated_at = ?
+		WHERE id = ?`,
+		rule.Name, rule.Priority, rule.Enabled, match, rule.Action, endpoints, tags, rule.Final, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook rule: %w", err)
+	}
+	return requireAffected(result, rule.ID)
+}
+
+func (r *RuleRepository) DeleteRule(ruleID string) error {
+	result, err := r.db.Exec(`DELETE FROM webhook_rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook rule: %w", err)
+	}
+	return requireAffected(result, ruleID)
+}
+
+func requireAffected(result sql.Result, ruleID string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("webhook rule %s not found", ruleID))
+	}
+	return nil
+}
+
+func marshalRule(rule *domainWebhook.Rule) (match, endpoints, tags string, err error) {
+	values := []any{rule.Match, rule.Endpoints, rule.Tags}
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		body, err := json.Marshal(value)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to encode webhook rule: %w", err)
+		}
+		encoded[i] = string(body)
+	}
+	return encoded[0], encoded[1], encoded[2], nil
+}
+
+func scanRule(scanner interface{ Scan(...any) error }) (*domainWebhook.Rule, error) {
+	var (
+		rule                   domainWebhook.Rule
+		match, endpoints, tags string
+	)
+	err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Priority, &rule.Enabled, &match, &rule.Action,
+		&endpoints, &tags, &rule.Final, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook rule: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(match), &rule.Match); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook rule match: %w", err)
+	}
+	if err := json.Unmarshal([]byte(endpoints), &rule.Endpoints); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook rule endpoints: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tags), &rule.Tags); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook rule tags: %w", err)
+	}
+	return &rule, nil
+}
@@ -5,46 +5,55 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 // forwardDeleteToWebhook sends a delete event to webhook
-func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) error {
+func forwardDeleteToWebhook(ctx context.Context, accountID string, evt *events.DeleteForMe, message *domainChatStorage.Message) error {
 	payload, err := createDeletePayload(ctx, evt, message)
 	if err != nil {
 		return err
 	}
 
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "delete event")
+	return forwardPayload(ctx, domainWebhook.Subject{
+		AccountID: accountID,
+		ChatJID:   evt.ChatJID.String(),
+		SenderJID: evt.SenderJID.String(),
+	}, payload)
 }
 
 // createDeletePayload creates a webhook payload for delete events
-func createDeletePayload(_ context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) (map[string]any, error) {
-	body := make(map[string]any)
-
-	// Basic delete event information
-	body["action"] = "event.delete_for_me"
-	body["deleted_message_id"] = evt.MessageID
-	body["sender_id"] = evt.SenderJID.User
-	body["timestamp"] = time.Now().Format(time.RFC3339)
+func createDeletePayload(_ context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) (*domainWebhook.DeleteForMePayload, error) {
+	body := &domainWebhook.DeleteForMePayload{
+		Meta:             domainWebhook.NewMeta(domainWebhook.EventMessageDeleteForMe),
+		Action:           "event.delete_for_me",
+		DeletedMessageID: evt.MessageID,
+		SenderJID:        evt.SenderJID.String(),
+		SenderID:         evt.SenderJID.User,
+		Timestamp:        time.Now().Format(time.RFC3339),
+	}
 
 	// Include original message information if available
 	if message != nil {
-		body["chat_id"] = message.ChatJID
-		body["original_content"] = message.Content
-		body["original_sender"] = message.Sender
-		body["original_timestamp"] = message.Timestamp.Format(time.RFC3339)
-		body["was_from_me"] = message.IsFromMe
+		body.DeletedMessage = &domainWebhook.DeletedMessage{
+			ChatJID:           message.ChatJID,
+			ChatID:            message.ChatJID,
+			OriginalContent:   message.Content,
+			OriginalSender:    message.Sender,
+			OriginalTimestamp: message.Timestamp.Format(time.RFC3339),
+			WasFromMe:         message.IsFromMe,
+		}
 
 		if message.MediaType != "" {
-			body["original_media_type"] = message.MediaType
-			body["original_filename"] = message.Filename
+			body.OriginalMediaType = message.MediaType
+			body.OriginalFilename = message.Filename
 		}
 	}
 
 	// Parse sender JID for proper formatting
 	if evt.SenderJID.Server != "" {
-		body["from"] = evt.SenderJID.String()
+		body.From = evt.SenderJID.String()
 	}
 
 	return body, nil
@@ -0,0 +1,333 @@
+package whatsapp
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Handler priorities, lower runs first. Leave gaps so new handlers can be placed in between.
+const (
+	PriorityLifecycle = 0
+	PriorityStorage   = 100
+	PriorityRealtime  = 200
+	PriorityMedia     = 300
+	PriorityAutoRead  = 400
+	PriorityAutoReply = 500
+	PriorityScript    = 600
+	PriorityRelay     = 700
+	PriorityForward   = 900
+)
+
+var (
+	eventBus             = eventbus.New()
+	registerHandlersOnce sync.Once
+)
+
+// EventBus returns the bus every WhatsApp client (default device and accounts) dispatches its events to
+func EventBus() *eventbus.Bus {
+	return eventBus
+}
+
+// DispatchEvent runs the registered handlers for an event of a client.
+// accountID is empty for the default device.
+func DispatchEvent(ctx context.Context, accountID string, client *whatsmeow.Client, evt any) {
+	eventBus.Dispatch(ctx, eventbus.Meta{AccountID: accountID, Client: client}, evt)
+}
+
+// EventHandlerStats returns the timing of every registered event handler
+func EventHandlerStats() []eventbus.HandlerStats {
+	return eventBus.Stats()
+}
+
+func defaultDeviceOnly[T any](meta eventbus.Meta, _ T) bool {
+	return meta.IsDefault()
+}
+
+// registerEventHandlers wires the built-in behaviours into the bus
+func registerEventHandlers(bus *eventbus.Bus, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// Lifecycle
+	eventbus.Register(bus, eventbus.Handler[*events.PairSuccess]{
+		Name:     "pair-success",
+		Priority: PriorityLifecycle,
+		Filter:   defaultDeviceOnly[*events.PairSuccess],
+		Handle: func(ctx context.Context, _ eventbus.Meta, evt *events.PairSuccess) error {
+			handlePairSuccess(ctx, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.LoggedOut]{
+		Name:     "remote-logout",
+		Priority: PriorityLifecycle,
+		Filter:   defaultDeviceOnly[*events.LoggedOut],
+		Handle: func(ctx context.Context, _ eventbus.Meta, _ *events.LoggedOut) error {
+			handleLoggedOut(ctx, chatStorageRepo)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.StreamReplaced]{
+		Name:     "stream-replaced",
+		Priority: PriorityLifecycle,
+		Filter:   defaultDeviceOnly[*events.StreamReplaced],
+		Handle: func(context.Context, eventbus.Meta, *events.StreamReplaced) error {
+			os.Exit(0)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Connected]{
+		Name:     "presence-on-connect",
+		Priority: PriorityLifecycle,
+		Handle: func(ctx context.Context, meta eventbus.Meta, _ *events.Connected) error {
+			handleConnectionEvents(ctx, meta.Client)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.PushNameSetting]{
+		Name:     "presence-on-pushname",
+		Priority: PriorityLifecycle,
+		Handle: func(ctx context.Context, meta eventbus.Meta, _ *events.PushNameSetting) error {
+			handleConnectionEvents(ctx, meta.Client)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.AppStateSyncComplete]{
+		Name:     "presence-on-app-state-sync",
+		Priority: PriorityLifecycle,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.AppStateSyncComplete) error {
+			handleAppStateSyncComplete(ctx, meta.Client, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Presence]{
+		Name:     "presence-log",
+		Priority: PriorityLifecycle,
+		Handle: func(ctx context.Context, _ eventbus.Meta, evt *events.Presence) error {
+			handlePresence(ctx, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.AppState]{
+		Name:     "app-state-log",
+		Priority: PriorityLifecycle,
+		Handle: func(ctx context.Context, _ eventbus.Meta, evt *events.AppState) error {
+			handleAppState(ctx, evt)
+			return nil
+		},
+	})
+
+	// Storage
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "message-storage",
+		Priority: PriorityStorage,
+		Handle: func(ctx context.Context, _ eventbus.Meta, evt *events.Message) error {
+			handleMessageStorage(ctx, evt, chatStorageRepo)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "poll-vote",
+		Priority: PriorityStorage,
+		Filter:   isPollVote,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handlePollVote(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.DeleteForMe]{
+		Name:     "delete-for-me",
+		Priority: PriorityStorage,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.DeleteForMe) error {
+			handleDeleteForMe(ctx, meta, evt, chatStorageRepo)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.HistorySync]{
+		Name:     "history-sync",
+		Priority: PriorityStorage,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.HistorySync) error {
+			handleHistorySync(ctx, meta, evt, chatStorageRepo)
+			return nil
+		},
+	})
+
+	// Realtime (websocket)
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "websocket-message",
+		Priority: PriorityRealtime,
+		Handle: func(_ context.Context, meta eventbus.Meta, evt *events.Message) error {
+			PublishMessageEvent(meta.AccountID, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Receipt]{
+		Name:     "websocket-receipt",
+		Priority: PriorityRealtime,
+		Handle: func(_ context.Context, meta eventbus.Meta, evt *events.Receipt) error {
+			PublishReceiptEvent(meta.AccountID, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*domainApp.QRCodeEvent]{
+		Name:     "websocket-qr",
+		Priority: PriorityRealtime,
+		Handle: func(_ context.Context, meta eventbus.Meta, evt *domainApp.QRCodeEvent) error {
+			PublishQRCodeEvent(meta.AccountID, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[any]{
+		Name:     "websocket-connection",
+		Priority: PriorityRealtime,
+		Filter: func(_ eventbus.Meta, evt any) bool {
+			return connectionState(evt) != ""
+		},
+		Handle: func(_ context.Context, meta eventbus.Meta, evt any) error {
+			PublishConnectionEvent(meta.AccountID, connectionState(evt), meta.Client)
+			return nil
+		},
+	})
+
+	// Automations
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "media-download",
+		Priority: PriorityMedia,
+		Filter: func(meta eventbus.Meta, _ *events.Message) bool {
+			return meta.IsDefault() && config.WhatsappAutoDownloadMedia
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleImageMessage(ctx, meta.Client, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "auto-mark-read",
+		Priority: PriorityAutoRead,
+		Filter: func(meta eventbus.Meta, evt *events.Message) bool {
+			return meta.IsDefault() && config.WhatsappAutoMarkRead && !evt.Info.IsFromMe
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleAutoMarkRead(ctx, meta.Client, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "auto-reply",
+		Priority: PriorityAutoReply,
+		Filter: func(meta eventbus.Meta, _ *events.Message) bool {
+			return hasAutoReply(meta)
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleAutoReply(ctx, meta, evt, chatStorageRepo)
+			return nil
+		},
+	})
+
+	// Scripts
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "script-message",
+		Priority: PriorityScript,
+		Filter:   hasScriptRunner[*events.Message],
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleScriptMessage(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Receipt]{
+		Name:     "script-receipt",
+		Priority: PriorityScript,
+		Filter: func(meta eventbus.Meta, evt *events.Receipt) bool {
+			return hasScriptRunner(meta, evt) && isAckReceipt(meta, evt)
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) error {
+			handleScriptReceipt(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.GroupInfo]{
+		Name:     "script-group-info",
+		Priority: PriorityScript,
+		Filter: func(meta eventbus.Meta, evt *events.GroupInfo) bool {
+			return hasScriptRunner(meta, evt) && groupInfoHasChanges(meta, evt)
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.GroupInfo) error {
+			handleScriptGroupInfo(ctx, meta, evt)
+			return nil
+		},
+	})
+
+	// Relay
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "relay-message",
+		Priority: PriorityRelay,
+		Filter:   hasRelayer,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleRelayMessage(ctx, meta, evt)
+			return nil
+		},
+	})
+
+	// Forwarding (webhooks and event sinks)
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "webhook-message",
+		Priority: PriorityForward,
+		Filter: func(_ eventbus.Meta, evt *events.Message) bool {
+			return hasEventSinks() && !strings.Contains(evt.Info.SourceString(), "broadcast")
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+			handleWebhookForward(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Receipt]{
+		Name:     "webhook-receipt",
+		Priority: PriorityForward,
+		Filter:   isAckReceipt,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) error {
+			handleReceipt(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.Receipt]{
+		Name:     "webhook-status-view",
+		Priority: PriorityForward,
+		Filter: func(meta eventbus.Meta, evt *events.Receipt) bool {
+			return hasEventSinks() && isStatusView(meta, evt)
+		},
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) error {
+			handleStatusView(ctx, meta, evt)
+			return nil
+		},
+	})
+	eventbus.Register(bus, eventbus.Handler[*events.GroupInfo]{
+		Name:     "webhook-group-info",
+		Priority: PriorityForward,
+		Filter:   groupInfoHasChanges,
+		Handle: func(ctx context.Context, meta eventbus.Meta, evt *events.GroupInfo) error {
+			handleGroupInfo(ctx, meta, evt)
+			return nil
+		},
+	})
+}
+
+// connectionState maps lifecycle events to the state published on the websocket connection topic
+func connectionState(evt any) string {
+	switch evt.(type) {
+	case *events.Connected:
+		return "connected"
+	case *events.Disconnected:
+		return "disconnected"
+	case *events.PairSuccess:
+		return "paired"
+	case *events.LoggedOut:
+		return "logged_out"
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// historyForwardQueueSize is how many history sync chunks can wait for the forwarding worker before the
+// history sync handler blocks
+const historyForwardQueueSize = 16
+
+var (
+	// historySyncIdleTimeout completes a sync that stopped sending chunks without reporting 100% progress
+	historySyncIdleTimeout = 2 * time.Minute
+
+	historySyncMu sync.Mutex
+	historySyncs  = make(map[string]*historySync)
+
+	historyForwardQueue = make(chan func(), historyForwardQueueSize)
+	historyForwardOnce  sync.Once
+)
+
+// historySync is a running history sync of an account, fed one chunk at a time
+type historySync struct {
+	key       string
+	accountID string
+	syncType  string
+
+	chunkOrder         uint32
+	progress           uint32
+	conversations      int
+	messages           int
+	totalConversations int
+	totalMessages      int
+
+	idle *time.Timer
+}
+
+func (s *historySync) payload(event string) *domainWebhook.HistorySyncPayload {
+	return &domainWebhook.HistorySyncPayload{
+		Meta:               domainWebhook.NewMeta(event),
+		SyncType:           s.syncType,
+		ChunkOrder:         s.chunkOrder,
+		Progress:           s.progress,
+		Conversations:      s.conversations,
+		Messages:           s.messages,
+		TotalConversations: s.totalConversations,
+		TotalMessages:      s.totalMessages,
+		Timestamp:          time.Now().Format(time.RFC3339),
+	}
+}
+
+// forwardHistorySync publishes the history_sync events of a chunk and forwards its messages with is_history set,
+// unless forwarding historic messages is switched off. Only the sync types stored in chat storage are reported.
+// Everything is published in order by a single background worker so large backlogs don't block the event handler.
+func forwardHistorySync(ctx context.Context, meta eventbus.Meta, data *waHistorySync.HistorySync) {
+	if data == nil || !hasEventSinks() {
+		return
+	}
+	switch data.GetSyncType() {
+	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT:
+	default:
+		return
+	}
+
+	var (
+		messages      []*events.Message
+		messageCount  int
+		conversations = data.GetConversations()
+	)
+	for _, conv := range conversations {
+		jid, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			continue
+		}
+		for _, histMsg := range conv.GetMessages() {
+			webMsg := histMsg.GetMessage()
+			if webMsg == nil {
+				continue
+			}
+			messageCount++
+			if config.WhatsappWebhookSkipHistory || meta.Client == nil {
+				continue
+			}
+
+			evt, err := meta.Client.ParseWebMessage(jid, webMsg)
+			if err != nil {
+				logrus.Debugf("Skipping history message %s: %v", webMsg.GetKey().GetID(), err)
+				continue
+			}
+			// Stubs such as group creation notices have no message content
+			if evt.Message == nil || strings.Contains(evt.Info.SourceString(), "broadcast") || skipWebhookMessage(evt) {
+				continue
+			}
+			messages = append(messages, evt)
+		}
+	}
+
+	payloads := trackHistorySync(meta.AccountID, data, len(conversations), messageCount)
+	enqueueHistoryForward(func() {
+		subject := domainWebhook.Subject{AccountID: meta.AccountID}
+		// The started event precedes the messages of the first chunk, progress and completed follow them
+		last := len(payloads) - 1
+		for _, payload := range payloads[:last] {
+			publishHistorySyncEvent(ctx, subject, payload)
+		}
+		for _, evt := range messages {
+			if err := forwardMessageToWebhook(ctx, meta, evt); err != nil {
+				logrus.Errorf("Failed forwarding history message %s to webhook: %v", evt.Info.ID, err)
+			}
+		}
+		publishHistorySyncEvent(ctx, subject, payloads[last])
+	})
+}
+
+// trackHistorySync adds a chunk to the running sync of its account and type and returns the events it causes:
+// started for the first chunk, followed by progress, or completed once WhatsApp reports 100%
+func trackHistorySync(accountID string, data *waHistorySync.HistorySync, conversations, messages int) []*domainWebhook.HistorySyncPayload {
+	syncType := strings.ToLower(data.GetSyncType().String())
+	key := accountID + "/" + syncType
+
+	historySyncMu.Lock()
+	defer historySyncMu.Unlock()
+
+	var payloads []*domainWebhook.HistorySyncPayload
+	current := historySyncs[key]
+	isNew := current == nil
+	if isNew {
+		current = &historySync{key: key, accountID: accountID, syncType: syncType}
+		historySyncs[key] = current
+	}
+
+	current.chunkOrder = data.GetChunkOrder()
+	current.progress = data.GetProgress()
+	current.conversations = conversations
+	current.messages = messages
+	current.totalConversations += conversations
+	current.totalMessages += messages
+
+	if isNew {
+		payloads = append(payloads, current.payload(domainWebhook.EventHistorySyncStarted))
+	}
+	if current.idle != nil {
+		current.idle.Stop()
+	}
+
+	if current.progress >= 100 {
+		delete(historySyncs, key)
+		return append(payloads, current.payload(domainWebhook.EventHistorySyncCompleted))
+	}
+
+	current.idle = time.AfterFunc(historySyncIdleTimeout, func() {
+		completeIdleHistorySync(current)
+	})
+	return append(payloads, current.payload(domainWebhook.EventHistorySyncProgress))
+}
+
+// completeIdleHistorySync publishes completed for a sync that received no chunk within the idle timeout
+func completeIdleHistorySync(idle *historySync) {
+	historySyncMu.Lock()
+	if historySyncs[idle.key] != idle {
+		historySyncMu.Unlock()
+		return
+	}
+	delete(historySyncs, idle.key)
+	payload := idle.payload(domainWebhook.EventHistorySyncCompleted)
+	historySyncMu.Unlock()
+
+	logrus.Infof("History sync %s of %s received no chunk for %s, reporting it as completed", idle.syncType, idle.accountID, historySyncIdleTimeout)
+	enqueueHistoryForward(func() {
+		publishHistorySyncEvent(context.Background(), domainWebhook.Subject{AccountID: idle.accountID}, payload)
+	})
+}
+
+func publishHistorySyncEvent(ctx context.Context, subject domainWebhook.Subject, payload *domainWebhook.HistorySyncPayload) {
+	if err := forwardPayload(ctx, subject, payload); err != nil {
+		logrus.Errorf("Failed forwarding %s to webhook: %v", payload.Event, err)
+	}
+}
+
+// enqueueHistoryForward runs the job on the history forwarding worker, after every job queued before it
+func enqueueHistoryForward(job func()) {
+	historyForwardOnce.Do(func() {
+		go func() {
+			for job := range historyForwardQueue {
+				job()
+			}
+		}()
+	})
+	historyForwardQueue <- job
+}
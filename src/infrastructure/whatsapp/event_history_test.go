@@ -0,0 +1,132 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/proto/waWeb"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func historyChunk(syncType waHistorySync.HistorySync_HistorySyncType, chunkOrder, progress uint32) *waHistorySync.HistorySync {
+	return &waHistorySync.HistorySync{
+		SyncType:   syncType.Enum(),
+		ChunkOrder: proto.Uint32(chunkOrder),
+		Progress:   proto.Uint32(progress),
+	}
+}
+
+func resetHistorySyncs(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		historySyncMu.Lock()
+		defer historySyncMu.Unlock()
+		for key, running := range historySyncs {
+			running.idle.Stop()
+			delete(historySyncs, key)
+		}
+	})
+}
+
+func TestTrackHistorySync(t *testing.T) {
+	resetHistorySyncs(t)
+	bootstrap := waHistorySync.HistorySync_INITIAL_BOOTSTRAP
+
+	steps := []struct {
+		data          *waHistorySync.HistorySync
+		conversations int
+		messages      int
+		wantEvents    []string
+		wantTotal     int
+	}{
+		{historyChunk(bootstrap, 1, 30), 4, 120, []string{domainWebhook.EventHistorySyncStarted, domainWebhook.EventHistorySyncProgress}, 120},
+		{historyChunk(bootstrap, 2, 70), 3, 80, []string{domainWebhook.EventHistorySyncProgress}, 200},
+		{historyChunk(bootstrap, 3, 100), 1, 5, []string{domainWebhook.EventHistorySyncCompleted}, 205},
+		{historyChunk(bootstrap, 1, 50), 2, 10, []string{domainWebhook.EventHistorySyncStarted, domainWebhook.EventHistorySyncProgress}, 10},
+	}
+
+	for i, step := range steps {
+		payloads := trackHistorySync("acc", step.data, step.conversations, step.messages)
+		if len(payloads) != len(step.wantEvents) {
+			t.Fatalf("step %d: got %d events, want %v", i, len(payloads), step.wantEvents)
+		}
+		for j, payload := range payloads {
+			if payload.Event != step.wantEvents[j] {
+				t.Errorf("step %d: event %d = %s, want %s", i, j, payload.Event, step.wantEvents[j])
+			}
+			if payload.SyncType != "initial_bootstrap" || payload.Messages != step.messages || payload.TotalMessages != step.wantTotal {
+				t.Errorf("step %d: unexpected payload %+v", i, payload)
+			}
+		}
+	}
+
+	// Syncs of other accounts and types are tracked separately
+	if payloads := trackHistorySync("other", historyChunk(bootstrap, 1, 10), 1, 1); payloads[0].Event != domainWebhook.EventHistorySyncStarted {
+		t.Errorf("expected a new sync for another account, got %s", payloads[0].Event)
+	}
+	if payloads := trackHistorySync("acc", historyChunk(waHistorySync.HistorySync_RECENT, 1, 10), 1, 1); payloads[0].Event != domainWebhook.EventHistorySyncStarted {
+		t.Errorf("expected a new sync for another sync type, got %s", payloads[0].Event)
+	}
+}
+
+func TestTrackHistorySync_CompletesWhenIdle(t *testing.T) {
+	resetHistorySyncs(t)
+
+	originalTimeout := historySyncIdleTimeout
+	historySyncIdleTimeout = 10 * time.Millisecond
+	defer func() { historySyncIdleTimeout = originalTimeout }()
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://hook"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	events := make(chan *domainWebhook.HistorySyncPayload, 1)
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, body any, _ string) (webhookResponse, error) {
+		events <- body.(*domainWebhook.HistorySyncPayload)
+		return webhookResponse{StatusCode: 200}, nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	trackHistorySync("", historyChunk(waHistorySync.HistorySync_RECENT, 1, 0), 2, 7)
+
+	select {
+	case payload := <-events:
+		if payload.Event != domainWebhook.EventHistorySyncCompleted || payload.TotalConversations != 2 || payload.TotalMessages != 7 {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the idle sync to be reported as completed")
+	}
+
+	historySyncMu.Lock()
+	defer historySyncMu.Unlock()
+	if len(historySyncs) != 0 {
+		t.Errorf("expected the completed sync to be removed, got %v", historySyncs)
+	}
+}
+
+func TestIsHistoryMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		evt  *events.Message
+		want bool
+	}{
+		{name: "live", evt: &events.Message{}, want: false},
+		{name: "history sync", evt: &events.Message{SourceWebMsg: &waWeb.WebMessageInfo{}}, want: true},
+		{name: "unavailable message response", evt: &events.Message{SourceWebMsg: &waWeb.WebMessageInfo{}, UnavailableRequestID: "3EB0"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHistoryMessage(tt.evt); got != tt.want {
+				t.Errorf("isHistoryMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,34 +10,53 @@ import (
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 )
 
 // forwardMessageToWebhook is a helper function to forward message event to webhook url
-func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
-	payload, err := createMessagePayload(ctx, evt)
+func forwardMessageToWebhook(ctx context.Context, meta eventbus.Meta, evt *events.Message) error {
+	payload, err := createMessagePayload(ctx, meta.Client, evt)
 	if err != nil {
 		return err
 	}
 
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message event")
+	return forwardPayload(ctx, domainWebhook.Subject{
+		AccountID:   meta.AccountID,
+		ChatJID:     evt.Info.Chat.String(),
+		SenderJID:   evt.Info.Sender.String(),
+		MessageType: payload.MessageType(),
+		Content:     payload.Content(),
+		MessageID:   evt.Info.ID,
+		IsHistory:   payload.IsHistory,
+	}, payload)
 }
 
-func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]any, error) {
+func createMessagePayload(ctx context.Context, client *whatsmeow.Client, evt *events.Message) (*domainWebhook.MessagePayload, error) {
 	message := utils.BuildEventMessage(evt)
 	waReaction := utils.BuildEventReaction(evt)
-	forwarded := utils.BuildForwarded(evt)
 
-	body := make(map[string]any)
-
-	body["sender_id"] = evt.Info.Sender.User
-	body["chat_id"] = evt.Info.Chat.User
+	body := &domainWebhook.MessagePayload{
+		Meta:      domainWebhook.NewMeta(domainWebhook.EventMessage),
+		SenderJID: evt.Info.Sender.String(),
+		ChatJID:   evt.Info.Chat.String(),
+		SenderID:  evt.Info.Sender.User,
+		ChatID:    evt.Info.Chat.User,
+		Pushname:  evt.Info.PushName,
+		ViewOnce:  evt.IsViewOnce,
+		Forwarded: utils.BuildForwarded(evt),
+		Timestamp: evt.Info.Timestamp.Format(time.RFC3339),
+		IsHistory: isHistoryMessage(evt),
+	}
 
 	if from := evt.Info.SourceString(); from != "" {
-		body["from"] = from
+		body.From = from
 
 		from_user, from_group := from, ""
 		if strings.Contains(from, " in ") {
@@ -46,20 +65,20 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 		}
 
 		if strings.HasSuffix(from_user, "@lid") {
-			body["from_lid"] = from_user
+			body.FromLID = from_user
 			lid, err := types.ParseJID(from_user)
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
 				if !pn.IsEmpty() {
 					if from_group != "" {
-						body["from"] = fmt.Sprintf("%s in %s", pn.String(), from_group)
+						body.From = fmt.Sprintf("%s in %s", pn.String(), from_group)
 					} else {
-						body["from"] = pn.String()
+						body.From = pn.String()
 					}
 				}
 			}
@@ -76,7 +95,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -85,22 +104,15 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 				}
 			}
 		}
-		body["message"] = message
-	}
-	if pushname := evt.Info.PushName; pushname != "" {
-		body["pushname"] = pushname
+		body.Message = &domainWebhook.Message{
+			Text:          message.Text,
+			ID:            message.ID,
+			RepliedID:     message.RepliedId,
+			QuotedMessage: message.QuotedMessage,
+		}
 	}
 	if waReaction.Message != "" {
-		body["reaction"] = waReaction
-	}
-	if evt.IsViewOnce {
-		body["view_once"] = evt.IsViewOnce
-	}
-	if forwarded {
-		body["forwarded"] = forwarded
-	}
-	if timestamp := evt.Info.Timestamp.Format(time.RFC3339); timestamp != "" {
-		body["timestamp"] = timestamp
+		body.Reaction = &domainWebhook.Reaction{Message: waReaction.Message, ID: waReaction.ID}
 	}
 
 	// Handle protocol messages (revoke, etc.)
@@ -109,127 +121,114 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 
 		switch protocolType {
 		case "REVOKE":
-			body["action"] = "message_revoked"
+			body.Event = domainWebhook.EventMessageRevoked
+			body.Action = "message_revoked"
 			if key := protocolMessage.GetKey(); key != nil {
-				body["revoked_message_id"] = key.GetID()
-				body["revoked_from_me"] = key.GetFromMe()
+				body.RevokedMessageID = key.GetID()
+				body.RevokedFromMe = proto.Bool(key.GetFromMe())
 				if key.GetRemoteJID() != "" {
-					body["revoked_chat"] = key.GetRemoteJID()
+					body.RevokedChat = key.GetRemoteJID()
 				}
 			}
 		case "MESSAGE_EDIT":
-			body["action"] = "message_edited"
+			body.Event = domainWebhook.EventMessageEdited
+			body.Action = "message_edited"
 			// Extract the original message ID from the protocol message key
 			if key := protocolMessage.GetKey(); key != nil {
-				body["original_message_id"] = key.GetID()
+				body.OriginalMessageID = key.GetID()
 			}
 			if editedMessage := protocolMessage.GetEditedMessage(); editedMessage != nil {
 				if editedText := editedMessage.GetExtendedTextMessage(); editedText != nil {
-					body["edited_text"] = editedText.GetText()
+					body.EditedText = editedText.GetText()
 				} else if editedConv := editedMessage.GetConversation(); editedConv != "" {
-					body["edited_text"] = editedConv
+					body.EditedText = editedConv
 				}
 			}
 		}
 	}
 
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
-			if err != nil {
-				logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
-				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
-			}
-			body["audio"] = path
-		} else {
-			body["audio"] = map[string]any{
-				"url": audioMedia.GetURL(),
-			}
+		media, err := webhookMedia(ctx, client, evt, "audio", audioMedia)
+		if err != nil {
+			return nil, err
 		}
+		body.Audio = media
 	}
 
 	if contactMessage := evt.Message.GetContactMessage(); contactMessage != nil {
-		body["contact"] = contactMessage
+		body.Contact = contactMessage
 	}
 
 	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
-			if err != nil {
-				logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
-				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
-			}
-			body["document"] = path
-		} else {
-			body["document"] = map[string]any{
-				"url":      documentMedia.GetURL(),
-				"filename": documentMedia.GetFileName(),
-			}
+		media, err := webhookMedia(ctx, client, evt, "document", documentMedia)
+		if err != nil {
+			return nil, err
 		}
+		media.Filename = documentMedia.GetFileName()
+		body.Document = media
 	}
 
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
-			if err != nil {
-				logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
-				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
-			}
-			body["image"] = path
-		} else {
-			body["image"] = map[string]any{
-				"url":     imageMedia.GetURL(),
-				"caption": imageMedia.GetCaption(),
-			}
+		media, err := webhookMedia(ctx, client, evt, "image", imageMedia)
+		if err != nil {
+			return nil, err
 		}
+		media.Caption = imageMedia.GetCaption()
+		body.Image = media
 	}
 
 	if listMessage := evt.Message.GetListMessage(); listMessage != nil {
-		body["list"] = listMessage
+		body.List = listMessage
 	}
 
 	if liveLocationMessage := evt.Message.GetLiveLocationMessage(); liveLocationMessage != nil {
-		body["live_location"] = liveLocationMessage
+		body.LiveLocation = liveLocationMessage
 	}
 
 	if locationMessage := evt.Message.GetLocationMessage(); locationMessage != nil {
-		body["location"] = locationMessage
+		body.Location = locationMessage
 	}
 
 	if orderMessage := evt.Message.GetOrderMessage(); orderMessage != nil {
-		body["order"] = orderMessage
+		body.Order = orderMessage
 	}
 
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
-			if err != nil {
-				logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
-				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
-			}
-			body["sticker"] = path
-		} else {
-			body["sticker"] = map[string]any{
-				"url": stickerMedia.GetURL(),
-			}
+		media, err := webhookMedia(ctx, client, evt, "sticker", stickerMedia)
+		if err != nil {
+			return nil, err
 		}
+		body.Sticker = media
 	}
 
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
-			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
-			if err != nil {
-				logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
-				return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
-			}
-			body["video"] = path
-		} else {
-			body["video"] = map[string]any{
-				"url":     videoMedia.GetURL(),
-				"caption": videoMedia.GetCaption(),
-			}
+		media, err := webhookMedia(ctx, client, evt, "video", videoMedia)
+		if err != nil {
+			return nil, err
 		}
+		media.Caption = videoMedia.GetCaption()
+		body.Video = media
 	}
 
 	return body, nil
 }
+
+// isHistoryMessage reports whether the message was backfilled by a history sync instead of received live
+func isHistoryMessage(evt *events.Message) bool {
+	return evt.SourceWebMsg != nil && evt.UnavailableRequestID == ""
+}
+
+// webhookMedia describes an attachment, downloading it first when auto download is enabled.
+// Backfilled media is never downloaded, a large history sync would otherwise fetch every old attachment.
+func webhookMedia(ctx context.Context, client *whatsmeow.Client, evt *events.Message, kind string, message webhookMediaMessage) (*domainWebhook.Media, error) {
+	if !config.WhatsappAutoDownloadMedia || isHistoryMessage(evt) {
+		return remoteMedia(message), nil
+	}
+
+	extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, message)
+	if err != nil {
+		logrus.Errorf("Failed to download %s from %s: %v", kind, evt.Info.SourceString(), err)
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download %s: %v", kind, err))
+	}
+	return downloadedMedia(extracted)
+}
@@ -0,0 +1,122 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var (
+	pollMu   sync.RWMutex
+	pollRepo domainPoll.IPollRepository
+)
+
+// SetPollRepository enables vote counting for the polls stored in the repository
+func SetPollRepository(repo domainPoll.IPollRepository) {
+	pollMu.Lock()
+	defer pollMu.Unlock()
+	pollRepo = repo
+}
+
+func getPollRepository() domainPoll.IPollRepository {
+	pollMu.RLock()
+	defer pollMu.RUnlock()
+	return pollRepo
+}
+
+func isPollVote(_ eventbus.Meta, evt *events.Message) bool {
+	return evt.Message.GetPollUpdateMessage() != nil && getPollRepository() != nil
+}
+
+// handlePollVote decrypts a vote on a poll sent by the account and stores it as the current choice of the voter.
+// Votes on polls of others, or of another account, are left alone.
+func handlePollVote(ctx context.Context, meta eventbus.Meta, evt *events.Message) {
+	repo := getPollRepository()
+	pollID := evt.Message.GetPollUpdateMessage().GetPollCreationMessageKey().GetID()
+	poll, err := repo.GetPoll(pollID)
+	if err != nil {
+		var notFound pkgError.NotFoundError
+		if !errors.As(err, &notFound) {
+			logrus.Errorf("Failed to load poll %s: %v", pollID, err)
+		}
+		return
+	}
+	if poll.AccountID != meta.AccountID {
+		return
+	}
+
+	vote, err := decryptPollVote(ctx, meta.Client, poll, evt)
+	if err != nil {
+		logrus.Warnf("Failed to decrypt vote %s on poll %s: %v", evt.Info.ID, pollID, err)
+		return
+	}
+
+	stored := &domainPoll.Vote{
+		MessageID: poll.MessageID,
+		VoterJID:  evt.Info.Sender.ToNonAD().String(),
+		Options:   poll.OptionNames(vote.GetSelectedOptions()),
+		VotedAt:   evt.Info.Timestamp.UTC(),
+	}
+	changed, err := repo.PutVote(stored)
+	if err != nil {
+		logrus.Errorf("Failed to store vote on poll %s: %v", pollID, err)
+		return
+	}
+	if !changed || !hasEventSinks() {
+		return
+	}
+
+	go func() {
+		err := forwardPayload(ctx, domainWebhook.Subject{
+			AccountID: meta.AccountID,
+			ChatJID:   poll.ChatJID,
+			SenderJID: stored.VoterJID,
+		}, createPollVotePayload(poll, stored))
+		if err != nil {
+			logrus.Errorf("Failed to forward poll vote event to webhook: %v", err)
+		}
+	}()
+}
+
+// decryptPollVote decrypts a vote with the secret whatsmeow kept for the poll. When whatsmeow lost it, for example
+// with a separate keys database that was reset, the secret stored with the poll is put back first.
+func decryptPollVote(ctx context.Context, client *whatsmeow.Client, poll *domainPoll.Poll, evt *events.Message) (*waE2E.PollVoteMessage, error) {
+	vote, err := client.DecryptPollVote(ctx, evt)
+	if err == nil || !errors.Is(err, whatsmeow.ErrOriginalMessageSecretNotFound) || client.Store.ID == nil {
+		return vote, err
+	}
+
+	inserts := []store.MessageSecretInsert{{Chat: evt.Info.Chat, Sender: client.Store.ID.ToNonAD(), ID: poll.MessageID, Secret: poll.Secret}}
+	if !client.Store.LID.IsEmpty() {
+		inserts = append(inserts, store.MessageSecretInsert{Chat: evt.Info.Chat, Sender: client.Store.LID.ToNonAD(), ID: poll.MessageID, Secret: poll.Secret})
+	}
+	if err := client.Store.MsgSecrets.PutMessageSecrets(ctx, inserts); err != nil {
+		return nil, err
+	}
+	return client.DecryptPollVote(ctx, evt)
+}
+
+// createPollVotePayload creates a webhook payload for a changed vote
+func createPollVotePayload(poll *domainPoll.Poll, vote *domainPoll.Vote) *domainWebhook.PollVotePayload {
+	return &domainWebhook.PollVotePayload{
+		Meta:      domainWebhook.NewMeta(domainWebhook.EventPollVote),
+		Timestamp: vote.VotedAt.Format(time.RFC3339),
+		Payload: domainWebhook.PollVoteData{
+			PollID:   poll.MessageID,
+			ChatJID:  poll.ChatJID,
+			VoterJID: vote.VoterJID,
+			Options:  vote.Options,
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -33,41 +34,33 @@ func getReceiptTypeDescription(evt types.ReceiptType) string {
 }
 
 // createReceiptPayload creates a webhook payload for message acknowledgement (receipt) events
-func createReceiptPayload(evt *events.Receipt) map[string]any {
-	body := make(map[string]any)
-
-	// Create payload structure matching the expected format
-	payload := make(map[string]any)
-
-	// Add message ID (use first message ID if multiple)
-	if len(evt.MessageIDs) > 0 {
-		payload["ids"] = evt.MessageIDs
-	}
-
-	// Add from field (the chat where the message was sent)
-	payload["chat_id"] = evt.Chat
-	payload["sender_id"] = evt.Sender
-	payload["from"] = evt.SourceString()
-
+func createReceiptPayload(evt *events.Receipt) *domainWebhook.ReceiptPayload {
+	receiptType := string(evt.Type)
 	if evt.Type == types.ReceiptTypeDelivered {
-		payload["receipt_type"] = "delivered"
-	} else {
-		payload["receipt_type"] = evt.Type
+		receiptType = "delivered"
 	}
-	payload["receipt_type_description"] = getReceiptTypeDescription(evt.Type)
-
-	// Wrap in payload structure
-	body["payload"] = payload
 
-	// Add metadata for webhook processing
-	body["event"] = "message.ack"
-	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
-
-	return body
+	return &domainWebhook.ReceiptPayload{
+		Meta:      domainWebhook.NewMeta(domainWebhook.EventMessageAck),
+		Timestamp: evt.Timestamp.Format(time.RFC3339),
+		Payload: domainWebhook.ReceiptData{
+			IDs:                    evt.MessageIDs,
+			ChatJID:                evt.Chat.String(),
+			SenderJID:              evt.Sender.String(),
+			ChatID:                 evt.Chat.String(),
+			SenderID:               evt.Sender.String(),
+			From:                   evt.SourceString(),
+			ReceiptType:            receiptType,
+			ReceiptTypeDescription: getReceiptTypeDescription(evt.Type),
+		},
+	}
 }
 
 // forwardReceiptToWebhook forwards message acknowledgement events to the configured webhook URLs
-func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt) error {
-	payload := createReceiptPayload(evt)
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message ack event")
+func forwardReceiptToWebhook(ctx context.Context, accountID string, evt *events.Receipt) error {
+	return forwardPayload(ctx, domainWebhook.Subject{
+		AccountID: accountID,
+		ChatJID:   evt.Chat.String(),
+		SenderJID: evt.Sender.String(),
+	}, createReceiptPayload(evt))
 }
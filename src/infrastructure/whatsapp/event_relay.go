@@ -0,0 +1,96 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var relayer atomic.Pointer[domainRelay.IRelay]
+
+// SetRelayer sets what relays incoming messages to other chats
+func SetRelayer(relay domainRelay.IRelay) {
+	relayer.Store(&relay)
+}
+
+func hasRelayer(eventbus.Meta, *events.Message) bool {
+	return relayer.Load() != nil
+}
+
+// handleRelayMessage hands incoming messages to the relay rules. Messages sent by the account itself are
+// skipped, which covers what the relay sends, and media is only downloaded when a rule relays it.
+func handleRelayMessage(ctx context.Context, meta eventbus.Meta, evt *events.Message) {
+	relay := relayer.Load()
+	if relay == nil || evt.Info.IsFromMe || strings.Contains(evt.Info.SourceString(), "broadcast") || isHistoryMessage(evt) {
+		return
+	}
+
+	message := domainRelay.Incoming{
+		AccountID: meta.AccountID,
+		ChatJID:   evt.Info.Chat.String(),
+		SenderJID: evt.Info.Sender.ToNonAD().String(),
+		PushName:  evt.Info.PushName,
+		MessageID: evt.Info.ID,
+	}
+	if message.AccountID == "" {
+		message.AccountID = domainEventSink.DefaultAccountID
+	}
+
+	var (
+		contextInfo  *waE2E.ContextInfo
+		downloadable whatsmeow.DownloadableMessage
+	)
+	msg := evt.Message
+	switch {
+	case msg.GetConversation() != "":
+		message.Text = msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		message.Text = msg.GetExtendedTextMessage().GetText()
+		contextInfo = msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		image := msg.GetImageMessage()
+		message.Text, contextInfo, downloadable = image.GetCaption(), image.GetContextInfo(), image
+		message.Media = &domainRelay.Media{Type: domainSend.RelayMediaImage, MimeType: image.GetMimetype()}
+	case msg.GetVideoMessage() != nil:
+		video := msg.GetVideoMessage()
+		message.Text, contextInfo, downloadable = video.GetCaption(), video.GetContextInfo(), video
+		message.Media = &domainRelay.Media{Type: domainSend.RelayMediaVideo, MimeType: video.GetMimetype()}
+	case msg.GetAudioMessage() != nil:
+		audio := msg.GetAudioMessage()
+		contextInfo, downloadable = audio.GetContextInfo(), audio
+		message.Media = &domainRelay.Media{Type: domainSend.RelayMediaAudio, MimeType: audio.GetMimetype()}
+	case msg.GetDocumentMessage() != nil:
+		document := msg.GetDocumentMessage()
+		message.Text, contextInfo, downloadable = document.GetCaption(), document.GetContextInfo(), document
+		message.Media = &domainRelay.Media{
+			Type:     domainSend.RelayMediaDocument,
+			MimeType: document.GetMimetype(),
+			FileName: document.GetFileName(),
+		}
+	case msg.GetStickerMessage() != nil:
+		sticker := msg.GetStickerMessage()
+		contextInfo, downloadable = sticker.GetContextInfo(), sticker
+		message.Media = &domainRelay.Media{Type: domainSend.RelayMediaSticker, MimeType: sticker.GetMimetype()}
+	default:
+		// Reactions, polls, protocol messages and the like aren't relayed
+		return
+	}
+
+	message.QuotedMessageID = contextInfo.GetStanzaID()
+	if message.Media != nil {
+		client := meta.Client
+		message.Media.Download = func(ctx context.Context) ([]byte, error) {
+			return client.Download(ctx, downloadable)
+		}
+	}
+
+	go (*relay).Relay(ctx, message)
+}
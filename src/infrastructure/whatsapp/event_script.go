@@ -0,0 +1,105 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var scriptRunner atomic.Pointer[domainScript.IRunner]
+
+// SetScriptRunner sets what runs the scripts of an account on its message, receipt and group events
+func SetScriptRunner(runner domainScript.IRunner) {
+	scriptRunner.Store(&runner)
+}
+
+func hasScriptRunner[T any](eventbus.Meta, T) bool {
+	return scriptRunner.Load() != nil
+}
+
+// runScripts hands the event to the scripts without holding up the event handlers
+func runScripts(ctx context.Context, event domainScript.Event) {
+	runner := scriptRunner.Load()
+	if runner == nil {
+		return
+	}
+	if event.AccountID == "" {
+		event.AccountID = domainEventSink.DefaultAccountID
+	}
+	go (*runner).Run(ctx, event)
+}
+
+// handleScriptMessage runs scripts on incoming messages, messages sent by the account itself are skipped so
+// scripts replying to a chat don't trigger themselves
+func handleScriptMessage(ctx context.Context, meta eventbus.Meta, evt *events.Message) {
+	if evt.Info.IsFromMe || strings.Contains(evt.Info.SourceString(), "broadcast") || isHistoryMessage(evt) {
+		return
+	}
+
+	mediaType, _, _, _, _, _, _ := utils.ExtractMediaInfo(evt.Message)
+	runScripts(ctx, domainScript.Event{
+		Type:      domainScript.EventMessage,
+		AccountID: meta.AccountID,
+		ChatJID:   evt.Info.Chat.String(),
+		SenderJID: evt.Info.Sender.String(),
+		MessageID: evt.Info.ID,
+		Timestamp: evt.Info.Timestamp,
+		Fields: map[string]any{
+			"text":       utils.ExtractMessageTextFromEvent(evt),
+			"push_name":  evt.Info.PushName,
+			"is_group":   evt.Info.IsGroup,
+			"media_type": mediaType,
+		},
+	})
+}
+
+func handleScriptReceipt(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) {
+	receiptType := string(evt.Type)
+	if receiptType == "" {
+		receiptType = "delivered"
+	}
+	runScripts(ctx, domainScript.Event{
+		Type:      domainScript.EventReceipt,
+		AccountID: meta.AccountID,
+		ChatJID:   evt.Chat.String(),
+		SenderJID: evt.Sender.String(),
+		Timestamp: evt.Timestamp,
+		Fields: map[string]any{
+			"message_ids":  evt.MessageIDs,
+			"receipt_type": receiptType,
+		},
+	})
+}
+
+func handleScriptGroupInfo(ctx context.Context, meta eventbus.Meta, evt *events.GroupInfo) {
+	event := domainScript.Event{
+		Type:      domainScript.EventGroup,
+		AccountID: meta.AccountID,
+		ChatJID:   evt.JID.String(),
+		Timestamp: evt.Timestamp,
+		Fields: map[string]any{
+			"join":    jidsToStrings(evt.Join),
+			"leave":   jidsToStrings(evt.Leave),
+			"promote": jidsToStrings(evt.Promote),
+			"demote":  jidsToStrings(evt.Demote),
+			"name":    nil,
+			"topic":   nil,
+		},
+	}
+	if evt.Sender != nil {
+		event.SenderJID = evt.Sender.String()
+	}
+	if evt.Name != nil {
+		event.Fields["name"] = evt.Name.Name
+	}
+	if evt.Topic != nil {
+		event.Fields["topic"] = evt.Topic.Topic
+	}
+	runScripts(ctx, event)
+}
@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// isStatusView reports whether the receipt is a contact viewing a status of the account
+func isStatusView(_ eventbus.Meta, evt *events.Receipt) bool {
+	if evt.Chat != types.StatusBroadcastJID || evt.IsFromMe {
+		return false
+	}
+	return evt.Type == types.ReceiptTypeRead || evt.Type == types.ReceiptTypePlayed
+}
+
+// createStatusViewedPayload creates a webhook payload for a view of statuses of the account
+func createStatusViewedPayload(evt *events.Receipt) *domainWebhook.StatusViewedPayload {
+	return &domainWebhook.StatusViewedPayload{
+		Meta:      domainWebhook.NewMeta(domainWebhook.EventStatusViewed),
+		Timestamp: evt.Timestamp.Format(time.RFC3339),
+		Payload: domainWebhook.StatusViewedData{
+			IDs:       evt.MessageIDs,
+			ViewerJID: evt.Sender.String(),
+			ViewType:  string(evt.Type),
+		},
+	}
+}
+
+func handleStatusView(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) {
+	log.Infof("status %v was viewed by %s at %s", evt.MessageIDs, evt.Sender, evt.Timestamp)
+
+	go func() {
+		err := forwardPayload(ctx, domainWebhook.Subject{
+			AccountID: meta.AccountID,
+			ChatJID:   evt.Chat.String(),
+			SenderJID: evt.Sender.String(),
+		}, createStatusViewedPayload(evt))
+		if err != nil {
+			logrus.Errorf("Failed to forward status view event to webhook: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// PublishMessageEvent pushes a message summary to the websocket hub of an account
+func PublishMessageEvent(accountID string, evt *events.Message) {
+	mediaType, filename, _, _, _, _, _ := utils.ExtractMediaInfo(evt.Message)
+	chatJID := evt.Info.Chat.String()
+
+	websocket.PublishAccount(accountID, websocket.MessageTopic(chatJID), websocket.BroadcastMessage{
+		Code:    "MESSAGE",
+		Message: "Message received",
+		Result: map[string]any{
+			"id":         evt.Info.ID,
+			"chat_jid":   chatJID,
+			"sender_jid": evt.Info.Sender.String(),
+			"pushname":   evt.Info.PushName,
+			"text":       utils.ExtractMessageTextFromEvent(evt),
+			"media_type": mediaType,
+			"filename":   filename,
+			"is_from_me": evt.Info.IsFromMe,
+			"is_group":   evt.Info.IsGroup,
+			"timestamp":  evt.Info.Timestamp.Format(time.RFC3339),
+		},
+	})
+}
+
+// PublishReceiptEvent pushes a receipt to the websocket hub of an account
+func PublishReceiptEvent(accountID string, evt *events.Receipt) {
+	chatJID := evt.Chat.String()
+
+	websocket.PublishAccount(accountID, websocket.ReceiptTopic(chatJID), websocket.BroadcastMessage{
+		Code:    "RECEIPT",
+		Message: "Receipt received",
+		Result: map[string]any{
+			"ids":          evt.MessageIDs,
+			"chat_jid":     chatJID,
+			"sender_jid":   evt.Sender.String(),
+			"receipt_type": string(evt.Type),
+			"timestamp":    evt.Timestamp.Format(time.RFC3339),
+		},
+	})
+}
+
+// PublishQRCodeEvent pushes a new login QR code to the websocket hub of an account
+func PublishQRCodeEvent(accountID string, evt *domainApp.QRCodeEvent) {
+	websocket.PublishAccount(accountID, websocket.TopicQR, websocket.BroadcastMessage{
+		Code:    "QR_CODE",
+		Message: "QR code generated",
+		Result: map[string]any{
+			"qr_link":  evt.QRLink,
+			"code":     evt.Code,
+			"duration": evt.Duration,
+		},
+	})
+}
+
+// PublishConnectionEvent pushes the connection state of a client to the websocket hub of an account
+func PublishConnectionEvent(accountID string, state string, client *whatsmeow.Client) {
+	var (
+		isConnected, isLoggedIn bool
+		deviceID                string
+	)
+	if client != nil {
+		isConnected = client.IsConnected()
+		isLoggedIn = client.IsLoggedIn()
+		if client.Store != nil && client.Store.ID != nil {
+			deviceID = client.Store.ID.String()
+		}
+	}
+
+	websocket.PublishAccount(accountID, websocket.TopicConnection, websocket.BroadcastMessage{
+		Code:    "CONNECTION_STATE",
+		Message: state,
+		Result: map[string]any{
+			"state":        state,
+			"is_connected": isConnected,
+			"is_logged_in": isLoggedIn,
+			"device_id":    deviceID,
+		},
+	})
+}
@@ -13,8 +13,11 @@ import (
 	"go.mau.fi/whatsmeow/proto/waHistorySync"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/sirupsen/logrus"
@@ -44,6 +47,9 @@ var (
 	log           waLog.Logger
 	historySyncID int32
 	startupTime   = time.Now().Unix()
+
+	autoReplyResponder atomic.Pointer[domainAutoReply.IResponder]
+	flowResponder      atomic.Pointer[domainAutoReply.IResponder]
 )
 
 // InitWaDB initializes the WhatsApp database connection
@@ -136,12 +142,16 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	}
 
 	// Create and configure the client
-	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
-	cli.EnableAutoReconnect = true
-	cli.AutoTrustIdentity = true
+	client := whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
+	client.EnableAutoReconnect = true
+	client.AutoTrustIdentity = true
+	cli = client
 
-	cli.AddEventHandler(func(rawEvt interface{}) {
-		handler(ctx, rawEvt, chatStorageRepo)
+	registerHandlersOnce.Do(func() {
+		registerEventHandlers(eventBus, chatStorageRepo)
+	})
+	client.AddEventHandler(func(rawEvt interface{}) {
+		DispatchEvent(ctx, "", client, rawEvt)
 	})
 
 	return cli
@@ -440,39 +450,9 @@ func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.I
 	logrus.Info("[REMOTE_LOGOUT] Remote logout cleanup completed successfully")
 }
 
-// handler is the main event handler for WhatsApp events
-func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	switch evt := rawEvt.(type) {
-	case *events.DeleteForMe:
-		handleDeleteForMe(ctx, evt, chatStorageRepo)
-	case *events.AppStateSyncComplete:
-		handleAppStateSyncComplete(ctx, evt)
-	case *events.PairSuccess:
-		handlePairSuccess(ctx, evt)
-	case *events.LoggedOut:
-		handleLoggedOut(ctx, chatStorageRepo)
-	case *events.Connected, *events.PushNameSetting:
-		handleConnectionEvents(ctx)
-	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
-	case *events.Message:
-		handleMessage(ctx, evt, chatStorageRepo)
-	case *events.Receipt:
-		handleReceipt(ctx, evt)
-	case *events.Presence:
-		handlePresence(ctx, evt)
-	case *events.HistorySync:
-		handleHistorySync(ctx, evt, chatStorageRepo)
-	case *events.AppState:
-		handleAppState(ctx, evt)
-	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt)
-	}
-}
-
 // Event handler functions
 
-func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleDeleteForMe(ctx context.Context, meta eventbus.Meta, evt *events.DeleteForMe, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	log.Infof("Deleted message %s for %s", evt.MessageID, evt.SenderJID.String())
 
 	// Find the message to get its chat JID
@@ -495,18 +475,18 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
+	if hasEventSinks() {
 		go func() {
-			if err := forwardDeleteToWebhook(ctx, evt, message); err != nil {
+			if err := forwardDeleteToWebhook(ctx, meta.AccountID, evt, message); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
 			}
 		}()
 	}
 }
 
-func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
-	if len(cli.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
-		if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+func handleAppStateSyncComplete(_ context.Context, client *whatsmeow.Client, evt *events.AppStateSyncComplete) {
+	if len(client.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
+		if err := client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 			log.Warnf("Failed to send available presence: %v", err)
 		} else {
 			log.Infof("Marked self as available")
@@ -515,10 +495,10 @@ func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncCompl
 }
 
 func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
-	websocket.Broadcast <- websocket.BroadcastMessage{
+	websocket.Broadcast(websocket.BroadcastMessage{
 		Code:    "LOGIN_SUCCESS",
 		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
-	}
+	})
 	syncKeysDevice(ctx, db, keysDB)
 }
 
@@ -529,21 +509,21 @@ func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.ICha
 	handleRemoteLogout(ctx, chatStorageRepo)
 
 	// Broadcast final notification that cleanup is complete and ready for new login
-	websocket.Broadcast <- websocket.BroadcastMessage{
+	websocket.Broadcast(websocket.BroadcastMessage{
 		Code:    "LOGOUT_COMPLETE",
 		Message: "Remote logout cleanup completed - ready for new login",
 		Result:  nil,
-	}
+	})
 }
 
-func handleConnectionEvents(_ context.Context) {
-	if len(cli.Store.PushName) == 0 {
+func handleConnectionEvents(_ context.Context, client *whatsmeow.Client) {
+	if len(client.Store.PushName) == 0 {
 		return
 	}
 
 	// Send presence available when connecting and when the pushname is changed.
 	// This makes sure that outgoing messages always have the right pushname.
-	if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+	if err := client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 		log.Warnf("Failed to send available presence: %v", err)
 	} else {
 		log.Infof("Marked self as available")
@@ -554,7 +534,7 @@ func handleStreamReplaced(_ context.Context) {
 	os.Exit(0)
 }
 
-func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleMessageStorage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Log message metadata
 	metaParts := buildMessageMetaParts(evt)
 	log.Infof("Received message %s from %s (%s): %+v",
@@ -568,18 +548,6 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
-
-	// Handle image message if present
-	handleImageMessage(ctx, evt)
-
-	// Auto-mark message as read if configured
-	handleAutoMarkRead(ctx, evt)
-
-	// Handle auto-reply if configured
-	handleAutoReply(ctx, evt, chatStorageRepo)
-
-	// Forward to webhook if configured
-	handleWebhookForward(ctx, evt)
 }
 
 func buildMessageMetaParts(evt *events.Message) []string {
@@ -599,12 +567,9 @@ func buildMessageMetaParts(evt *events.Message) []string {
 	return metaParts
 }
 
-func handleImageMessage(ctx context.Context, evt *events.Message) {
-	if !config.WhatsappAutoDownloadMedia {
-		return
-	}
+func handleImageMessage(ctx context.Context, client *whatsmeow.Client, evt *events.Message) {
 	if img := evt.Message.GetImageMessage(); img != nil {
-		if path, err := utils.ExtractMedia(ctx, cli, config.PathStorages, img); err != nil {
+		if path, err := utils.ExtractMedia(ctx, client, config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
 			log.Infof("Image downloaded to %s", path)
@@ -612,37 +577,47 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoMarkRead(_ context.Context, evt *events.Message) {
-	// Only mark read if auto-mark read is enabled and message is incoming
-	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
-		return
-	}
-
+func handleAutoMarkRead(_ context.Context, client *whatsmeow.Client, evt *events.Message) {
 	// Mark the message as read
 	messageIDs := []types.MessageID{evt.Info.ID}
 	timestamp := time.Now()
 	chat := evt.Info.Chat
 	sender := evt.Info.Sender
 
-	if err := cli.MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
+	if err := client.MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
 	}
 }
 
-func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	if config.WhatsappAutoReplyMessage == "" {
-		return
-	}
+// SetAutoReplyResponder sets what answers incoming messages with the auto-reply rules, greetings and away
+// messages. The static --autoreply message is only sent when the responder didn't answer.
+func SetAutoReplyResponder(responder domainAutoReply.IResponder) {
+	autoReplyResponder.Store(&responder)
+}
+
+// SetFlowResponder sets what advances the flow sessions of contacts. It answers before the auto-reply rules,
+// which stay silent for contacts in a flow or handed off to a human.
+func SetFlowResponder(responder domainAutoReply.IResponder) {
+	flowResponder.Store(&responder)
+}
 
-	// Skip groups, broadcasts, and self messages
-	if utils.IsGroupJID(evt.Info.Chat.String()) || evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
+// hasAutoReply reports whether incoming messages of a client can be answered automatically, the --autoreply
+// message only answers on the default device
+func hasAutoReply(meta eventbus.Meta) bool {
+	return (meta.IsDefault() && config.WhatsappAutoReplyMessage != "") || autoReplyResponder.Load() != nil || flowResponder.Load() != nil
+}
+
+func handleAutoReply(ctx context.Context, meta eventbus.Meta, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// Skip broadcasts and self messages
+	if evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
 		return
 	}
 
-	// Only reply to direct 1:1 chats (e.g., *@s.whatsapp.net)
-	if evt.Info.Chat.Server != types.DefaultUserServer {
+	// Only reply to direct 1:1 chats (e.g., *@s.whatsapp.net), groups are left to rules scoped to them
+	isGroup := utils.IsGroupJID(evt.Info.Chat.String())
+	if !isGroup && evt.Info.Chat.Server != types.DefaultUserServer {
 		return
 	}
 
@@ -654,9 +629,49 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 		return
 	}
 
-	// Require actual typed text (not captions or synthetic labels)
-	hasText := false
+	// Rules need actual typed text (not captions or synthetic labels), greetings and away messages also
+	// answer media sent in 1:1 chats
+	text := autoReplyText(evt)
+	if text == "" {
+		if isGroup {
+			return
+		}
+		if mediaType, _, _, _, _, _, _ := utils.ExtractMediaInfo(evt.Message); mediaType == "" {
+			return
+		}
+	}
 
+	accountID := meta.AccountID
+	if accountID == "" {
+		accountID = domainEventSink.DefaultAccountID
+	}
+	incoming := domainAutoReply.Incoming{
+		AccountID: accountID,
+		ChatJID:   evt.Info.Chat.String(),
+		SenderJID: evt.Info.Sender.String(),
+		PushName:  evt.Info.PushName,
+		MessageID: evt.Info.ID,
+		Text:      text,
+		IsGroup:   isGroup,
+		Timestamp: evt.Info.Timestamp,
+	}
+
+	// Flows answer first, then the rules, greetings and away messages, and the --autoreply message last.
+	// Replies may download media, so they don't hold up the event handlers
+	go func() {
+		for _, responder := range []*domainAutoReply.IResponder{flowResponder.Load(), autoReplyResponder.Load()} {
+			if responder != nil && (*responder).Respond(ctx, incoming) {
+				return
+			}
+		}
+		if meta.IsDefault() && !isGroup && text != "" && config.WhatsappAutoReplyMessage != "" {
+			sendStaticAutoReply(ctx, meta.Client, evt, chatStorageRepo)
+		}
+	}()
+}
+
+// autoReplyText returns the typed text of a message, empty for captions, media and other content
+func autoReplyText(evt *events.Message) string {
 	// Unwrap FutureProof wrappers to access the inner message content first
 	innerMsg := evt.Message
 	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
@@ -681,27 +696,28 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 
 	// Check for genuine typed text on the unwrapped content
 	if conv := innerMsg.GetConversation(); conv != "" {
-		hasText = true
+		return conv
 	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-		hasText = true
+		return ext.GetText()
 	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
 		if edited := protoMsg.GetEditedMessage(); edited != nil {
 			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-				hasText = true
+				return ext.GetText()
 			} else if conv := edited.GetConversation(); conv != "" {
-				hasText = true
+				return conv
 			}
 		}
 	}
-	if !hasText {
-		return
-	}
+	return ""
+}
 
+// sendStaticAutoReply answers with the --autoreply message
+func sendStaticAutoReply(ctx context.Context, client *whatsmeow.Client, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Format recipient JID
 	recipientJID := utils.FormatJID(evt.Info.Sender.String())
 
 	// Send the auto-reply message
-	response, err := cli.SendMessage(
+	response, err := client.SendMessage(
 		ctx,
 		recipientJID,
 		&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
@@ -716,8 +732,8 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	if chatStorageRepo != nil {
 		// Get our own JID as sender
 		senderJID := ""
-		if cli.Store.ID != nil {
-			senderJID = cli.Store.ID.String()
+		if client.Store.ID != nil {
+			senderJID = client.Store.ID.String()
 		}
 
 		// Store the sent auto-reply message
@@ -728,6 +744,7 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 			recipientJID.String(),           // Recipient JID
 			config.WhatsappAutoReplyMessage, // Auto-reply content
 			response.Timestamp,              // Timestamp from response
+			nil,                             // Text only, no media to keep
 		); err != nil {
 			// Log storage error but don't fail the auto-reply
 			log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
@@ -737,43 +754,52 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 }
 
-func handleWebhookForward(ctx context.Context, evt *events.Message) {
-	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
+func handleWebhookForward(ctx context.Context, meta eventbus.Meta, evt *events.Message) {
+	if skipWebhookMessage(evt) {
+		return
+	}
+
+	go func(evt *events.Message) {
+		if err := forwardMessageToWebhook(ctx, meta, evt); err != nil {
+			logrus.Error("Failed forward to webhook: ", err)
+		}
+	}(evt)
+}
+
+// skipWebhookMessage reports whether a message is a protocol message that shouldn't trigger webhooks
+func skipWebhookMessage(evt *events.Message) bool {
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
 		protocolType := protocolMessage.GetType().String()
 		// Skip EPHEMERAL_SYNC_RESPONSE but allow REVOKE and MESSAGE_EDIT
 		if protocolType == "EPHEMERAL_SYNC_RESPONSE" {
 			log.Debugf("Skipping webhook for EPHEMERAL_SYNC_RESPONSE message")
-			return
+			return true
 		}
 	}
+	return false
+}
 
-	if len(config.WhatsappWebhook) > 0 &&
-		!strings.Contains(evt.Info.SourceString(), "broadcast") {
-		go func(evt *events.Message) {
-			if err := forwardMessageToWebhook(ctx, evt); err != nil {
-				logrus.Error("Failed forward to webhook: ", err)
-			}
-		}(evt)
+// isAckReceipt reports whether the receipt is forwarded as a message.ack event
+func isAckReceipt(_ eventbus.Meta, evt *events.Receipt) bool {
+	switch evt.Type {
+	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf, types.ReceiptTypeDelivered:
+		return true
 	}
+	return false
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt) {
-	sendReceipt := false
-	switch evt.Type {
-	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
-		sendReceipt = true
-		log.Infof("%v was read by %s at %s: %+v", evt.MessageIDs, evt.SourceString(), evt.Timestamp, evt)
-	case types.ReceiptTypeDelivered:
-		sendReceipt = true
+func handleReceipt(ctx context.Context, meta eventbus.Meta, evt *events.Receipt) {
+	if evt.Type == types.ReceiptTypeDelivered {
 		log.Infof("%s was delivered to %s at %s: %+v", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp, evt)
+	} else {
+		log.Infof("%v was read by %s at %s: %+v", evt.MessageIDs, evt.SourceString(), evt.Timestamp, evt)
 	}
 
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
+	if hasEventSinks() {
 		go func(e *events.Receipt) {
-			if err := forwardReceiptToWebhook(ctx, e); err != nil {
+			if err := forwardReceiptToWebhook(ctx, meta.AccountID, e); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
 			}
 		}(evt)
@@ -792,12 +818,13 @@ func handlePresence(_ context.Context, evt *events.Presence) {
 	}
 }
 
-func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleHistorySync(ctx context.Context, meta eventbus.Meta, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	client := meta.Client
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
 		startupTime,
-		cli.Store.ID.String(),
+		client.Store.ID.String(),
 		id,
 		evt.Data.SyncType.String(),
 	)
@@ -820,10 +847,12 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 
 	// Process history sync data to database
 	if chatStorageRepo != nil {
-		if err := processHistorySync(ctx, evt.Data, chatStorageRepo); err != nil {
+		if err := processHistorySync(ctx, client, evt.Data, chatStorageRepo); err != nil {
 			log.Errorf("Failed to process history sync to database: %v", err)
 		}
 	}
+
+	forwardHistorySync(ctx, meta, evt.Data)
 }
 
 func handleAppState(_ context.Context, evt *events.AppState) {
@@ -831,7 +860,7 @@ func handleAppState(_ context.Context, evt *events.AppState) {
 }
 
 // processHistorySync processes history sync data and stores messages in the database
-func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func processHistorySync(ctx context.Context, client *whatsmeow.Client, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	if data == nil {
 		return nil
 	}
@@ -842,7 +871,7 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 	switch syncType {
 	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT:
 		// Process conversation messages
-		return processConversationMessages(ctx, data, chatStorageRepo)
+		return processConversationMessages(ctx, client, data, chatStorageRepo)
 	case waHistorySync.HistorySync_PUSH_NAME:
 		// Process push names to update chat names
 		return processPushNames(ctx, data, chatStorageRepo)
@@ -854,7 +883,7 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 }
 
 // processConversationMessages processes and stores conversation messages from history sync
-func processConversationMessages(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func processConversationMessages(_ context.Context, client *whatsmeow.Client, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	conversations := data.GetConversations()
 	log.Infof("Processing %d conversations from history sync", len(conversations))
 
@@ -907,6 +936,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 			// Extract message content and media info
 			content := utils.ExtractMessageTextFromProto(msg.GetMessage())
 			mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(msg.GetMessage())
+			mimetype, directPath := utils.ExtractMediaDetails(msg.GetMessage())
 
 			// Skip if there's no content and no media
 			if content == "" && mediaType == "" {
@@ -918,8 +948,8 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 			isFromMe := msgKey.GetFromMe()
 			if isFromMe {
 				// For self-messages, use the full JID format to match regular message processing
-				if cli.Store.ID != nil {
-					sender = cli.Store.ID.String() // Use full JID instead of just User part
+				if client.Store.ID != nil {
+					sender = client.Store.ID.String() // Use full JID instead of just User part
 				} else {
 					// Skip messages where we can't determine the sender to avoid NOT NULL violations
 					log.Warnf("Skipping self-message %s: client ID unavailable", messageID)
@@ -957,19 +987,22 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 
 			// Create message object and add to batch
 			message := &domainChatStorage.Message{
-				ID:            messageID,
-				ChatJID:       chatJID,
-				Sender:        sender,
-				Content:       content,
-				Timestamp:     timestamp,
-				IsFromMe:      isFromMe,
-				MediaType:     mediaType,
-				Filename:      filename,
-				URL:           url,
-				MediaKey:      mediaKey,
-				FileSHA256:    fileSHA256,
-				FileEncSHA256: fileEncSHA256,
-				FileLength:    fileLength,
+				ID:              messageID,
+				ChatJID:         chatJID,
+				Sender:          sender,
+				Content:         content,
+				Timestamp:       timestamp,
+				IsFromMe:        isFromMe,
+				MediaType:       mediaType,
+				Filename:        filename,
+				URL:             url,
+				MediaKey:        mediaKey,
+				FileSHA256:      fileSHA256,
+				FileEncSHA256:   fileEncSHA256,
+				FileLength:      fileLength,
+				Mimetype:        mimetype,
+				DirectPath:      directPath,
+				ForwardingScore: utils.ExtractForwardingScore(msg.GetMessage()),
 			}
 
 			messageBatch = append(messageBatch, message)
@@ -1036,15 +1069,13 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 	return nil
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
-	// Only process events that have actual changes
-	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
+// groupInfoHasChanges skips group info events without actual changes
+func groupInfoHasChanges(_ eventbus.Meta, evt *events.GroupInfo) bool {
+	return len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
 		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
+}
 
-	if !hasChanges {
-		return
-	}
-
+func handleGroupInfo(ctx context.Context, meta eventbus.Meta, evt *events.GroupInfo) {
 	// Log group events for debugging
 	if len(evt.Join) > 0 {
 		log.Infof("Group %s: %d users joined at %s", evt.JID, len(evt.Join), evt.Timestamp)
@@ -1060,9 +1091,9 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
+	if hasEventSinks() {
 		go func(e *events.GroupInfo) {
-			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
+			if err := forwardGroupInfoToWebhook(ctx, meta.AccountID, e); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
 			}
 		}(evt)
@@ -10,31 +10,42 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
-func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
+// maxWebhookResponseSize caps how much of a webhook response body is read for reply actions
+const maxWebhookResponseSize = 1 << 20
+
+// webhookResponse is what the webhook answered on the last attempt
+type webhookResponse struct {
+	StatusCode int
+	Body       []byte
+	Attempts   int
+}
+
+func submitWebhook(ctx context.Context, payload any, url string) (response webhookResponse, err error) {
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	postBody, err := json.Marshal(payload)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+		return response, pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+		return response, pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
 	}
 
 	secretKey := []byte(config.WhatsappWebhookSecret)
 	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		return response, pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", domainWebhook.ContentType(payload))
 	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
 
 	var attempt int
@@ -44,13 +55,22 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 	for attempt = 0; attempt < maxAttempts; attempt++ {
 		// Create new request body for each attempt
 		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
-		resp, err := client.Do(req)
+		response.Attempts = attempt + 1
+
+		var resp *http.Response
+		resp, err = client.Do(req)
 		if err == nil {
-			defer resp.Body.Close()
+			response.StatusCode = resp.StatusCode
 			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				response.Body, err = io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseSize))
+				resp.Body.Close()
+				if err != nil {
+					logrus.Warnf("Failed reading webhook response body: %v", err)
+				}
 				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
+				return response, nil
 			}
+			resp.Body.Close()
 			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
 		}
 		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
@@ -60,5 +80,5 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 		}
 	}
 
-	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
+	return response, pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
@@ -0,0 +1,182 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var webhookBatches = newWebhookBatcher()
+
+// isBatchEndpoint reports whether events for the webhook URL are delivered in batches
+func isBatchEndpoint(url string) bool {
+	return slices.Contains(config.WhatsappWebhookBatchEndpoints, "*") || slices.Contains(config.WhatsappWebhookBatchEndpoints, url)
+}
+
+// FlushWebhookBatches sends every pending batch right away and waits for the requests to finish
+func FlushWebhookBatches() {
+	webhookBatches.flushAll()
+}
+
+type batchedEvent struct {
+	event    domainEventSink.Event
+	queuedAt time.Time
+}
+
+// webhookBatcher collects events per webhook URL and sends them as one JSON array once the batch is full or
+// its oldest event waited for the maximum latency
+type webhookBatcher struct {
+	mu     sync.Mutex
+	queues map[string]*batchQueue
+}
+
+type batchQueue struct {
+	events []batchedEvent
+	timer  *time.Timer
+}
+
+func newWebhookBatcher() *webhookBatcher {
+	return &webhookBatcher{queues: make(map[string]*batchQueue)}
+}
+
+func (b *webhookBatcher) add(url string, event domainEventSink.Event) {
+	b.mu.Lock()
+	queue := b.queues[url]
+	if queue == nil {
+		queue = &batchQueue{}
+		b.queues[url] = queue
+	}
+	queue.events = append(queue.events, batchedEvent{event: event, queuedAt: time.Now()})
+
+	var ready []batchedEvent
+	if len(queue.events) >= config.WhatsappWebhookBatchSize {
+		ready = queue.take()
+	} else if queue.timer == nil {
+		queue.timer = time.AfterFunc(config.WhatsappWebhookBatchMaxLatency, func() {
+			b.flush(url)
+		})
+	}
+	b.mu.Unlock()
+
+	if ready != nil {
+		go sendBatch(url, ready)
+	}
+}
+
+// flush sends the pending events of a URL once its latency timer fired
+func (b *webhookBatcher) flush(url string) {
+	b.mu.Lock()
+	ready := b.queues[url].take()
+	b.mu.Unlock()
+
+	if len(ready) > 0 {
+		sendBatch(url, ready)
+	}
+}
+
+func (b *webhookBatcher) flushAll() {
+	b.mu.Lock()
+	pending := make(map[string][]batchedEvent, len(b.queues))
+	for url, queue := range b.queues {
+		if events := queue.take(); len(events) > 0 {
+			pending[url] = events
+		}
+	}
+	b.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for url, events := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendBatch(url, events)
+		}()
+	}
+	wg.Wait()
+}
+
+// take empties the queue and stops its latency timer
+func (q *batchQueue) take() []batchedEvent {
+	events := q.events
+	q.events = nil
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	return events
+}
+
+// sendBatch delivers the events as one signed JSON array and records a delivery per event, its duration includes
+// the time the event waited for the batch. The endpoint can reject single events by answering
+// {"failed": [<index>, ...]}. Reply actions are not executed for batches.
+func sendBatch(url string, events []batchedEvent) {
+	bodies := make([]any, len(events))
+	for i, queued := range events {
+		bodies[i] = queued.event.Payload
+	}
+
+	started := time.Now()
+	response, err := submitWebhookFn(context.Background(), bodies, url)
+
+	var rejected []int
+	if err == nil {
+		rejected = batchRejections(response.Body, len(events))
+	}
+
+	batchID := uuid.NewString()
+	for i, queued := range events {
+		delivery := newDelivery(queued.event, url, response, err, started)
+		delivery.BatchID = batchID
+		delivery.BatchIndex = i
+		delivery.BatchSize = len(events)
+		delivery.DurationMs = time.Since(queued.queuedAt).Milliseconds()
+		if err == nil && slices.Contains(rejected, i) {
+			delivery.Status = domainWebhook.DeliveryStatusFailed
+			delivery.Error = "rejected by the webhook"
+		}
+		recordDelivery(delivery)
+	}
+
+	switch {
+	case err != nil:
+		logrus.Warnf("Failed forwarding batch of %d events to %s: %v", len(events), url, err)
+	case len(rejected) > 0:
+		logrus.Warnf("Webhook %s rejected %d of %d batched events", url, len(rejected), len(events))
+	default:
+		logrus.Infof("Forwarded batch of %d events to %s", len(events), url)
+	}
+}
+
+// batchRejections returns the valid, distinct positions a batch response marks as failed
+func batchRejections(body []byte, size int) []int {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var response domainWebhook.BatchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		logrus.Debugf("Ignoring batch response body: %v", err)
+		return nil
+	}
+
+	var rejected []int
+	for _, index := range response.Failed {
+		if index < 0 || index >= size {
+			logrus.Warnf("Batch response marks unknown index %d as failed", index)
+			continue
+		}
+		if !slices.Contains(rejected, index) {
+			rejected = append(rejected, index)
+		}
+	}
+	return rejected
+}
@@ -0,0 +1,162 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]any
+	sent    chan struct{}
+}
+
+func (r *batchRecorder) submit(response webhookResponse, err error) func(context.Context, any, string) (webhookResponse, error) {
+	return func(_ context.Context, body any, _ string) (webhookResponse, error) {
+		r.mu.Lock()
+		r.batches = append(r.batches, body.([]any))
+		r.mu.Unlock()
+		r.sent <- struct{}{}
+		return response, err
+	}
+}
+
+func (r *batchRecorder) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.sent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("batch was not sent")
+	}
+}
+
+func setupBatching(t *testing.T, size int, latency time.Duration) *memoryDeliveryRepository {
+	t.Helper()
+
+	originalWebhooks := config.WhatsappWebhook
+	originalEndpoints := config.WhatsappWebhookBatchEndpoints
+	originalSize := config.WhatsappWebhookBatchSize
+	originalLatency := config.WhatsappWebhookBatchMaxLatency
+	originalSubmit := submitWebhookFn
+	originalBatches := webhookBatches
+
+	config.WhatsappWebhook = []string{"https://bulk"}
+	config.WhatsappWebhookBatchEndpoints = []string{"https://bulk"}
+	config.WhatsappWebhookBatchSize = size
+	config.WhatsappWebhookBatchMaxLatency = latency
+	webhookBatches = newWebhookBatcher()
+
+	repo := &memoryDeliveryRepository{}
+	SetWebhookDeliveryRepository(repo)
+
+	t.Cleanup(func() {
+		config.WhatsappWebhook = originalWebhooks
+		config.WhatsappWebhookBatchEndpoints = originalEndpoints
+		config.WhatsappWebhookBatchSize = originalSize
+		config.WhatsappWebhookBatchMaxLatency = originalLatency
+		submitWebhookFn = originalSubmit
+		webhookBatches = originalBatches
+		SetWebhookDeliveryRepository(nil)
+	})
+	return repo
+}
+
+func TestWebhookBatch_SendsWhenFull(t *testing.T) {
+	repo := setupBatching(t, 3, time.Hour)
+	recorder := &batchRecorder{sent: make(chan struct{}, 1)}
+	submitWebhookFn = recorder.submit(webhookResponse{StatusCode: 200, Attempts: 1, Body: []byte(`{"failed":[1, 1, 7]}`)}, nil)
+
+	for range 3 {
+		if err := forwardPayload(context.Background(), domainWebhook.Subject{}, testPayload()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	recorder.wait(t)
+	deliveries := repo.waitFor(t, 3)
+
+	if len(recorder.batches) != 1 || len(recorder.batches[0]) != 3 {
+		t.Fatalf("expected one batch of 3 events, got %v", recorder.batches)
+	}
+	for i, delivery := range deliveries {
+		if delivery.BatchID == "" || delivery.BatchID != deliveries[0].BatchID || delivery.BatchIndex != i || delivery.BatchSize != 3 {
+			t.Errorf("unexpected batch fields %+v", delivery)
+		}
+		wantStatus := domainWebhook.DeliveryStatusDelivered
+		if i == 1 {
+			wantStatus = domainWebhook.DeliveryStatusFailed
+		}
+		if delivery.Status != wantStatus {
+			t.Errorf("delivery %d status = %s, want %s", i, delivery.Status, wantStatus)
+		}
+	}
+}
+
+func TestWebhookBatch_SendsAfterMaxLatency(t *testing.T) {
+	repo := setupBatching(t, 100, 20*time.Millisecond)
+	recorder := &batchRecorder{sent: make(chan struct{}, 1)}
+	submitWebhookFn = recorder.submit(webhookResponse{Attempts: 5, StatusCode: 500}, errors.New("webhook returned status 500"))
+
+	for range 2 {
+		if err := forwardPayload(context.Background(), domainWebhook.Subject{}, testPayload()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	recorder.wait(t)
+	deliveries := repo.waitFor(t, 2)
+
+	if len(recorder.batches) != 1 || len(recorder.batches[0]) != 2 {
+		t.Fatalf("expected one batch of 2 events, got %v", recorder.batches)
+	}
+	for _, delivery := range deliveries {
+		if delivery.Status != domainWebhook.DeliveryStatusFailed || delivery.Attempts != 5 || delivery.Error == "" {
+			t.Errorf("unexpected delivery %+v", delivery)
+		}
+	}
+}
+
+func TestWebhookBatch_FlushAndUnbatchedEndpoints(t *testing.T) {
+	setupBatching(t, 100, time.Hour)
+	config.WhatsappWebhook = []string{"https://bulk", "https://single"}
+
+	var (
+		mu     sync.Mutex
+		bodies = map[string]any{}
+	)
+	submitWebhookFn = func(_ context.Context, body any, url string) (webhookResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		bodies[url] = body
+		return webhookResponse{StatusCode: 200}, nil
+	}
+
+	if err := forwardPayload(context.Background(), domainWebhook.Subject{}, testPayload()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := bodies["https://single"].(*domainWebhook.ReceiptPayload); !ok {
+		t.Fatalf("expected the unbatched endpoint to receive the payload right away, got %T", bodies["https://single"])
+	}
+	if _, ok := bodies["https://bulk"]; ok {
+		t.Fatal("expected the batch endpoint to wait for its batch")
+	}
+
+	FlushWebhookBatches()
+	if batch, ok := bodies["https://bulk"].([]any); !ok || len(batch) != 1 {
+		t.Fatalf("expected flush to send the pending batch, got %v", bodies["https://bulk"])
+	}
+}
+
+func TestContentTypeOfBatches(t *testing.T) {
+	cloudEvent := domainWebhook.NewCloudEvent("default", testPayload())
+	if got := domainWebhook.ContentType([]any{cloudEvent}); got != domainWebhook.CloudEventsBatchContentType {
+		t.Errorf("content type = %s, want %s", got, domainWebhook.CloudEventsBatchContentType)
+	}
+	if got := domainWebhook.ContentType([]any{testPayload()}); got != domainWebhook.JSONContentType {
+		t.Errorf("content type = %s, want %s", got, domainWebhook.JSONContentType)
+	}
+}
@@ -0,0 +1,128 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// deliveryPruneInterval is how often records older than the retention are removed from the delivery log
+const deliveryPruneInterval = time.Hour
+
+var (
+	deliveryMu        sync.RWMutex
+	deliveryRepo      domainWebhook.IDeliveryRepository
+	actionExecutor    domainWebhook.IActionExecutor
+	deliveryLastPrune time.Time
+)
+
+// SetWebhookDeliveryRepository enables the delivery log, every webhook request is recorded in it
+func SetWebhookDeliveryRepository(repo domainWebhook.IDeliveryRepository) {
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+	deliveryRepo = repo
+}
+
+// SetWebhookActionExecutor sets what runs the reply actions returned by webhooks
+func SetWebhookActionExecutor(executor domainWebhook.IActionExecutor) {
+	deliveryMu.Lock()
+	defer deliveryMu.Unlock()
+	actionExecutor = executor
+}
+
+// newDelivery describes the outcome of submitting an event to a webhook URL
+func newDelivery(event domainEventSink.Event, url string, response webhookResponse, err error, started time.Time) *domainWebhook.Delivery {
+	delivery := &domainWebhook.Delivery{
+		ID:         uuid.NewString(),
+		AccountID:  event.AccountID,
+		EventType:  event.Type,
+		URL:        url,
+		Status:     domainWebhook.DeliveryStatusDelivered,
+		Attempts:   response.Attempts,
+		StatusCode: response.StatusCode,
+		DurationMs: time.Since(started).Milliseconds(),
+		CreatedAt:  started,
+	}
+	if err != nil {
+		delivery.Status = domainWebhook.DeliveryStatusFailed
+		delivery.Error = err.Error()
+	}
+	return delivery
+}
+
+// runReplyActions executes the actions of a webhook response body against the chat of the delivered event.
+// It returns the outcome of every action, or why the body was not accepted.
+func runReplyActions(ctx context.Context, subject domainWebhook.Subject, body []byte) ([]domainWebhook.ActionResult, string) {
+	// Plain acknowledgements such as "OK" are not meant as actions
+	if !config.WhatsappWebhookReplyActions || !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return nil, ""
+	}
+
+	var response domainWebhook.ActionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Sprintf("response body is not a valid action list: %v", err)
+	}
+	if len(response.Actions) == 0 {
+		return nil, ""
+	}
+	if subject.ChatJID == "" {
+		return nil, fmt.Sprintf("%s events have no chat to run reply actions against", subject.EventType)
+	}
+	// Answering old messages while a history sync backfills them would reply to conversations long over
+	if subject.IsHistory {
+		return nil, "reply actions are not run for messages backfilled by a history sync"
+	}
+
+	deliveryMu.RLock()
+	executor := actionExecutor
+	deliveryMu.RUnlock()
+	if executor == nil {
+		return nil, "reply actions are not available"
+	}
+
+	return executor.ExecuteActions(ctx, subject, response.Actions), ""
+}
+
+// recordDelivery stores the delivery in the log and prunes old records now and then
+func recordDelivery(delivery *domainWebhook.Delivery) {
+	for _, result := range delivery.Actions {
+		if result.Status != domainWebhook.ActionStatusSuccess {
+			logrus.Warnf("Webhook reply action %s for %s from %s %s: %s", result.Type, delivery.EventType, delivery.URL, result.Status, result.Error)
+		}
+	}
+	if delivery.ActionError != "" {
+		logrus.Warnf("Ignored reply actions for %s from %s: %s", delivery.EventType, delivery.URL, delivery.ActionError)
+	}
+
+	deliveryMu.Lock()
+	repo := deliveryRepo
+	prune := repo != nil && config.WhatsappWebhookDeliveryRetention > 0 && time.Since(deliveryLastPrune) > deliveryPruneInterval
+	if prune {
+		deliveryLastPrune = time.Now()
+	}
+	deliveryMu.Unlock()
+
+	if repo == nil {
+		return
+	}
+	if err := repo.CreateDelivery(delivery); err != nil {
+		logrus.Warnf("Failed recording webhook delivery: %v", err)
+	}
+	if prune {
+		removed, err := repo.DeleteDeliveriesBefore(time.Now().Add(-config.WhatsappWebhookDeliveryRetention))
+		if err != nil {
+			logrus.Warnf("Failed pruning webhook delivery log: %v", err)
+		} else if removed > 0 {
+			logrus.Debugf("Pruned %d webhook delivery records", removed)
+		}
+	}
+}
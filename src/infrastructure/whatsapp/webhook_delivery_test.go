@@ -0,0 +1,221 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waWeb"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type memoryDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries []domainWebhook.Delivery
+}
+
+func (r *memoryDeliveryRepository) InitializeSchema() error { return nil }
+
+func (r *memoryDeliveryRepository) CreateDelivery(delivery *domainWebhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *memoryDeliveryRepository) ListDeliveries(domainWebhook.DeliveryFilter) ([]domainWebhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.deliveries), nil
+}
+
+// waitFor returns the records once n deliveries were recorded
+func (r *memoryDeliveryRepository) waitFor(t *testing.T, n int) []domainWebhook.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		deliveries, _ := r.ListDeliveries(domainWebhook.DeliveryFilter{})
+		if len(deliveries) >= n {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d delivery records, got %d", n, len(deliveries))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (r *memoryDeliveryRepository) GetDelivery(string) (*domainWebhook.Delivery, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryDeliveryRepository) DeleteDeliveriesBefore(time.Time) (int64, error) { return 0, nil }
+
+type recordingExecutor struct {
+	subject domainWebhook.Subject
+	actions []domainWebhook.Action
+}
+
+func (e *recordingExecutor) ExecuteActions(_ context.Context, subject domainWebhook.Subject, actions []domainWebhook.Action) []domainWebhook.ActionResult {
+	e.subject = subject
+	e.actions = actions
+	results := make([]domainWebhook.ActionResult, len(actions))
+	for i, action := range actions {
+		results[i] = domainWebhook.ActionResult{Type: action.Type, Status: domainWebhook.ActionStatusSuccess}
+	}
+	return results
+}
+
+func TestWebhookSink_ReplyActionsAndDeliveryLog(t *testing.T) {
+	ctx := context.Background()
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://bot", "https://down"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalReplyActions := config.WhatsappWebhookReplyActions
+	config.WhatsappWebhookReplyActions = true
+	defer func() { config.WhatsappWebhookReplyActions = originalReplyActions }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, _ any, url string) (webhookResponse, error) {
+		if url == "https://down" {
+			return webhookResponse{StatusCode: 503, Attempts: 5}, errors.New("webhook returned status 503")
+		}
+		return webhookResponse{
+			StatusCode: 200,
+			Attempts:   1,
+			Body:       []byte(`{"actions":[{"type":"reply","text":"hi","quote":true},{"type":"mark_read"}]}`),
+		}, nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	repo := &memoryDeliveryRepository{}
+	executor := &recordingExecutor{}
+	SetWebhookDeliveryRepository(repo)
+	SetWebhookActionExecutor(executor)
+	defer func() {
+		SetWebhookDeliveryRepository(nil)
+		SetWebhookActionExecutor(nil)
+	}()
+
+	subject := domainWebhook.Subject{ChatJID: "6289@s.whatsapp.net", MessageID: "3EB0"}
+	if err := forwardPayload(ctx, subject, testPayload()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(executor.actions) != 2 || executor.actions[0].Text != "hi" || !executor.actions[0].Quote {
+		t.Fatalf("unexpected actions %+v", executor.actions)
+	}
+	if executor.subject.ChatJID != subject.ChatJID || executor.subject.MessageID != subject.MessageID {
+		t.Errorf("actions ran against %+v", executor.subject)
+	}
+
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("expected 2 delivery records, got %d", len(repo.deliveries))
+	}
+	delivered, failed := repo.deliveries[0], repo.deliveries[1]
+	if delivered.Status != domainWebhook.DeliveryStatusDelivered || delivered.StatusCode != 200 || len(delivered.Actions) != 2 {
+		t.Errorf("unexpected delivered record %+v", delivered)
+	}
+	if failed.Status != domainWebhook.DeliveryStatusFailed || failed.Attempts != 5 || failed.Error == "" || failed.Actions != nil {
+		t.Errorf("unexpected failed record %+v", failed)
+	}
+}
+
+func TestRunReplyActions(t *testing.T) {
+	originalReplyActions := config.WhatsappWebhookReplyActions
+	defer func() { config.WhatsappWebhookReplyActions = originalReplyActions }()
+
+	executor := &recordingExecutor{}
+	SetWebhookActionExecutor(executor)
+	defer SetWebhookActionExecutor(nil)
+
+	chat := domainWebhook.Subject{EventType: domainWebhook.EventMessage, ChatJID: "6289@s.whatsapp.net"}
+	tests := []struct {
+		name        string
+		enabled     bool
+		subject     domainWebhook.Subject
+		body        string
+		wantResults int
+		wantError   bool
+	}{
+		{name: "disabled", enabled: false, subject: chat, body: `{"actions":[{"type":"mark_read"}]}`},
+		{name: "empty body", enabled: true, subject: chat, body: " "},
+		{name: "plain acknowledgement", enabled: true, subject: chat, body: `{"status":"ok"}`},
+		{name: "plain text", enabled: true, subject: chat, body: "OK"},
+		{name: "malformed actions", enabled: true, subject: chat, body: `{"actions":"reply"}`, wantError: true},
+		{name: "no chat", enabled: true, subject: domainWebhook.Subject{EventType: "message.ack"}, body: `{"actions":[{"type":"mark_read"}]}`, wantError: true},
+		{name: "history message", enabled: true, subject: domainWebhook.Subject{EventType: domainWebhook.EventMessage, ChatJID: "6289@s.whatsapp.net", IsHistory: true}, body: `{"actions":[{"type":"reply","text":"hi"}]}`, wantError: true},
+		{name: "actions", enabled: true, subject: chat, body: `{"actions":[{"type":"react","emoji":"👍"}]}`, wantResults: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.WhatsappWebhookReplyActions = tt.enabled
+			results, actionError := runReplyActions(context.Background(), tt.subject, []byte(tt.body))
+			if len(results) != tt.wantResults {
+				t.Errorf("results = %+v, want %d", results, tt.wantResults)
+			}
+			if (actionError != "") != tt.wantError {
+				t.Errorf("action error = %q, want error %v", actionError, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestForwardHistoryMessageSkipsReplyActions(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://bot"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalReplyActions := config.WhatsappWebhookReplyActions
+	config.WhatsappWebhookReplyActions = true
+	defer func() { config.WhatsappWebhookReplyActions = originalReplyActions }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(context.Context, any, string) (webhookResponse, error) {
+		return webhookResponse{StatusCode: 200, Attempts: 1, Body: []byte(`{"actions":[{"type":"reply","text":"hi"}]}`)}, nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	repo := &memoryDeliveryRepository{}
+	executor := &recordingExecutor{}
+	SetWebhookDeliveryRepository(repo)
+	SetWebhookActionExecutor(executor)
+	defer func() {
+		SetWebhookDeliveryRepository(nil)
+		SetWebhookActionExecutor(nil)
+	}()
+
+	chat := types.NewJID("6289", types.DefaultUserServer)
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			ID:            "3EB0",
+			Timestamp:     time.Now().Add(-24 * time.Hour),
+		},
+		Message:      &waE2E.Message{Conversation: proto.String("are you there?")},
+		SourceWebMsg: &waWeb.WebMessageInfo{},
+	}
+	if err := forwardMessageToWebhook(context.Background(), eventbus.Meta{}, evt); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if executor.actions != nil {
+		t.Errorf("expected no reply actions for a history message, ran %+v", executor.actions)
+	}
+	deliveries := repo.waitFor(t, 1)
+	if deliveries[0].Status != domainWebhook.DeliveryStatusDelivered || deliveries[0].ActionError == "" {
+		t.Errorf("unexpected delivery record %+v", deliveries[0])
+	}
+}
@@ -4,19 +4,167 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/sirupsen/logrus"
 )
 
 var submitWebhookFn = submitWebhook
 
-// forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL.
+var (
+	eventSinksMu sync.RWMutex
+	eventSinks   []domainEventSink.IEventSink
+
+	webhookRules atomic.Pointer[domainWebhook.RuleSet]
+)
+
+// SetWebhookRules replaces the routing rules evaluated before every event is published
+func SetWebhookRules(rules *domainWebhook.RuleSet) {
+	webhookRules.Store(rules)
+}
+
+// EvaluateWebhookRules returns what the current routing rules decide for an event
+func EvaluateWebhookRules(subject domainWebhook.Subject) domainWebhook.Decision {
+	if subject.AccountID == "" {
+		subject.AccountID = domainEventSink.DefaultAccountID
+	}
+	return webhookRules.Load().Evaluate(subject)
+}
+
+// RegisterEventSink adds a sink that receives every event next to the configured webhooks
+func RegisterEventSink(sink domainEventSink.IEventSink) {
+	eventSinksMu.Lock()
+	defer eventSinksMu.Unlock()
+	eventSinks = append(eventSinks, sink)
+}
+
+// CloseEventSinks sends the pending webhook batches, then flushes and closes every registered sink
+func CloseEventSinks() {
+	FlushWebhookBatches()
+
+	eventSinksMu.Lock()
+	defer eventSinksMu.Unlock()
+	for _, sink := range eventSinks {
+		if err := sink.Close(); err != nil {
+			logrus.Warnf("Failed closing %s event sink: %v", sink.Name(), err)
+		}
+	}
+	eventSinks = nil
+}
+
+// hasEventSinks reports whether any webhook, routing rule or broker sink is configured
+func hasEventSinks() bool {
+	eventSinksMu.RLock()
+	defer eventSinksMu.RUnlock()
+	return len(config.WhatsappWebhook) > 0 || len(eventSinks) > 0 || webhookRules.Load().Len() > 0
+}
+
+// activeEventSinks returns the webhook sink followed by the registered broker sinks.
+// endpoints replace the configured webhooks when a routing rule matched.
+func activeEventSinks(subject domainWebhook.Subject, endpoints []string) []domainEventSink.IEventSink {
+	eventSinksMu.RLock()
+	defer eventSinksMu.RUnlock()
+	return append([]domainEventSink.IEventSink{webhookSink{subject: subject, endpoints: endpoints}}, eventSinks...)
+}
+
+// ForwardPayload publishes an event raised outside the WhatsApp event handlers, like the progress of a bulk
+// send job. accountID is empty for the default device.
+func ForwardPayload(ctx context.Context, accountID string, payload domainWebhook.Payload) error {
+	if !hasEventSinks() {
+		return nil
+	}
+	return forwardPayload(ctx, domainWebhook.Subject{AccountID: accountID}, payload)
+}
+
+// forwardPayload evaluates the routing rules for the event described by subject (an empty account is the
+// default device) and publishes the payload to every sink. With CloudEvents enabled every sink receives the
+// same envelope.
+func forwardPayload(ctx context.Context, subject domainWebhook.Subject, payload domainWebhook.Payload) error {
+	subject.EventType = payload.EventType()
+	if subject.AccountID == "" {
+		subject.AccountID = domainEventSink.DefaultAccountID
+	}
+
+	decision := webhookRules.Load().Evaluate(subject)
+	if decision.Drop {
+		logrus.Debugf("Dropped %s by webhook rules %v", subject.EventType, decision.MatchedRules)
+		return nil
+	}
+	if len(decision.Tags) > 0 {
+		payload.AddTags(decision.Tags...)
+	}
+
+	var body any = payload
+	if config.WhatsappWebhookCloudEvents {
+		body = domainWebhook.NewCloudEvent(subject.AccountID, payload)
+	}
+
+	return publishEvent(ctx, domainEventSink.Event{
+		AccountID: subject.AccountID,
+		Type:      subject.EventType,
+		Payload:   body,
+	}, subject, decision.Endpoints)
+}
+
+// publishEvent fans the event out to every sink. It only returns an error when all sinks fail.
+func publishEvent(ctx context.Context, event domainEventSink.Event, subject domainWebhook.Subject, endpoints []string) error {
+	sinks := activeEventSinks(subject, endpoints)
+
+	var (
+		lastErr error
+		failed  []string
+	)
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			lastErr = err
+			failed = append(failed, fmt.Sprintf("%s: %v", sink.Name(), err))
+			logrus.Warnf("Failed publishing %s to %s sink: %v", event.Type, sink.Name(), err)
+		}
+	}
+
+	switch {
+	case len(failed) < len(sinks):
+		return nil
+	case len(sinks) == 1:
+		return lastErr
+	default:
+		return pkgError.EventSinkError(fmt.Sprintf("all event sinks failed for %s: %s", event.Type, strings.Join(failed, "; ")))
+	}
+}
+
+// webhookSink delivers events of the default device to every configured webhook URL, or any event to the
+// endpoints chosen by a routing rule. Other events of accounts only go to the broker sinks, their webhook is
+// configured per account.
 // It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
-// successful targets still receive the event.
-func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	total := len(config.WhatsappWebhook)
+// successful targets still receive the event. Every request is recorded in the delivery log, together with
+// the reply actions the webhook answered with. Events for batch endpoints are queued and count as delivered,
+// their outcome is recorded once the batch is sent.
+type webhookSink struct {
+	subject   domainWebhook.Subject
+	endpoints []string
+}
+
+func (webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s webhookSink) Publish(ctx context.Context, event domainEventSink.Event) error {
+	urls := s.endpoints
+	if len(urls) == 0 {
+		if event.AccountID != domainEventSink.DefaultAccountID {
+			return nil
+		}
+		urls = config.WhatsappWebhook
+	}
+
+	eventName := event.Type
+	total := len(urls)
 	logrus.Infof("Forwarding %s to %d configured webhook(s)", eventName, total)
 
 	if total == 0 {
@@ -28,13 +176,26 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 		failed    []string
 		successes int
 	)
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhookFn(ctx, payload, url); err != nil {
+	for _, url := range urls {
+		if isBatchEndpoint(url) {
+			webhookBatches.add(url, event)
+			successes++
+			continue
+		}
+
+		started := time.Now()
+		response, err := submitWebhookFn(ctx, event.Payload, url)
+		delivery := newDelivery(event, url, response, err, started)
+		if err != nil {
+			recordDelivery(delivery)
 			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
 			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, url, err)
 			continue
 		}
 		successes++
+
+		delivery.Actions, delivery.ActionError = runReplyActions(ctx, s.subject, response.Body)
+		recordDelivery(delivery)
 	}
 
 	if len(failed) == total {
@@ -49,3 +210,7 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 
 	return nil
 }
+
+func (webhookSink) Close() error {
+	return nil
+}
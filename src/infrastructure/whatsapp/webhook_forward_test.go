@@ -7,31 +7,37 @@ import (
 	"testing"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 )
 
-func TestForwardPayloadToConfiguredWebhooks_NoWebhooksConfigured(t *testing.T) {
+func testPayload() *domainWebhook.ReceiptPayload {
+	return &domainWebhook.ReceiptPayload{Meta: domainWebhook.NewMeta(domainWebhook.EventMessageAck)}
+}
+
+func TestForwardPayload_NoWebhooksConfigured(t *testing.T) {
 	ctx := context.Background()
-	payload := map[string]any{"foo": "bar"}
+	payload := testPayload()
 
 	originalWebhooks := config.WhatsappWebhook
 	config.WhatsappWebhook = nil
 	defer func() { config.WhatsappWebhook = originalWebhooks }()
 
 	originalSubmit := submitWebhookFn
-	submitWebhookFn = func(context.Context, map[string]any, string) error {
+	submitWebhookFn = func(context.Context, any, string) (webhookResponse, error) {
 		t.Fatal("submitWebhookFn should not be invoked when no webhooks are configured")
-		return nil
+		return webhookResponse{}, nil
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err != nil {
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, payload); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
 
-func TestForwardPayloadToConfiguredWebhooks_PartialFailure(t *testing.T) {
+func TestForwardPayload_PartialFailure(t *testing.T) {
 	ctx := context.Background()
-	payload := map[string]any{"foo": "bar"}
+	payload := testPayload()
 
 	originalWebhooks := config.WhatsappWebhook
 	config.WhatsappWebhook = []string{"https://success", "https://fail", "https://success2"}
@@ -39,16 +45,16 @@ func TestForwardPayloadToConfiguredWebhooks_PartialFailure(t *testing.T) {
 
 	originalSubmit := submitWebhookFn
 	var attempts []string
-	submitWebhookFn = func(_ context.Context, _ map[string]any, url string) error {
+	submitWebhookFn = func(_ context.Context, _ any, url string) (webhookResponse, error) {
 		attempts = append(attempts, url)
 		if strings.Contains(url, "fail") {
-			return errors.New("boom")
+			return webhookResponse{}, errors.New("boom")
 		}
-		return nil
+		return webhookResponse{}, nil
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err != nil {
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, payload); err != nil {
 		t.Fatalf("expected partial failure to return nil, got %v", err)
 	}
 
@@ -57,21 +63,187 @@ func TestForwardPayloadToConfiguredWebhooks_PartialFailure(t *testing.T) {
 	}
 }
 
-func TestForwardPayloadToConfiguredWebhooks_AllFail(t *testing.T) {
+func TestForwardPayload_AllFail(t *testing.T) {
 	ctx := context.Background()
-	payload := map[string]any{"foo": "bar"}
+	payload := testPayload()
 
 	originalWebhooks := config.WhatsappWebhook
 	config.WhatsappWebhook = []string{"https://fail1", "https://fail2"}
 	defer func() { config.WhatsappWebhook = originalWebhooks }()
 
 	originalSubmit := submitWebhookFn
-	submitWebhookFn = func(_ context.Context, _ map[string]any, url string) error {
-		return errors.New("failure for " + url)
+	submitWebhookFn = func(_ context.Context, _ any, url string) (webhookResponse, error) {
+		return webhookResponse{}, errors.New("failure for " + url)
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err == nil {
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, payload); err == nil {
 		t.Fatalf("expected error when all webhooks fail")
 	}
 }
+
+type recordingSink struct {
+	err    error
+	events []domainEventSink.Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Publish(_ context.Context, event domainEventSink.Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestForwardPayload_FansOutToEventSinks(t *testing.T) {
+	ctx := context.Background()
+	payload := testPayload()
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://fail"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(context.Context, any, string) (webhookResponse, error) {
+		return webhookResponse{}, errors.New("boom")
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	sink := &recordingSink{}
+	RegisterEventSink(sink)
+	defer CloseEventSinks()
+
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, payload); err != nil {
+		t.Fatalf("expected a delivered sink to suppress the webhook failure, got %v", err)
+	}
+
+	if len(sink.events) != 1 {
+		t.Fatalf("expected 1 event on the sink, got %d", len(sink.events))
+	}
+	got := sink.events[0]
+	if got.Type != domainWebhook.EventMessageAck || got.AccountID != domainEventSink.DefaultAccountID {
+		t.Errorf("unexpected event %+v", got)
+	}
+
+	sink.err = errors.New("broker down")
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, payload); err == nil {
+		t.Fatal("expected error when the webhook and every sink fail")
+	}
+}
+
+func TestForwardPayload_CloudEvents(t *testing.T) {
+	ctx := context.Background()
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://success"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalCloudEvents := config.WhatsappWebhookCloudEvents
+	config.WhatsappWebhookCloudEvents = true
+	defer func() { config.WhatsappWebhookCloudEvents = originalCloudEvents }()
+
+	originalSubmit := submitWebhookFn
+	var submitted any
+	submitWebhookFn = func(_ context.Context, body any, _ string) (webhookResponse, error) {
+		submitted = body
+		return webhookResponse{}, nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	sink := &recordingSink{}
+	RegisterEventSink(sink)
+	defer CloseEventSinks()
+
+	if err := forwardPayload(ctx, domainWebhook.Subject{AccountID: "sales"}, testPayload()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if submitted != nil {
+		t.Fatal("events of other accounts must not be sent to the global webhooks")
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf("expected 1 event on the sink, got %d", len(sink.events))
+	}
+	envelope, ok := sink.events[0].Payload.(domainWebhook.CloudEvent)
+	if !ok {
+		t.Fatalf("expected a CloudEvent, got %T", sink.events[0].Payload)
+	}
+	if envelope.SpecVersion != "1.0" || envelope.Type != "whatsapp.message.ack" || envelope.Source != "/whatsapp/sales" {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+	if envelope.DataSchema != domainWebhook.SchemaURL(domainWebhook.EventMessageAck) || envelope.ID == "" {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+	if domainWebhook.ContentType(envelope) != domainWebhook.CloudEventsContentType {
+		t.Errorf("content type = %s", domainWebhook.ContentType(envelope))
+	}
+
+	if err := forwardPayload(ctx, domainWebhook.Subject{}, testPayload()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := submitted.(domainWebhook.CloudEvent); !ok {
+		t.Fatalf("expected the webhook to receive the CloudEvent, got %T", submitted)
+	}
+}
+
+func TestForwardPayload_RoutingRules(t *testing.T) {
+	ctx := context.Background()
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://default"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	var attempts []string
+	var lastBody any
+	submitWebhookFn = func(_ context.Context, body any, url string) (webhookResponse, error) {
+		attempts = append(attempts, url)
+		lastBody = body
+		return webhookResponse{}, nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	rules, err := domainWebhook.NewRuleSet([]domainWebhook.Rule{
+		{ID: "groups", Enabled: true, Priority: 1, Action: domainWebhook.RuleActionRoute, Endpoints: []string{"https://team"}, Match: domainWebhook.RuleMatch{ChatType: domainWebhook.ChatTypeGroup}},
+		{ID: "spam", Enabled: true, Priority: 0, Action: domainWebhook.RuleActionDrop, Match: domainWebhook.RuleMatch{ContentRegex: "(?i)casino"}},
+		{ID: "vip", Enabled: true, Priority: 2, Action: domainWebhook.RuleActionTag, Tags: []string{"vip"}, Match: domainWebhook.RuleMatch{Sender: "6281*"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetWebhookRules(rules)
+	defer SetWebhookRules(nil)
+
+	tests := []struct {
+		name     string
+		subject  domainWebhook.Subject
+		want     []string
+		wantTags []string
+	}{
+		{name: "group is routed", subject: domainWebhook.Subject{ChatJID: "120363@g.us", SenderJID: "6289@s.whatsapp.net"}, want: []string{"https://team"}},
+		{name: "account group is routed", subject: domainWebhook.Subject{AccountID: "sales", ChatJID: "120363@g.us"}, want: []string{"https://team"}},
+		{name: "dm uses configured webhooks", subject: domainWebhook.Subject{ChatJID: "6289@s.whatsapp.net", SenderJID: "6289@s.whatsapp.net"}, want: []string{"https://default"}},
+		{name: "account dm is not sent to global webhooks", subject: domainWebhook.Subject{AccountID: "sales", ChatJID: "6289@s.whatsapp.net"}},
+		{name: "spam is dropped", subject: domainWebhook.Subject{ChatJID: "120363@g.us", Content: "Best CASINO deals"}},
+		{name: "vip is tagged", subject: domainWebhook.Subject{ChatJID: "6281@s.whatsapp.net", SenderJID: "6281@s.whatsapp.net"}, want: []string{"https://default"}, wantTags: []string{"vip"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts, lastBody = nil, nil
+			if err := forwardPayload(ctx, tt.subject, testPayload()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if strings.Join(attempts, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("delivered to %v, want %v", attempts, tt.want)
+			}
+			if tt.wantTags != nil {
+				payload := lastBody.(*domainWebhook.ReceiptPayload)
+				if strings.Join(payload.Tags, ",") != strings.Join(tt.wantTags, ",") {
+					t.Errorf("tags = %v, want %v", payload.Tags, tt.wantTags)
+				}
+			}
+		})
+	}
+}
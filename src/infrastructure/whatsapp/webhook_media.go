@@ -0,0 +1,113 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+)
+
+// WebhookMediaRoute serves downloaded media behind signed URLs, relative to the app base path
+const WebhookMediaRoute = "/webhook/media"
+
+// webhookMediaMessage is implemented by every downloadable WhatsApp media message
+type webhookMediaMessage interface {
+	whatsmeow.DownloadableMessage
+	GetURL() string
+	GetMimetype() string
+	GetFileLength() uint64
+}
+
+// remoteMedia describes an attachment that was not downloaded, with the metadata WhatsApp sent
+func remoteMedia(media webhookMediaMessage) *domainWebhook.Media {
+	return &domainWebhook.Media{
+		URL:      media.GetURL(),
+		MimeType: media.GetMimetype(),
+		Size:     int64(media.GetFileLength()),
+		SHA256:   hex.EncodeToString(media.GetFileSHA256()),
+	}
+}
+
+// downloadedMedia describes a media file stored by utils.ExtractMedia according to the webhook media mode
+func downloadedMedia(extracted utils.ExtractedMedia) (*domainWebhook.Media, error) {
+	media := &domainWebhook.Media{
+		MediaPath: extracted.MediaPath,
+		MimeType:  extracted.MimeType,
+		Caption:   extracted.Caption,
+	}
+
+	data, err := os.ReadFile(extracted.MediaPath)
+	if err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to read media %s: %v", extracted.MediaPath, err))
+	}
+	sum := sha256.Sum256(data)
+	media.Size = int64(len(data))
+	media.SHA256 = hex.EncodeToString(sum[:])
+
+	switch config.WhatsappWebhookMediaMode {
+	case domainWebhook.MediaModeInline:
+		if media.Size <= config.WhatsappWebhookMediaInlineMaxSize {
+			media.Data = base64.StdEncoding.EncodeToString(data)
+			break
+		}
+		fallthrough
+	case domainWebhook.MediaModeURL:
+		downloadURL, expiresAt := SignMediaURL(filepath.Base(extracted.MediaPath), time.Now())
+		media.DownloadURL = downloadURL
+		media.DownloadURLExpiresAt = expiresAt.Format(time.RFC3339)
+	}
+
+	return media, nil
+}
+
+// SignMediaURL returns a download URL for a file in config.PathMedia that is valid until the returned time
+func SignMediaURL(filename string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(config.WhatsappWebhookMediaURLExpiry).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", mediaSignature(filename, expires))
+
+	base := strings.TrimSuffix(config.WhatsappWebhookMediaBaseURL, "/") + config.AppBasePath + WebhookMediaRoute
+	return fmt.Sprintf("%s/%s?%s", base, url.PathEscape(filename), query.Encode()), expiresAt
+}
+
+// VerifyMediaSignature checks a signed media URL and returns the local path of the file
+func VerifyMediaSignature(filename, expires, signature string, now time.Time) (string, error) {
+	if filename == "" || filename != filepath.Base(filename) || strings.HasPrefix(filename, ".") {
+		return "", pkgError.ValidationError("invalid media file name")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", pkgError.AuthError("invalid media URL expiry")
+	}
+	if now.Unix() > expiresAt {
+		return "", pkgError.AuthError("media URL expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(mediaSignature(filename, expires))) {
+		return "", pkgError.AuthError("invalid media URL signature")
+	}
+
+	return filepath.Join(config.PathMedia, filename), nil
+}
+
+func mediaSignature(filename, expires string) string {
+	mac := hmac.New(sha256.New, []byte(config.WhatsappWebhookSecret))
+	mac.Write([]byte(filename + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
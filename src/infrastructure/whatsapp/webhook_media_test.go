@@ -0,0 +1,117 @@
+package whatsapp
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+func TestSignAndVerifyMediaURL(t *testing.T) {
+	originalBaseURL := config.WhatsappWebhookMediaBaseURL
+	config.WhatsappWebhookMediaBaseURL = "https://wa.example.com/"
+	defer func() { config.WhatsappWebhookMediaBaseURL = originalBaseURL }()
+
+	now := time.Unix(1700000000, 0)
+	signed, expiresAt := SignMediaURL("1700000000-abc.jpg", now)
+	if !expiresAt.Equal(now.Add(config.WhatsappWebhookMediaURLExpiry)) {
+		t.Errorf("expires at %s", expiresAt)
+	}
+
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Host != "wa.example.com" || parsed.Path != WebhookMediaRoute+"/1700000000-abc.jpg" {
+		t.Fatalf("unexpected signed URL %s", signed)
+	}
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+
+	tests := []struct {
+		name      string
+		filename  string
+		expires   string
+		signature string
+		at        time.Time
+		wantErr   bool
+	}{
+		{name: "valid", filename: "1700000000-abc.jpg", expires: expires, signature: signature, at: now},
+		{name: "expired", filename: "1700000000-abc.jpg", expires: expires, signature: signature, at: expiresAt.Add(time.Second), wantErr: true},
+		{name: "other file", filename: "1700000000-def.jpg", expires: expires, signature: signature, at: now, wantErr: true},
+		{name: "extended expiry", filename: "1700000000-abc.jpg", expires: "9999999999", signature: signature, at: now, wantErr: true},
+		{name: "path traversal", filename: "../whatsapp.db", expires: expires, signature: signature, at: now, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := VerifyMediaSignature(tt.filename, tt.expires, tt.signature, tt.at)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && path != filepath.Join(config.PathMedia, tt.filename) {
+				t.Errorf("path = %s", path)
+			}
+		})
+	}
+}
+
+func TestDownloadedMediaModes(t *testing.T) {
+	content := []byte("not really a jpeg")
+	sum := sha256.Sum256(content)
+	path := filepath.Join(t.TempDir(), "1700000000-abc.jpg")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	extracted := utils.ExtractedMedia{MediaPath: path, MimeType: "image/jpeg"}
+
+	originalMode, originalMax := config.WhatsappWebhookMediaMode, config.WhatsappWebhookMediaInlineMaxSize
+	defer func() {
+		config.WhatsappWebhookMediaMode, config.WhatsappWebhookMediaInlineMaxSize = originalMode, originalMax
+	}()
+
+	tests := []struct {
+		name      string
+		mode      string
+		maxInline int64
+		wantData  bool
+		wantURL   bool
+	}{
+		{name: "path", mode: domainWebhook.MediaModePath, maxInline: 1024},
+		{name: "inline", mode: domainWebhook.MediaModeInline, maxInline: 1024, wantData: true},
+		{name: "inline above cap falls back to url", mode: domainWebhook.MediaModeInline, maxInline: 4, wantURL: true},
+		{name: "url", mode: domainWebhook.MediaModeURL, maxInline: 1024, wantURL: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.WhatsappWebhookMediaMode, config.WhatsappWebhookMediaInlineMaxSize = tt.mode, tt.maxInline
+
+			media, err := downloadedMedia(extracted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if media.Size != int64(len(content)) || media.SHA256 != hex.EncodeToString(sum[:]) || media.MimeType != "image/jpeg" {
+				t.Errorf("unexpected metadata %+v", media)
+			}
+			if got := media.Data != ""; got != tt.wantData {
+				t.Errorf("data set = %v, want %v", got, tt.wantData)
+			}
+			if tt.wantData && media.Data != base64.StdEncoding.EncodeToString(content) {
+				t.Errorf("data = %s", media.Data)
+			}
+			if got := media.DownloadURL != ""; got != tt.wantURL {
+				t.Errorf("download url set = %v, want %v", got, tt.wantURL)
+			}
+			if tt.wantURL && !strings.Contains(media.DownloadURL, "1700000000-abc.jpg?expires=") {
+				t.Errorf("download url = %s", media.DownloadURL)
+			}
+		})
+	}
+}
@@ -42,3 +42,37 @@ func (e ContextError) ErrCode() string {
 func (e ContextError) StatusCode() int {
 	return http.StatusRequestTimeout
 }
+
+type NotFoundError string
+
+// Error for complying the error interface
+func (e NotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e NotFoundError) ErrCode() string {
+	return "NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e NotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type ConflictError string
+
+// Error for complying the error interface
+func (e ConflictError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e ConflictError) ErrCode() string {
+	return "CONFLICT"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e ConflictError) StatusCode() int {
+	return http.StatusConflict
+}
@@ -36,6 +36,23 @@ func (e WebhookError) StatusCode() int {
 	return http.StatusInternalServerError
 }
 
+type EventSinkError string
+
+// Error for complying the error interface
+func (e EventSinkError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e EventSinkError) ErrCode() string {
+	return "EVENT_SINK_ERROR"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e EventSinkError) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
 type WaCliError string
 
 // Error for complying the error interface
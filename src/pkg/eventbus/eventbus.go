@@ -0,0 +1,224 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// ErrStopPropagation can be returned by a handler to skip the remaining (lower priority) handlers of the event
+var ErrStopPropagation = errors.New("stop event propagation")
+
+// DefaultSlowThreshold is the duration after which a handler run is logged as slow
+const DefaultSlowThreshold = time.Second
+
+// Meta describes the client an event was received on
+type Meta struct {
+	// AccountID is empty for the default (single device) client
+	AccountID string
+	Client    *whatsmeow.Client
+}
+
+// IsDefault reports whether the event comes from the default (single device) client
+func (m Meta) IsDefault() bool {
+	return m.AccountID == ""
+}
+
+// Handler handles events of type T. Handlers run sequentially in ascending Priority order.
+type Handler[T any] struct {
+	Name     string
+	Priority int
+	// Filter skips the handler when it returns false (optional)
+	Filter func(meta Meta, evt T) bool
+	Handle func(ctx context.Context, meta Meta, evt T) error
+}
+
+// HandlerStats is the timing of a registered handler
+type HandlerStats struct {
+	Name      string  `json:"name"`
+	EventType string  `json:"event_type"`
+	Priority  int     `json:"priority"`
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	Panics    int64   `json:"panics"`
+	TotalMs   float64 `json:"total_ms"`
+	AvgMs     float64 `json:"avg_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	LastMs    float64 `json:"last_ms"`
+	LastError string  `json:"last_error,omitempty"`
+}
+
+type registration struct {
+	name      string
+	eventType string
+	priority  int
+	seq       int
+	// invoke returns false when the event is not of the handler type or was filtered out
+	invoke func(ctx context.Context, meta Meta, evt any) (bool, error)
+
+	mu        sync.Mutex
+	calls     int64
+	errors    int64
+	panics    int64
+	total     time.Duration
+	max       time.Duration
+	last      time.Duration
+	lastError string
+}
+
+// Bus dispatches WhatsApp events to the registered handlers
+type Bus struct {
+	mu            sync.Mutex
+	handlers      atomic.Pointer[[]*registration]
+	seq           int
+	SlowThreshold time.Duration
+}
+
+func New() *Bus {
+	b := &Bus{SlowThreshold: DefaultSlowThreshold}
+	b.handlers.Store(&[]*registration{})
+	return b
+}
+
+// Register adds a typed handler. T may be a concrete event type such as *events.Message or an interface.
+func Register[T any](b *Bus, h Handler[T]) {
+	if h.Handle == nil {
+		panic(fmt.Sprintf("eventbus: handler %q has no Handle func", h.Name))
+	}
+
+	var zero T
+	reg := &registration{
+		name:      h.Name,
+		eventType: fmt.Sprintf("%T", zero),
+		priority:  h.Priority,
+		invoke: func(ctx context.Context, meta Meta, raw any) (bool, error) {
+			evt, ok := raw.(T)
+			if !ok {
+				return false, nil
+			}
+			if h.Filter != nil && !h.Filter(meta, evt) {
+				return false, nil
+			}
+			return true, h.Handle(ctx, meta, evt)
+		},
+	}
+	if reg.eventType == "<nil>" {
+		reg.eventType = "any"
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	reg.seq = b.seq
+
+	// Copy on write so Dispatch never takes a lock
+	current := *b.handlers.Load()
+	handlers := make([]*registration, len(current), len(current)+1)
+	copy(handlers, current)
+	handlers = append(handlers, reg)
+	sort.SliceStable(handlers, func(i, j int) bool {
+		if handlers[i].priority != handlers[j].priority {
+			return handlers[i].priority < handlers[j].priority
+		}
+		return handlers[i].seq < handlers[j].seq
+	})
+	b.handlers.Store(&handlers)
+}
+
+// Dispatch runs every matching handler. A failing or panicking handler never prevents the next one from running.
+func (b *Bus) Dispatch(ctx context.Context, meta Meta, evt any) {
+	for _, reg := range *b.handlers.Load() {
+		if err := b.run(ctx, reg, meta, evt); errors.Is(err, ErrStopPropagation) {
+			logrus.Debugf("[EVENT_BUS] %s stopped propagation of %T", reg.name, evt)
+			return
+		}
+	}
+}
+
+func (b *Bus) run(ctx context.Context, reg *registration, meta Meta, evt any) (err error) {
+	start := time.Now()
+	matched := true
+	panicked := false
+
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			err = fmt.Errorf("panic: %v", r)
+			logrus.Errorf("[EVENT_BUS] handler %s panicked on %T: %v\n%s", reg.name, evt, r, debug.Stack())
+		}
+		if !matched {
+			return
+		}
+
+		elapsed := time.Since(start)
+		reg.record(elapsed, err, panicked)
+		if b.SlowThreshold > 0 && elapsed > b.SlowThreshold {
+			logrus.Warnf("[EVENT_BUS] slow handler %s took %s on %T", reg.name, elapsed, evt)
+		}
+	}()
+
+	matched, err = reg.invoke(ctx, meta, evt)
+	if err != nil && !errors.Is(err, ErrStopPropagation) {
+		logrus.Errorf("[EVENT_BUS] handler %s failed on %T: %v", reg.name, evt, err)
+	}
+	return err
+}
+
+func (r *registration) record(elapsed time.Duration, err error, panicked bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	r.total += elapsed
+	r.last = elapsed
+	if elapsed > r.max {
+		r.max = elapsed
+	}
+	if panicked {
+		r.panics++
+	}
+	if err != nil && !errors.Is(err, ErrStopPropagation) {
+		r.errors++
+		r.lastError = err.Error()
+	}
+}
+
+// Stats returns the timing of every handler in dispatch order
+func (b *Bus) Stats() []HandlerStats {
+	handlers := *b.handlers.Load()
+	stats := make([]HandlerStats, 0, len(handlers))
+	for _, reg := range handlers {
+		reg.mu.Lock()
+		s := HandlerStats{
+			Name:      reg.name,
+			EventType: reg.eventType,
+			Priority:  reg.priority,
+			Calls:     reg.calls,
+			Errors:    reg.errors,
+			Panics:    reg.panics,
+			TotalMs:   toMs(reg.total),
+			MaxMs:     toMs(reg.max),
+			LastMs:    toMs(reg.last),
+			LastError: reg.lastError,
+		}
+		if reg.calls > 0 {
+			s.AvgMs = toMs(reg.total / time.Duration(reg.calls))
+		}
+		reg.mu.Unlock()
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
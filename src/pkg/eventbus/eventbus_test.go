@@ -0,0 +1,150 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type textEvent struct{ Text string }
+
+type otherEvent struct{}
+
+func TestDispatchOrderAndFilter(t *testing.T) {
+	bus := New()
+	var calls []string
+
+	record := func(name string) func(context.Context, Meta, *textEvent) error {
+		return func(context.Context, Meta, *textEvent) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	Register(bus, Handler[*textEvent]{Name: "late", Priority: 900, Handle: record("late")})
+	Register(bus, Handler[*textEvent]{Name: "early", Priority: 0, Handle: record("early")})
+	Register(bus, Handler[*textEvent]{Name: "same-priority-second", Priority: 900, Handle: record("same-priority-second")})
+	Register(bus, Handler[*textEvent]{
+		Name:     "accounts-only",
+		Priority: 100,
+		Filter:   func(meta Meta, _ *textEvent) bool { return !meta.IsDefault() },
+		Handle:   record("accounts-only"),
+	})
+	Register(bus, Handler[*otherEvent]{
+		Name: "other",
+		Handle: func(context.Context, Meta, *otherEvent) error {
+			calls = append(calls, "other")
+			return nil
+		},
+	})
+
+	bus.Dispatch(context.Background(), Meta{}, &textEvent{})
+
+	want := []string{"early", "late", "same-priority-second"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestDispatchIsolatesPanicsAndErrors(t *testing.T) {
+	bus := New()
+	reached := false
+
+	Register(bus, Handler[*textEvent]{
+		Name:     "panics",
+		Priority: 0,
+		Handle: func(context.Context, Meta, *textEvent) error {
+			panic("boom")
+		},
+	})
+	Register(bus, Handler[*textEvent]{
+		Name:     "fails",
+		Priority: 1,
+		Handle: func(context.Context, Meta, *textEvent) error {
+			return errors.New("failed")
+		},
+	})
+	Register(bus, Handler[*textEvent]{
+		Name:     "runs",
+		Priority: 2,
+		Handle: func(context.Context, Meta, *textEvent) error {
+			reached = true
+			return nil
+		},
+	})
+
+	bus.Dispatch(context.Background(), Meta{}, &textEvent{})
+
+	if !reached {
+		t.Fatal("expected handler after a panicking and a failing handler to run")
+	}
+
+	stats := bus.Stats()
+	if stats[0].Name != "panics" || stats[0].Panics != 1 || stats[0].Errors != 1 || stats[0].Calls != 1 {
+		t.Errorf("unexpected stats for panicking handler: %+v", stats[0])
+	}
+	if stats[1].Errors != 1 || stats[1].LastError != "failed" {
+		t.Errorf("unexpected stats for failing handler: %+v", stats[1])
+	}
+	if stats[2].Calls != 1 || stats[2].Errors != 0 {
+		t.Errorf("unexpected stats for healthy handler: %+v", stats[2])
+	}
+}
+
+func TestDispatchStopPropagation(t *testing.T) {
+	bus := New()
+	reached := false
+
+	Register(bus, Handler[*textEvent]{
+		Name:     "stops",
+		Priority: 0,
+		Handle: func(context.Context, Meta, *textEvent) error {
+			return ErrStopPropagation
+		},
+	})
+	Register(bus, Handler[*textEvent]{
+		Name:     "skipped",
+		Priority: 1,
+		Handle: func(context.Context, Meta, *textEvent) error {
+			reached = true
+			return nil
+		},
+	})
+
+	bus.Dispatch(context.Background(), Meta{}, &textEvent{})
+
+	if reached {
+		t.Fatal("expected ErrStopPropagation to skip the remaining handlers")
+	}
+	if stats := bus.Stats(); stats[0].Errors != 0 {
+		t.Errorf("ErrStopPropagation must not count as an error: %+v", stats[0])
+	}
+}
+
+func TestRegisterInterfaceType(t *testing.T) {
+	bus := New()
+	var seen []any
+
+	Register(bus, Handler[any]{
+		Name: "everything",
+		Handle: func(_ context.Context, _ Meta, evt any) error {
+			seen = append(seen, evt)
+			return nil
+		},
+	})
+
+	bus.Dispatch(context.Background(), Meta{}, &textEvent{})
+	bus.Dispatch(context.Background(), Meta{}, &otherEvent{})
+
+	if len(seen) != 2 {
+		t.Fatalf("expected any handler to see both events, got %d", len(seen))
+	}
+	if stats := bus.Stats(); stats[0].EventType != "any" {
+		t.Errorf("event type = %q, want any", stats[0].EventType)
+	}
+}
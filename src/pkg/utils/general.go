@@ -439,6 +439,55 @@ func DownloadVideoFromURL(videoURL string) ([]byte, string, error) {
 	return videoData, fileName, nil
 }
 
+// DownloadFileFromURL downloads a document of any type from the provided URL and returns the bytes and the file
+// name taken from the URL path. The size is limited to WhatsappSettingMaxFileSize.
+func DownloadFileFromURL(fileURL string) ([]byte, string, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(fileURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("HTTP request failed with status: %s", resp.Status)
+	}
+
+	maxSize := config.WhatsappSettingMaxFileSize
+	if resp.ContentLength > maxSize {
+		return nil, "", fmt.Errorf("file size %d exceeds maximum allowed size %d", resp.ContentLength, maxSize)
+	}
+
+	limitedReader := &io.LimitedReader{R: resp.Body, N: maxSize + 1}
+	fileData, err := io.ReadAll(limitedReader)
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(fileData)) > maxSize {
+		return nil, "", fmt.Errorf("downloaded file size of %d bytes exceeds the maximum allowed size of %d bytes", len(fileData), maxSize)
+	}
+
+	// Derive filename from URL path
+	fileName := ""
+	if parsed, err := url.Parse(fileURL); err == nil {
+		fileName = filepath.Base(parsed.Path)
+	}
+	if fileName == "" || fileName == "." || fileName == "/" {
+		fileName = fmt.Sprintf("file_%d", time.Now().Unix())
+	}
+
+	return fileData, fileName, nil
+}
+
 // FormatBusinessHourTime converts numeric time format (e.g., 600, 1200) to HH:MM format (e.g., "06:00", "12:00")
 func FormatBusinessHourTime(timeValue any) string {
 	var timeInt int
@@ -307,6 +307,51 @@ func ExtractMediaInfo(msg *waE2E.Message) (mediaType string, filename string, ur
 	return "", "", "", nil, nil, nil, 0
 }
 
+// ExtractMediaDetails extracts the MIME type and direct path of the media in a WhatsApp message
+func ExtractMediaDetails(msg *waE2E.Message) (mimetype string, directPath string) {
+	var media interface {
+		GetMimetype() string
+		GetDirectPath() string
+	}
+
+	switch {
+	case msg.GetImageMessage() != nil:
+		media = msg.GetImageMessage()
+	case msg.GetVideoMessage() != nil:
+		media = msg.GetVideoMessage()
+	case msg.GetAudioMessage() != nil:
+		media = msg.GetAudioMessage()
+	case msg.GetDocumentMessage() != nil:
+		media = msg.GetDocumentMessage()
+	case msg.GetStickerMessage() != nil:
+		media = msg.GetStickerMessage()
+	default:
+		return "", ""
+	}
+
+	return media.GetMimetype(), media.GetDirectPath()
+}
+
+// ExtractForwardingScore extracts how many times a WhatsApp message has been forwarded, 0 if it never was
+func ExtractForwardingScore(msg *waE2E.Message) uint32 {
+	contextInfos := []*waE2E.ContextInfo{
+		msg.GetExtendedTextMessage().GetContextInfo(),
+		msg.GetImageMessage().GetContextInfo(),
+		msg.GetVideoMessage().GetContextInfo(),
+		msg.GetAudioMessage().GetContextInfo(),
+		msg.GetDocumentMessage().GetContextInfo(),
+		msg.GetStickerMessage().GetContextInfo(),
+		msg.GetContactMessage().GetContextInfo(),
+		msg.GetLocationMessage().GetContextInfo(),
+	}
+	for _, contextInfo := range contextInfos {
+		if contextInfo != nil {
+			return contextInfo.GetForwardingScore()
+		}
+	}
+	return 0
+}
+
 // ExtractEphemeralExpiration extracts ephemeral expiration from a WhatsApp message
 func ExtractEphemeralExpiration(msg *waE2E.Message) uint32 {
 	logrus.Debug("ExtractEphemeralExpiration: Starting extraction process")
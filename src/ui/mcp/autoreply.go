@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type AutoReplyHandler struct {
+	autoReplyService domainAutoReply.IAutoReplyUsecase
+}
+
+func InitMcpAutoReply(autoReplyService domainAutoReply.IAutoReplyUsecase) *AutoReplyHandler {
+	return &AutoReplyHandler{autoReplyService: autoReplyService}
+}
+
+func (h *AutoReplyHandler) AddAutoReplyTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListRules(), h.handleListRules)
+	mcpServer.AddTool(h.toolSaveRule("whatsapp_autoreply_create_rule", "Create Auto-Reply Rule", false), h.handleCreateRule)
+	mcpServer.AddTool(h.toolSaveRule("whatsapp_autoreply_update_rule", "Update Auto-Reply Rule", true), h.handleUpdateRule)
+	mcpServer.AddTool(h.toolDeleteRule(), h.handleDeleteRule)
+	mcpServer.AddTool(h.toolStats(), h.handleStats)
+}
+
+func (h *AutoReplyHandler) toolListRules() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_autoreply_list_rules",
+		mcp.WithDescription("List the auto-reply rules that answer incoming messages, in evaluation order."),
+		mcp.WithTitleAnnotation("List Auto-Reply Rules"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *AutoReplyHandler) handleListRules(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rules, err := h.autoReplyService.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d auto-reply rules", len(rules))
+	return mcp.NewToolResultStructured(map[string]any{"rules": rules}, fallback), nil
+}
+
+// toolSaveRule describes the create and update tools, they only differ in the rule_id argument
+func (h *AutoReplyHandler) toolSaveRule(name, title string, update bool) mcp.Tool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Save an auto-reply rule. The first enabled rule (lowest priority) whose trigger matches an incoming text replies to it."),
+		mcp.WithTitleAnnotation(title),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(update),
+		mcp.WithIdempotentHintAnnotation(update),
+	}
+	if update {
+		options = append(options, mcp.WithString("rule_id",
+			mcp.Description("ID of the rule to replace."),
+			mcp.Required(),
+		))
+	}
+	options = append(options,
+		mcp.WithString("name",
+			mcp.Description("Rule name."),
+			mcp.Required(),
+		),
+		mcp.WithString("trigger_type",
+			mcp.Description("keyword, regex or exact."),
+			mcp.Enum(domainAutoReply.TriggerKeyword, domainAutoReply.TriggerRegex, domainAutoReply.TriggerExact),
+			mcp.Required(),
+		),
+		mcp.WithArray("keywords",
+			mcp.Description("Words that trigger a keyword rule."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("pattern",
+			mcp.Description("Regular expression of a regex rule, or the whole message of an exact rule."),
+		),
+		mcp.WithBoolean("case_sensitive",
+			mcp.Description("Match case sensitively (default: false)."),
+		),
+		mcp.WithString("reply",
+			mcp.Description("Reply text, {{pushname}}, {{phone}} and {{message}} are replaced. Caption of image and video replies."),
+		),
+		mcp.WithString("media_type",
+			mcp.Description("Reply with media: image, video or audio."),
+			mcp.Enum(domainAutoReply.MediaImage, domainAutoReply.MediaVideo, domainAutoReply.MediaAudio),
+		),
+		mcp.WithString("media_url",
+			mcp.Description("URL of the media to reply with."),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("Lower priorities are evaluated first (default: 0)."),
+		),
+		mcp.WithNumber("cooldown_seconds",
+			mcp.Description("Seconds the rule stays silent for a contact after replying (default: 0)."),
+		),
+		mcp.WithString("account_id",
+			mcp.Description("Limit the rule to one account, 'default' is the single-device client."),
+		),
+		mcp.WithString("chat_jid",
+			mcp.Description("Limit the rule to one chat. Groups are only answered by rules scoped to them."),
+		),
+		mcp.WithBoolean("enabled",
+			mcp.Description("Whether the rule is active (default: true)."),
+		),
+	)
+	return mcp.NewTool(name, options...)
+}
+
+func ruleRequestFromArguments(request mcp.CallToolRequest) domainAutoReply.RuleRequest {
+	enabled := request.GetBool("enabled", true)
+	return domainAutoReply.RuleRequest{
+		Name:            strings.TrimSpace(request.GetString("name", "")),
+		Priority:        request.GetInt("priority", 0),
+		Enabled:         &enabled,
+		AccountID:       strings.TrimSpace(request.GetString("account_id", "")),
+		ChatJID:         strings.TrimSpace(request.GetString("chat_jid", "")),
+		TriggerType:     request.GetString("trigger_type", ""),
+		Keywords:        request.GetStringSlice("keywords", nil),
+		Pattern:         request.GetString("pattern", ""),
+		CaseSensitive:   request.GetBool("case_sensitive", false),
+		Reply:           request.GetString("reply", ""),
+		MediaType:       request.GetString("media_type", ""),
+		MediaURL:        strings.TrimSpace(request.GetString("media_url", "")),
+		CooldownSeconds: request.GetInt("cooldown_seconds", 0),
+	}
+}
+
+func (h *AutoReplyHandler) handleCreateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rule, err := h.autoReplyService.CreateRule(ctx, ruleRequestFromArguments(request))
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Created auto-reply rule %s (%s)", rule.Name, rule.ID)
+	return mcp.NewToolResultStructured(rule, fallback), nil
+}
+
+func (h *AutoReplyHandler) handleUpdateRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, err := request.RequireString("rule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := h.autoReplyService.UpdateRule(ctx, strings.TrimSpace(ruleID), ruleRequestFromArguments(request))
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Updated auto-reply rule %s (%s)", rule.Name, rule.ID)
+	return mcp.NewToolResultStructured(rule, fallback), nil
+}
+
+func (h *AutoReplyHandler) toolDeleteRule() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_autoreply_delete_rule",
+		mcp.WithDescription("Delete an auto-reply rule, its recorded matches are kept."),
+		mcp.WithTitleAnnotation("Delete Auto-Reply Rule"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("rule_id",
+			mcp.Description("ID of the rule to delete."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *AutoReplyHandler) handleDeleteRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, err := request.RequireString("rule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.autoReplyService.DeleteRule(ctx, strings.TrimSpace(ruleID)); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted auto-reply rule %s", ruleID)), nil
+}
+
+func (h *AutoReplyHandler) toolStats() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_autoreply_stats",
+		mcp.WithDescription("Show how often every auto-reply rule matched, replied, failed or was held back by its cooldown."),
+		mcp.WithTitleAnnotation("Auto-Reply Statistics"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *AutoReplyHandler) handleStats(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	stats, err := h.autoReplyService.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("%d auto-reply rules matched at least once", len(stats))
+	return mcp.NewToolResultStructured(map[string]any{"rules": stats}, fallback), nil
+}
@@ -22,6 +22,7 @@ func InitRestApp(app fiber.Router, service domainApp.IAppUsecase) App {
 	app.Get("/app/reconnect", rest.Reconnect)
 	app.Get("/app/devices", rest.Devices)
 	app.Get("/app/status", rest.ConnectionStatus)
+	app.Get("/app/event-handlers", rest.EventHandlers)
 
 	return App{Service: service}
 }
@@ -105,3 +106,12 @@ func (handler *App) ConnectionStatus(c *fiber.Ctx) error {
 		},
 	})
 }
+
+func (handler *App) EventHandlers(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Event handler stats retrieved",
+		Results: whatsapp.EventHandlerStats(),
+	})
+}
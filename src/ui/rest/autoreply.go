@@ -0,0 +1,178 @@
+package rest
+
+import (
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AutoReply struct {
+	Service domainAutoReply.IAutoReplyUsecase
+}
+
+func InitRestAutoReply(app fiber.Router, service domainAutoReply.IAutoReplyUsecase) AutoReply {
+	rest := AutoReply{Service: service}
+	app.Get("/autoreply/rules", rest.ListRules)
+	app.Post("/autoreply/rules", rest.CreateRule)
+	app.Get("/autoreply/rules/:rule_id", rest.GetRule)
+	app.Put("/autoreply/rules/:rule_id", rest.UpdateRule)
+	app.Delete("/autoreply/rules/:rule_id", rest.DeleteRule)
+
+	// Business hours
+	app.Get("/autoreply/schedules", rest.ListSchedules)
+	app.Get("/autoreply/schedules/:account_id", rest.GetSchedule)
+	app.Put("/autoreply/schedules/:account_id", rest.SaveSchedule)
+	app.Delete("/autoreply/schedules/:account_id", rest.DeleteSchedule)
+
+	// Usage
+	app.Get("/autoreply/matches", rest.ListMatches)
+	app.Get("/autoreply/stats", rest.Stats)
+
+	return rest
+}
+
+func (handler *AutoReply) ListRules(c *fiber.Ctx) error {
+	rules, err := handler.Service.ListRules(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rules retrieved",
+		Results: rules,
+	})
+}
+
+func (handler *AutoReply) GetRule(c *fiber.Ctx) error {
+	rule, err := handler.Service.GetRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule retrieved",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) CreateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule created",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) UpdateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.UpdateRule(c.UserContext(), c.Params("rule_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule updated",
+		Results: rule,
+	})
+}
+
+func (handler *AutoReply) DeleteRule(c *fiber.Ctx) error {
+	err := handler.Service.DeleteRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule deleted",
+	})
+}
+
+func (handler *AutoReply) ListSchedules(c *fiber.Ctx) error {
+	schedules, err := handler.Service.ListSchedules(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Business hours retrieved",
+		Results: schedules,
+	})
+}
+
+func (handler *AutoReply) GetSchedule(c *fiber.Ctx) error {
+	schedule, err := handler.Service.GetSchedule(c.UserContext(), c.Params("account_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Business hours retrieved",
+		Results: schedule,
+	})
+}
+
+func (handler *AutoReply) SaveSchedule(c *fiber.Ctx) error {
+	var request domainAutoReply.ScheduleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	schedule, err := handler.Service.SaveSchedule(c.UserContext(), c.Params("account_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Business hours saved",
+		Results: schedule,
+	})
+}
+
+func (handler *AutoReply) DeleteSchedule(c *fiber.Ctx) error {
+	err := handler.Service.DeleteSchedule(c.UserContext(), c.Params("account_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Business hours deleted",
+	})
+}
+
+func (handler *AutoReply) ListMatches(c *fiber.Ctx) error {
+	var filter domainAutoReply.MatchFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	matches, err := handler.Service.ListMatches(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply matches retrieved",
+		Results: matches,
+	})
+}
+
+func (handler *AutoReply) Stats(c *fiber.Ctx) error {
+	stats, err := handler.Service.Stats(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply statistics retrieved",
+		Results: stats,
+	})
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Bulk struct {
+	Service domainBulk.IBulkUsecase
+}
+
+func InitRestBulk(app fiber.Router, service domainBulk.IBulkUsecase) Bulk {
+	rest := Bulk{Service: service}
+
+	app.Post("/send/bulk", rest.CreateJob)
+	app.Get("/send/bulk", rest.ListJobs)
+	app.Get("/send/bulk/:job_id", rest.GetJob)
+	app.Get("/send/bulk/:job_id/recipients", rest.ListRecipients)
+	app.Post("/send/bulk/:job_id/pause", rest.PauseJob)
+	app.Post("/send/bulk/:job_id/resume", rest.ResumeJob)
+	app.Post("/send/bulk/:job_id/cancel", rest.CancelJob)
+
+	return rest
+}
+
+// CreateJob accepts a JSON body, or a multipart form with the recipients as a CSV file and the payload as a
+// JSON string
+func (handler *Bulk) CreateJob(c *fiber.Ctx) error {
+	var request domainBulk.JobRequest
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		var err error
+		request, err = bulkFormRequest(c)
+		utils.PanicIfNeeded(err)
+	} else {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	job, err := handler.Service.CreateJob(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send job created",
+		Results: job,
+	})
+}
+
+func bulkFormRequest(c *fiber.Ctx) (request domainBulk.JobRequest, err error) {
+	request = domainBulk.JobRequest{
+		AccountID: c.FormValue("account_id"),
+		Type:      c.FormValue("type"),
+		Payload:   json.RawMessage(c.FormValue("payload")),
+	}
+	if request.IntervalMs, err = formInt(c, "interval_ms"); err != nil {
+		return request, err
+	}
+	if request.JitterMs, err = formInt(c, "jitter_ms"); err != nil {
+		return request, err
+	}
+
+	file, err := c.FormFile("recipients")
+	if err != nil {
+		return request, pkgError.ValidationError("recipients: upload a CSV file with one phone number per row.")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return request, err
+	}
+	defer reader.Close()
+
+	if request.Recipients, err = domainBulk.ParseRecipientsCSV(reader); err != nil {
+		return request, pkgError.ValidationError(fmt.Sprintf("recipients: %v.", err))
+	}
+	return request, nil
+}
+
+// formInt returns the integer form value, nil when it is missing
+func formInt(c *fiber.Ctx, field string) (*int, error) {
+	value := c.FormValue(field)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, pkgError.ValidationError(field + ": must be an integer.")
+	}
+	return &parsed, nil
+}
+
+func (handler *Bulk) ListJobs(c *fiber.Ctx) error {
+	var filter domainBulk.JobFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	jobs, err := handler.Service.ListJobs(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send jobs retrieved",
+		Results: jobs,
+	})
+}
+
+func (handler *Bulk) GetJob(c *fiber.Ctx) error {
+	job, err := handler.Service.GetJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send job retrieved",
+		Results: job,
+	})
+}
+
+func (handler *Bulk) ListRecipients(c *fiber.Ctx) error {
+	var filter domainBulk.RecipientFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	recipients, err := handler.Service.ListRecipients(c.UserContext(), c.Params("job_id"), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send recipients retrieved",
+		Results: recipients,
+	})
+}
+
+func (handler *Bulk) PauseJob(c *fiber.Ctx) error {
+	job, err := handler.Service.PauseJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send job paused",
+		Results: job,
+	})
+}
+
+func (handler *Bulk) ResumeJob(c *fiber.Ctx) error {
+	job, err := handler.Service.ResumeJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send job resumed",
+		Results: job,
+	})
+}
+
+func (handler *Bulk) CancelJob(c *fiber.Ctx) error {
+	job, err := handler.Service.CancelJob(c.UserContext(), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Bulk send job cancelled",
+		Results: job,
+	})
+}
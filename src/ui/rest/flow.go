@@ -0,0 +1,115 @@
+package rest
+
+import (
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Flow struct {
+	Service domainFlow.IFlowUsecase
+}
+
+func InitRestFlow(app fiber.Router, service domainFlow.IFlowUsecase) Flow {
+	rest := Flow{Service: service}
+
+	// Sessions are registered first so they aren't taken for a flow ID
+	app.Get("/flows/sessions", rest.ListSessions)
+	app.Delete("/flows/sessions/:account_id/:contact_jid", rest.ReleaseSession)
+
+	app.Get("/flows", rest.ListFlows)
+	app.Post("/flows", rest.CreateFlow)
+	app.Get("/flows/:flow_id", rest.GetFlow)
+	app.Put("/flows/:flow_id", rest.UpdateFlow)
+	app.Delete("/flows/:flow_id", rest.DeleteFlow)
+
+	return rest
+}
+
+func (handler *Flow) ListFlows(c *fiber.Ctx) error {
+	flows, err := handler.Service.ListFlows(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flows retrieved",
+		Results: flows,
+	})
+}
+
+func (handler *Flow) GetFlow(c *fiber.Ctx) error {
+	flow, err := handler.Service.GetFlow(c.UserContext(), c.Params("flow_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow retrieved",
+		Results: flow,
+	})
+}
+
+// CreateFlow reads the definition from the raw body, YAML and JSON are both accepted
+func (handler *Flow) CreateFlow(c *fiber.Ctx) error {
+	flow, err := handler.Service.CreateFlow(c.UserContext(), c.Body())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow created",
+		Results: flow,
+	})
+}
+
+func (handler *Flow) UpdateFlow(c *fiber.Ctx) error {
+	flow, err := handler.Service.UpdateFlow(c.UserContext(), c.Params("flow_id"), c.Body())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow updated",
+		Results: flow,
+	})
+}
+
+func (handler *Flow) DeleteFlow(c *fiber.Ctx) error {
+	err := handler.Service.DeleteFlow(c.UserContext(), c.Params("flow_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow deleted",
+	})
+}
+
+func (handler *Flow) ListSessions(c *fiber.Ctx) error {
+	var filter domainFlow.SessionFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	sessions, err := handler.Service.ListSessions(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow sessions retrieved",
+		Results: sessions,
+	})
+}
+
+// ReleaseSession ends the session of a contact, e.g. when a human finished a handoff
+func (handler *Flow) ReleaseSession(c *fiber.Ctx) error {
+	err := handler.Service.ReleaseSession(c.UserContext(), c.Params("account_id"), c.Params("contact_jid"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Flow session released",
+	})
+}
@@ -2,16 +2,19 @@ package rest
 
 import (
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
 type Message struct {
 	Service domainMessage.IMessageUsecase
+	// SendService forwards stored messages
+	SendService domainSend.ISendUsecase
 }
 
-func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Message {
-	rest := Message{Service: service}
+func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase, sendService domainSend.ISendUsecase) Message {
+	rest := Message{Service: service, SendService: sendService}
 
 	// Message action endpoints
 	app.Post("/message/:message_id/reaction", rest.ReactMessage)
@@ -21,6 +24,7 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 	app.Post("/message/:message_id/read", rest.MarkAsRead)
 	app.Post("/message/:message_id/star", rest.StarMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
+	app.Post("/message/:message_id/forward", rest.ForwardMessage)
 	app.Get("/message/:message_id/download", rest.DownloadMedia)
 	return rest
 }
@@ -176,3 +180,24 @@ func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Message) ForwardMessage(c *fiber.Ctx) error {
+	var request domainSend.ForwardRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	for i := range request.Phones {
+		utils.SanitizePhone(&request.Phones[i])
+	}
+
+	response, err := controller.SendService.ForwardMessage(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
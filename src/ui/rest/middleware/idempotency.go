@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// idempotencyPruneInterval is how often expired keys are removed
+const idempotencyPruneInterval = time.Hour
+
+// Idempotency replays the stored response when a POST repeats the Idempotency-Key of an earlier request with the
+// same payload, and fails with a conflict when the payload differs or the first request is still running. Only
+// successful responses are kept, a failed request frees its key so it can be retried.
+func Idempotency(repo domainIdempotency.IIdempotencyRepository, ttl time.Duration) fiber.Handler {
+	var (
+		pruneMu   sync.Mutex
+		lastPrune time.Time
+	)
+
+	return func(c *fiber.Ctx) error {
+		key := strings.TrimSpace(c.Get(domainIdempotency.Header))
+		if key == "" || c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+		if len(key) > domainIdempotency.MaxKeyLength {
+			panic(pkgError.ValidationError(fmt.Sprintf("%s must be at most %d characters", domainIdempotency.Header, domainIdempotency.MaxKeyLength)))
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		utils.PanicIfNeeded(err)
+
+		now := time.Now()
+		pruneMu.Lock()
+		if now.Sub(lastPrune) > idempotencyPruneInterval {
+			lastPrune = now
+			if err := repo.DeleteExpired(now); err != nil {
+				logrus.Warnf("Failed to prune idempotency keys: %v", err)
+			}
+		}
+		pruneMu.Unlock()
+
+		existing, reserved, err := repo.Reserve(&domainIdempotency.Record{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		})
+		utils.PanicIfNeeded(err)
+
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				panic(pkgError.ConflictError(fmt.Sprintf("%s %s was already used for a different request", domainIdempotency.Header, key)))
+			case existing.Pending():
+				panic(pkgError.ConflictError(fmt.Sprintf("the request with %s %s is still in progress", domainIdempotency.Header, key)))
+			}
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(existing.StatusCode).Send(existing.Response)
+		}
+
+		// The key is freed when the request panics or fails, a completed one is kept
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := repo.Release(key); err != nil {
+				logrus.Errorf("Failed to release idempotency key %s: %v", key, err)
+			}
+		}()
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err == nil && status >= 200 && status < 300 {
+			if err := repo.Complete(key, status, bytes.Clone(c.Response().Body())); err != nil {
+				logrus.Errorf("Failed to store the response of idempotency key %s: %v", key, err)
+			} else {
+				completed = true
+			}
+		}
+		return err
+	}
+}
+
+// requestFingerprint hashes the method, path and payload of a request. JSON is hashed in a canonical form and
+// multipart forms by their fields and file contents, so a client resending the same payload with another key order
+// or multipart boundary gets the same fingerprint.
+func requestFingerprint(c *fiber.Ctx) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", c.Method(), c.Path())
+
+	contentType := c.Get(fiber.HeaderContentType)
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		if err := hashMultipartForm(c, h); err != nil {
+			return "", err
+		}
+	case strings.HasPrefix(contentType, fiber.MIMEApplicationJSON):
+		decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+		decoder.UseNumber()
+		var payload any
+		if err := decoder.Decode(&payload); err != nil {
+			h.Write(c.Body())
+			break
+		}
+		canonical, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		h.Write(canonical)
+	default:
+		h.Write(c.Body())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashMultipartForm(c *fiber.Ctx, h hash.Hash) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("invalid multipart form: %v", err))
+	}
+
+	names := make([]string, 0, len(form.Value))
+	for name := range form.Value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "field %q %q\n", name, form.Value[name])
+	}
+
+	names = names[:0]
+	for name := range form.File {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, header := range form.File[name] {
+			fmt.Fprintf(h, "file %q %q %d\n", name, header.Filename, header.Size)
+			file, err := header.Open()
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(h, file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
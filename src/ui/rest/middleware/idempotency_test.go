@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]domainIdempotency.Record
+}
+
+func (r *memoryIdempotencyRepository) InitializeSchema() error { return nil }
+
+func (r *memoryIdempotencyRepository) Reserve(record *domainIdempotency.Record) (*domainIdempotency.Record, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[record.Key]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return &existing, false, nil
+	}
+	r.records[record.Key] = *record
+	return nil, true, nil
+}
+
+func (r *memoryIdempotencyRepository) Complete(key string, statusCode int, response []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.records[key]
+	record.StatusCode, record.Response = statusCode, response
+	r.records[key] = record
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) Release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.records[key].Pending() {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyRepository) DeleteExpired(time.Time) error { return nil }
+
+func TestIdempotency(t *testing.T) {
+	sends := 0
+	app := fiber.New()
+	app.Use(Recovery())
+	idempotency := Idempotency(&memoryIdempotencyRepository{records: map[string]domainIdempotency.Record{}}, time.Hour)
+	app.Use("/send", idempotency)
+	app.Use("/message/:message_id/forward", idempotency)
+	send := func(c *fiber.Ctx) error {
+		if strings.Contains(string(c.Body()), "offline") {
+			panic(pkgError.InternalServerError("not connected"))
+		}
+		sends++
+		return c.JSON(utils.ResponseData{Status: 200, Code: "SUCCESS", Results: map[string]int{"send": sends}})
+	}
+	app.Post("/send/message", send)
+	app.Post("/message/:message_id/forward", send)
+
+	request := func(path, key, body string) (int, string, string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(domainIdempotency.Header, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(respBody), resp.Header.Get("Idempotent-Replayed")
+	}
+
+	status, first, _ := request("/send/message", "order-1", `{"phone": "6281234567890", "message": "Shipped"}`)
+	assert.Equal(t, 200, status)
+
+	// The same payload with another key order is replayed without sending again
+	status, replayed, header := request("/send/message", "order-1", `{"message": "Shipped", "phone": "6281234567890"}`)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, first, replayed)
+	assert.Equal(t, "true", header)
+	assert.Equal(t, 1, sends)
+
+	status, body, _ := request("/send/message", "order-1", `{"phone": "6281234567890", "message": "Delivered"}`)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Contains(t, body, "CONFLICT")
+	assert.Equal(t, 1, sends)
+
+	// A failed send frees its key for a retry
+	status, _, _ = request("/send/message", "order-2", `{"phone": "6281234567890", "message": "offline"}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	status, _, header = request("/send/message", "order-2", `{"phone": "6281234567890", "message": "Retry"}`)
+	assert.Equal(t, 200, status)
+	assert.Empty(t, header)
+	assert.Equal(t, 2, sends)
+
+	// The same key on another endpoint is a different request
+	status, _, _ = request("/message/3EB0C127D7BACC83D6A1/forward", "order-1", `{"phone": "6281234567890", "message": "Shipped"}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _, _ = request("/message/3EB0C127D7BACC83D6A1/forward", "forward-1", `{"phones": ["6281234567890"]}`)
+	assert.Equal(t, 200, status)
+	status, _, header = request("/message/3EB0C127D7BACC83D6A1/forward", "forward-1", `{"phones": ["6281234567890"]}`)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "true", header)
+	assert.Equal(t, 3, sends)
+
+	// Requests without a key are never deduplicated
+	request("/send/message", "", `{"phone": "6281234567890", "message": "Shipped"}`)
+	request("/send/message", "", `{"phone": "6281234567890", "message": "Shipped"}`)
+	assert.Equal(t, 5, sends)
+}
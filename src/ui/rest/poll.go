@@ -0,0 +1,31 @@
+package rest
+
+import (
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Poll struct {
+	Service domainPoll.IPollUsecase
+}
+
+func InitRestPoll(app fiber.Router, service domainPoll.IPollUsecase) Poll {
+	rest := Poll{Service: service}
+
+	app.Get("/poll/:message_id/results", rest.GetResults)
+
+	return rest
+}
+
+func (handler *Poll) GetResults(c *fiber.Ctx) error {
+	results, err := handler.Service.GetResults(c.UserContext(), c.Params("message_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Poll results retrieved",
+		Results: results,
+	})
+}
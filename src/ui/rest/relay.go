@@ -0,0 +1,107 @@
+package rest
+
+import (
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Relay struct {
+	Service domainRelay.IRelayUsecase
+}
+
+func InitRestRelay(app fiber.Router, service domainRelay.IRelayUsecase) Relay {
+	rest := Relay{Service: service}
+
+	app.Get("/relay/rules", rest.ListRules)
+	app.Post("/relay/rules", rest.CreateRule)
+	app.Get("/relay/rules/:rule_id", rest.GetRule)
+	app.Put("/relay/rules/:rule_id", rest.UpdateRule)
+	app.Delete("/relay/rules/:rule_id", rest.DeleteRule)
+	app.Get("/relay/messages", rest.ListMessages)
+
+	return rest
+}
+
+func (handler *Relay) ListRules(c *fiber.Ctx) error {
+	rules, err := handler.Service.ListRules(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relay rules retrieved",
+		Results: rules,
+	})
+}
+
+func (handler *Relay) GetRule(c *fiber.Ctx) error {
+	rule, err := handler.Service.GetRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relay rule retrieved",
+		Results: rule,
+	})
+}
+
+func (handler *Relay) CreateRule(c *fiber.Ctx) error {
+	var request domainRelay.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relay rule created",
+		Results: rule,
+	})
+}
+
+func (handler *Relay) UpdateRule(c *fiber.Ctx) error {
+	var request domainRelay.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.UpdateRule(c.UserContext(), c.Params("rule_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relay rule updated",
+		Results: rule,
+	})
+}
+
+func (handler *Relay) DeleteRule(c *fiber.Ctx) error {
+	err := handler.Service.DeleteRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relay rule deleted",
+	})
+}
+
+func (handler *Relay) ListMessages(c *fiber.Ctx) error {
+	var filter domainRelay.MessageFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	messages, err := handler.Service.ListMessages(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Relayed messages retrieved",
+		Results: messages,
+	})
+}
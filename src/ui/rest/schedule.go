@@ -0,0 +1,79 @@
+package rest
+
+import (
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Schedule struct {
+	Service domainSchedule.IScheduleUsecase
+}
+
+// InitRestSchedule registers the scheduled messages, they are created by the send endpoints with send_at
+func InitRestSchedule(app fiber.Router, service domainSchedule.IScheduleUsecase) Schedule {
+	rest := Schedule{Service: service}
+
+	app.Get("/schedules", rest.ListSchedules)
+	app.Get("/schedules/:schedule_id", rest.GetSchedule)
+	app.Put("/schedules/:schedule_id", rest.UpdateSchedule)
+	app.Post("/schedules/:schedule_id/cancel", rest.CancelSchedule)
+
+	return rest
+}
+
+func (handler *Schedule) ListSchedules(c *fiber.Ctx) error {
+	var filter domainSchedule.Filter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	schedules, err := handler.Service.ListSchedules(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scheduled messages retrieved",
+		Results: schedules,
+	})
+}
+
+func (handler *Schedule) GetSchedule(c *fiber.Ctx) error {
+	schedule, err := handler.Service.GetSchedule(c.UserContext(), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scheduled message retrieved",
+		Results: schedule,
+	})
+}
+
+func (handler *Schedule) UpdateSchedule(c *fiber.Ctx) error {
+	var request domainSchedule.UpdateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	schedule, err := handler.Service.UpdateSchedule(c.UserContext(), c.Params("schedule_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scheduled message updated",
+		Results: schedule,
+	})
+}
+
+func (handler *Schedule) CancelSchedule(c *fiber.Ctx) error {
+	schedule, err := handler.Service.CancelSchedule(c.UserContext(), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scheduled message cancelled",
+		Results: schedule,
+	})
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Script struct {
+	Service domainScript.IScriptUsecase
+}
+
+func InitRestScript(app fiber.Router, service domainScript.IScriptUsecase) Script {
+	rest := Script{Service: service}
+
+	// Chat tags are registered first so they aren't taken for a script ID
+	app.Get("/scripts/tags", rest.ListChatTags)
+
+	app.Get("/scripts", rest.ListScripts)
+	app.Post("/scripts", rest.CreateScript)
+	app.Get("/scripts/:script_id", rest.GetScript)
+	app.Put("/scripts/:script_id", rest.UpdateScript)
+	app.Delete("/scripts/:script_id", rest.DeleteScript)
+	app.Get("/scripts/:script_id/errors", rest.ListErrors)
+
+	return rest
+}
+
+func (handler *Script) ListScripts(c *fiber.Ctx) error {
+	scripts, err := handler.Service.ListScripts(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scripts retrieved",
+		Results: scripts,
+	})
+}
+
+func (handler *Script) GetScript(c *fiber.Ctx) error {
+	script, err := handler.Service.GetScript(c.UserContext(), c.Params("script_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Script retrieved",
+		Results: script,
+	})
+}
+
+func (handler *Script) CreateScript(c *fiber.Ctx) error {
+	var request domainScript.ScriptRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	script, err := handler.Service.CreateScript(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Script created",
+		Results: script,
+	})
+}
+
+func (handler *Script) UpdateScript(c *fiber.Ctx) error {
+	var request domainScript.ScriptRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	script, err := handler.Service.UpdateScript(c.UserContext(), c.Params("script_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Script updated",
+		Results: script,
+	})
+}
+
+func (handler *Script) DeleteScript(c *fiber.Ctx) error {
+	err := handler.Service.DeleteScript(c.UserContext(), c.Params("script_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Script deleted",
+	})
+}
+
+func (handler *Script) ListErrors(c *fiber.Ctx) error {
+	var filter domainScript.ErrorFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	runErrors, err := handler.Service.ListErrors(c.UserContext(), c.Params("script_id"), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Script errors retrieved",
+		Results: runErrors,
+	})
+}
+
+func (handler *Script) ListChatTags(c *fiber.Ctx) error {
+	var filter domainScript.ChatTagFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	tags, err := handler.Service.ListChatTags(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Chat tags retrieved",
+		Results: tags,
+	})
+}
@@ -1,7 +1,12 @@
 package rest
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,6 +27,7 @@ func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	app.Post("/send/location", rest.SendLocation)
 	app.Post("/send/audio", rest.SendAudio)
 	app.Post("/send/poll", rest.SendPoll)
+	app.Post("/send/album", rest.SendAlbum)
 	app.Post("/send/presence", rest.SendPresence)
 	app.Post("/send/chat-presence", rest.SendChatPresence)
 	return rest
@@ -75,10 +81,9 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	file, err := c.FormFile("file")
-	utils.PanicIfNeeded(err)
-
-	request.File = file
+	if file, errFile := c.FormFile("file"); errFile == nil {
+		request.File = file
+	}
 	utils.SanitizePhone(&request.Phone)
 
 	response, err := controller.Service.SendFile(c.UserContext(), request)
@@ -233,6 +238,39 @@ func (controller *Send) SendPoll(c *fiber.Ctx) error {
 	})
 }
 
+// SendAlbum accepts a JSON body, or a multipart form with the items as a JSON string and the upload of item i in
+// the media_<i> field
+func (controller *Send) SendAlbum(c *fiber.Ctx) error {
+	var request domainSend.AlbumRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		if items := c.FormValue("items"); items != "" {
+			if err := json.Unmarshal([]byte(items), &request.Items); err != nil {
+				utils.PanicIfNeeded(pkgError.ValidationError(fmt.Sprintf("items: %v.", err)))
+			}
+		}
+		for i := range request.Items {
+			if file, errFile := c.FormFile(fmt.Sprintf("media_%d", i)); errFile == nil {
+				request.Items[i].File = file
+			}
+		}
+	}
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.SendAlbum(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendPresence(c *fiber.Ctx) error {
 	var request domainSend.PresenceRequest
 	err := c.BodyParser(&request)
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Status struct {
+	Service domainStatus.IStatusUsecase
+}
+
+func InitRestStatus(app fiber.Router, service domainStatus.IStatusUsecase) Status {
+	rest := Status{Service: service}
+
+	app.Post("/status", rest.PostStatus)
+	app.Get("/status", rest.ListStatuses)
+
+	return rest
+}
+
+func (handler *Status) PostStatus(c *fiber.Ctx) error {
+	var request domainStatus.PostRequest
+	request.Compress = true
+
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, errFile := c.FormFile("media"); errFile == nil {
+		request.Media = file
+	}
+	for i := range request.Phones {
+		utils.SanitizePhone(&request.Phones[i])
+	}
+
+	status, err := handler.Service.PostStatus(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Status posted",
+		Results: status,
+	})
+}
+
+func (handler *Status) ListStatuses(c *fiber.Ctx) error {
+	var filter domainStatus.Filter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	statuses, err := handler.Service.ListStatuses(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Statuses retrieved",
+		Results: statuses,
+	})
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Template struct {
+	Service domainTemplate.ITemplateUsecase
+}
+
+func InitRestTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+
+	app.Get("/templates", rest.ListTemplates)
+	app.Post("/templates", rest.CreateTemplate)
+	app.Get("/templates/:template_id", rest.GetTemplate)
+	app.Put("/templates/:template_id", rest.UpdateTemplate)
+	app.Delete("/templates/:template_id", rest.DeleteTemplate)
+	app.Get("/templates/:template_id/versions", rest.ListVersions)
+	app.Post("/templates/:template_id/render", rest.RenderTemplate)
+
+	return rest
+}
+
+func (handler *Template) ListTemplates(c *fiber.Ctx) error {
+	var filter domainTemplate.Filter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	templates, err := handler.Service.ListTemplates(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Templates retrieved",
+		Results: templates,
+	})
+}
+
+func (handler *Template) GetTemplate(c *fiber.Ctx) error {
+	template, err := handler.Service.GetTemplate(c.UserContext(), c.Params("template_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template retrieved",
+		Results: template,
+	})
+}
+
+func (handler *Template) CreateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	template, err := handler.Service.CreateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template created",
+		Results: template,
+	})
+}
+
+func (handler *Template) UpdateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	template, err := handler.Service.UpdateTemplate(c.UserContext(), c.Params("template_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template updated",
+		Results: template,
+	})
+}
+
+func (handler *Template) DeleteTemplate(c *fiber.Ctx) error {
+	err := handler.Service.DeleteTemplate(c.UserContext(), c.Params("template_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template deleted",
+	})
+}
+
+func (handler *Template) ListVersions(c *fiber.Ctx) error {
+	versions, err := handler.Service.ListVersions(c.UserContext(), c.Params("template_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template versions retrieved",
+		Results: versions,
+	})
+}
+
+// RenderTemplate previews a template without sending it
+func (handler *Template) RenderTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.RenderRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	request.TemplateID = c.Params("template_id")
+
+	_, rendered, err := handler.Service.Render(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template rendered",
+		Results: rendered,
+	})
+}
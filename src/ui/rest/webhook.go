@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+	app.Get("/webhook/schemas", rest.Schemas)
+	app.Get("/webhook/schemas/:event", rest.Schema)
+
+	// Routing rules
+	app.Get("/webhook/rules", rest.ListRules)
+	app.Post("/webhook/rules", rest.CreateRule)
+	app.Post("/webhook/rules/test", rest.TestRules)
+	app.Get("/webhook/rules/:rule_id", rest.GetRule)
+	app.Put("/webhook/rules/:rule_id", rest.UpdateRule)
+	app.Delete("/webhook/rules/:rule_id", rest.DeleteRule)
+
+	// Delivery log
+	app.Get("/webhook/deliveries", rest.ListDeliveries)
+	app.Get("/webhook/deliveries/:delivery_id", rest.GetDelivery)
+
+	return rest
+}
+
+// InitRestWebhookMedia serves media behind signed URLs. It is registered before basic auth because webhook
+// receivers only hold the signed URL.
+func InitRestWebhookMedia(app fiber.Router) {
+	app.Get(config.AppBasePath+whatsapp.WebhookMediaRoute+"/:filename", WebhookMedia)
+}
+
+func WebhookMedia(c *fiber.Ctx) error {
+	path, err := whatsapp.VerifyMediaSignature(c.Params("filename"), c.Query("expires"), c.Query("signature"), time.Now())
+	utils.PanicIfNeeded(err)
+
+	return c.SendFile(path)
+}
+
+func (handler *Webhook) Schemas(c *fiber.Ctx) error {
+	schemas := make([]map[string]any, 0)
+	for _, eventType := range domainWebhook.EventTypes() {
+		schemas = append(schemas, map[string]any{
+			"event":         eventType,
+			"event_version": domainWebhook.EventVersion,
+			"schema_url":    domainWebhook.SchemaURL(eventType),
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook schemas retrieved",
+		Results: schemas,
+	})
+}
+
+// Schema returns the raw JSON Schema so it can be fed to a validator directly
+func (handler *Webhook) Schema(c *fiber.Ctx) error {
+	eventType := c.Params("event")
+	body, err := domainWebhook.MarshalSchema(eventType)
+	if err != nil {
+		utils.PanicIfNeeded(pkgError.NotFoundError(fmt.Sprintf("no schema for event %s", eventType)))
+	}
+
+	c.Set(fiber.HeaderContentType, "application/schema+json")
+	return c.Send(body)
+}
+
+func (handler *Webhook) ListRules(c *fiber.Ctx) error {
+	rules, err := handler.Service.ListRules(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rules retrieved",
+		Results: rules,
+	})
+}
+
+func (handler *Webhook) GetRule(c *fiber.Ctx) error {
+	rule, err := handler.Service.GetRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rule retrieved",
+		Results: rule,
+	})
+}
+
+func (handler *Webhook) CreateRule(c *fiber.Ctx) error {
+	var request domainWebhook.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rule created",
+		Results: rule,
+	})
+}
+
+func (handler *Webhook) UpdateRule(c *fiber.Ctx) error {
+	var request domainWebhook.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	rule, err := handler.Service.UpdateRule(c.UserContext(), c.Params("rule_id"), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rule updated",
+		Results: rule,
+	})
+}
+
+func (handler *Webhook) DeleteRule(c *fiber.Ctx) error {
+	err := handler.Service.DeleteRule(c.UserContext(), c.Params("rule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rule deleted",
+	})
+}
+
+// TestRules shows which rules match a sample event and where it would be delivered
+func (handler *Webhook) TestRules(c *fiber.Ctx) error {
+	var subject domainWebhook.Subject
+	err := c.BodyParser(&subject)
+	utils.PanicIfNeeded(err)
+
+	decision, err := handler.Service.TestRules(c.UserContext(), subject)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook rules evaluated",
+		Results: decision,
+	})
+}
+
+func (handler *Webhook) ListDeliveries(c *fiber.Ctx) error {
+	var filter domainWebhook.DeliveryFilter
+	err := c.QueryParser(&filter)
+	utils.PanicIfNeeded(err)
+
+	deliveries, err := handler.Service.ListDeliveries(c.UserContext(), filter)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook deliveries retrieved",
+		Results: deliveries,
+	})
+}
+
+func (handler *Webhook) GetDelivery(c *fiber.Ctx) error {
+	delivery, err := handler.Service.GetDelivery(c.UserContext(), c.Params("delivery_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook delivery retrieved",
+		Results: delivery,
+	})
+}
@@ -0,0 +1,162 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const commandTimeout = 60 * time.Second
+
+// commandFunc executes a websocket command with its raw JSON params on behalf of the account of the connection
+type commandFunc func(ctx context.Context, accountID string, params json.RawMessage) (any, error)
+
+type commandHandler struct {
+	commands map[string]commandFunc
+}
+
+// newCommandHandler maps websocket command names onto the send and message usecases
+func newCommandHandler(sendService domainSend.ISendUsecase, messageService domainMessage.IMessageUsecase) *commandHandler {
+	return &commandHandler{
+		commands: map[string]commandFunc{
+			"send_text": bindCommand(sendService.SendText, func(r *domainSend.MessageRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"send_contact": bindCommand(sendService.SendContact, func(r *domainSend.ContactRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"send_link": bindCommand(sendService.SendLink, func(r *domainSend.LinkRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"send_location": bindCommand(sendService.SendLocation, func(r *domainSend.LocationRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"send_poll": bindCommand(sendService.SendPoll, func(r *domainSend.PollRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"send_chat_presence": bindCommand(sendService.SendChatPresence, func(r *domainSend.ChatPresenceRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return bindAccount(&r.AccountID, accountID)
+			}),
+			"mark_read": bindCommand(messageService.MarkAsRead, func(r *domainMessage.MarkAsReadRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return defaultAccountOnly(accountID)
+			}),
+			"react": bindCommand(messageService.ReactMessage, func(r *domainMessage.ReactionRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return defaultAccountOnly(accountID)
+			}),
+			"revoke": bindCommand(messageService.RevokeMessage, func(r *domainMessage.RevokeRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return defaultAccountOnly(accountID)
+			}),
+			"update_message": bindCommand(messageService.UpdateMessage, func(r *domainMessage.UpdateMessageRequest, accountID string) error {
+				utils.SanitizePhone(&r.Phone)
+				return defaultAccountOnly(accountID)
+			}),
+		},
+	}
+}
+
+// bindCommand decodes params into the usecase request type before calling it, prepare binds the request to the
+// account of the connection
+func bindCommand[Req any, Res any](fn func(context.Context, Req) (Res, error), prepare func(*Req, string) error) commandFunc {
+	return func(ctx context.Context, accountID string, params json.RawMessage) (any, error) {
+		var request Req
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &request); err != nil {
+				return nil, pkgError.ValidationError(fmt.Sprintf("invalid params: %v", err))
+			}
+		}
+		if err := prepare(&request, accountID); err != nil {
+			return nil, err
+		}
+		return fn(ctx, request)
+	}
+}
+
+// bindAccount sends a request from the account of the connection, a connection can't send as another account
+func bindAccount(requestAccountID *string, accountID string) error {
+	if *requestAccountID != "" && *requestAccountID != accountID {
+		return pkgError.ValidationError(fmt.Sprintf("account_id %s does not match the account of this connection", *requestAccountID))
+	}
+	*requestAccountID = accountID
+	return nil
+}
+
+// defaultAccountOnly rejects commands whose usecase always acts as the default device on an account connection
+func defaultAccountOnly(accountID string) error {
+	if accountID != DefaultHub {
+		return pkgError.ValidationError("this command is only available on the /ws connection of the default device")
+	}
+	return nil
+}
+
+// handle runs the command asynchronously and replies with the same correlation ID
+func (h *commandHandler) handle(c *Client, msg incomingMessage) {
+	if msg.ID == "" {
+		c.reply(BroadcastMessage{Code: "COMMAND_ERROR", Message: "command id is required"})
+		return
+	}
+
+	if !c.isAuthenticated() {
+		c.reply(BroadcastMessage{Code: "UNAUTHORIZED", ID: msg.ID, Message: "authenticate before sending commands"})
+		return
+	}
+
+	command, ok := h.commands[msg.Command]
+	if !ok {
+		c.reply(BroadcastMessage{Code: "COMMAND_ERROR", ID: msg.ID, Message: "unknown command: " + msg.Command})
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		result, err := runCommand(ctx, command, c.hub.accountID, msg.Params)
+		if err != nil {
+			errorCode := "INTERNAL_SERVER_ERROR"
+			if genericErr, ok := err.(pkgError.GenericError); ok {
+				errorCode = genericErr.ErrCode()
+			}
+			c.reply(BroadcastMessage{
+				Code:    "COMMAND_ERROR",
+				ID:      msg.ID,
+				Message: err.Error(),
+				Result:  map[string]string{"command": msg.Command, "error_code": errorCode},
+			})
+			return
+		}
+
+		c.reply(BroadcastMessage{
+			Code:    "COMMAND_RESULT",
+			ID:      msg.ID,
+			Message: msg.Command + " executed",
+			Result:  result,
+		})
+	}()
+}
+
+// runCommand shields the websocket loop from panics raised by the usecases
+func runCommand(ctx context.Context, command commandFunc, accountID string, params json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("websocket command panic: %v", r)
+			err = pkgError.InternalServerError(fmt.Sprintf("%v", r))
+		}
+	}()
+	return command(ctx, accountID, params)
+}
@@ -0,0 +1,41 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+)
+
+func TestBindCommandAccount(t *testing.T) {
+	var sent domainSend.MessageRequest
+	command := bindCommand(func(_ context.Context, request domainSend.MessageRequest) (any, error) {
+		sent = request
+		return nil, nil
+	}, func(r *domainSend.MessageRequest, accountID string) error {
+		return bindAccount(&r.AccountID, accountID)
+	})
+
+	if _, err := command(context.Background(), "a", json.RawMessage(`{"phone": "6281234567890", "message": "hi"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.AccountID != "a" {
+		t.Errorf("account_id = %q, want the account of the connection", sent.AccountID)
+	}
+
+	sent = domainSend.MessageRequest{}
+	if _, err := command(context.Background(), "a", json.RawMessage(`{"account_id": "b", "message": "hi"}`)); err == nil {
+		t.Error("expected another account_id to be rejected")
+	}
+	if _, err := command(context.Background(), DefaultHub, json.RawMessage(`{"account_id": "b", "message": "hi"}`)); err == nil {
+		t.Error("expected an account_id on the default connection to be rejected")
+	}
+	if sent.Message != "" {
+		t.Error("expected rejected commands not to be sent")
+	}
+
+	if err := defaultAccountOnly("a"); err == nil {
+		t.Error("expected default device commands to be rejected on an account connection")
+	}
+}
@@ -0,0 +1,254 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sendQueueSize is the number of frames buffered per connection before it is evicted
+	sendQueueSize = 64
+	// writeWait is the time allowed to write a single frame to the peer
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong from the peer
+	pongWait = 60 * time.Second
+	// pingPeriod must be shorter than pongWait so the peer can answer in time
+	pingPeriod = (pongWait * 9) / 10
+	// maxFrameSize limits incoming frames (commands may carry base64 payloads)
+	maxFrameSize = 1 << 20
+)
+
+// DefaultHub is the key of the hub serving the legacy single-device /ws endpoint
+const DefaultHub = ""
+
+// Hub fans websocket frames out to the connections of one account
+type Hub struct {
+	accountID string
+	mu        sync.RWMutex
+	clients   map[*Client]struct{}
+}
+
+// Client is a single websocket connection with its own send queue and writer goroutine
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	done chan struct{}
+	// stopped is closed once the writer goroutine no longer touches the connection
+	stopped chan struct{}
+
+	closeOnce sync.Once
+
+	mu            sync.Mutex
+	authenticated bool
+	topics        map[string]bool
+}
+
+var (
+	hubsMu sync.RWMutex
+	hubs   = make(map[string]*Hub)
+)
+
+// register adds a connection to the hub of an account, creating the hub with its first connection.
+// DefaultHub is used for the global (non multi-account) client.
+func register(accountID string, conn *websocket.Conn, authenticated bool) *Client {
+	hubsMu.Lock()
+	defer hubsMu.Unlock()
+
+	hub, ok := hubs[accountID]
+	if !ok {
+		hub = &Hub{accountID: accountID, clients: make(map[*Client]struct{})}
+		hubs[accountID] = hub
+	}
+	c := newClient(hub, conn, authenticated)
+	hub.add(c)
+	return c
+}
+
+// lookupHub returns the hub of an account, nil while nobody is connected to it
+func lookupHub(accountID string) *Hub {
+	hubsMu.RLock()
+	defer hubsMu.RUnlock()
+	return hubs[accountID]
+}
+
+// Broadcast sends a frame to every connection of the default hub
+func Broadcast(message BroadcastMessage) {
+	if hub := lookupHub(DefaultHub); hub != nil {
+		hub.Broadcast(message)
+	}
+}
+
+// Publish sends an event to the default hub connections subscribed to the topic
+func Publish(topic string, message BroadcastMessage) {
+	PublishAccount(DefaultHub, topic, message)
+}
+
+// PublishAccount sends an event to the connections of an account subscribed to the topic
+func PublishAccount(accountID string, topic string, message BroadcastMessage) {
+	if hub := lookupHub(accountID); hub != nil {
+		hub.Publish(topic, message)
+	}
+}
+
+func newClient(hub *Hub, conn *websocket.Conn, authenticated bool) *Client {
+	return &Client{
+		hub:           hub,
+		conn:          conn,
+		send:          make(chan []byte, sendQueueSize),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		authenticated: authenticated,
+		topics:        make(map[string]bool),
+	}
+}
+
+func (h *Hub) add(c *Client) {
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	logrus.Debugf("websocket connection registered (account: %q)", h.accountID)
+}
+
+// remove drops a connection, the hub is dropped with its last connection so hubs don't pile up for every
+// account ID a client ever connected with
+func (h *Hub) remove(c *Client) {
+	hubsMu.Lock()
+	defer hubsMu.Unlock()
+
+	h.mu.Lock()
+	delete(h.clients, c)
+	empty := len(h.clients) == 0
+	h.mu.Unlock()
+	if empty && hubs[h.accountID] == h {
+		delete(hubs, h.accountID)
+	}
+	logrus.Debugf("websocket connection unregistered (account: %q)", h.accountID)
+}
+
+// snapshot copies the client set so frames are queued without holding the hub lock
+func (h *Hub) snapshot() []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// Len returns the number of connected clients
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Broadcast queues a frame for every connection of the hub
+func (h *Hub) Broadcast(message BroadcastMessage) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		logrus.Errorf("websocket marshal error: %v", err)
+		return
+	}
+
+	for _, c := range h.snapshot() {
+		c.enqueue(payload)
+	}
+}
+
+// Publish queues an event for the authenticated connections subscribed to the topic
+func (h *Hub) Publish(topic string, message BroadcastMessage) {
+	message.Topic = topic
+	payload, err := json.Marshal(message)
+	if err != nil {
+		logrus.Errorf("websocket marshal error: %v", err)
+		return
+	}
+
+	for _, c := range h.snapshot() {
+		if c.isSubscribed(topic) {
+			c.enqueue(payload)
+		}
+	}
+}
+
+// enqueue never blocks; a connection whose queue is full is evicted as a slow consumer
+func (c *Client) enqueue(payload []byte) {
+	select {
+	case <-c.done:
+	case c.send <- payload:
+	default:
+		logrus.Warnf("websocket client too slow, evicting (account: %q)", c.hub.accountID)
+		c.close()
+	}
+}
+
+// reply queues a frame for this connection only
+func (c *Client) reply(message BroadcastMessage) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		logrus.Errorf("websocket marshal error: %v", err)
+		return
+	}
+	c.enqueue(payload)
+}
+
+// close detaches the client from its hub and stops the writer
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		c.hub.remove(c)
+		close(c.done)
+	})
+}
+
+func (c *Client) isSubscribed(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authenticated && c.subscribed(topic)
+}
+
+func (c *Client) isAuthenticated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authenticated
+}
+
+// writePump is the only goroutine writing to the connection
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+		_ = c.conn.Close()
+		close(c.stopped)
+	}()
+
+	for {
+		select {
+		case payload := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				logrus.Debugf("websocket write error: %v", err)
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				logrus.Debugf("websocket ping error: %v", err)
+				return
+			}
+
+		case <-c.done:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHubEvictsSlowConsumer(t *testing.T) {
+	hub := &Hub{accountID: "slow", clients: make(map[*Client]struct{})}
+	c := newClient(hub, nil, true)
+	hub.add(c)
+
+	// Nobody drains the queue, so it fills up and the next frame evicts the client
+	for i := 0; i < sendQueueSize; i++ {
+		hub.Broadcast(BroadcastMessage{Code: "PING"})
+	}
+	if hub.Len() != 1 {
+		t.Fatalf("client evicted before its queue was full")
+	}
+
+	hub.Broadcast(BroadcastMessage{Code: "PING"})
+	if hub.Len() != 0 {
+		t.Fatalf("expected slow client to be evicted, hub still has %d clients", hub.Len())
+	}
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("expected evicted client to be closed")
+	}
+
+	// Publishing to a closed client must not block or panic
+	hub.Broadcast(BroadcastMessage{Code: "PING"})
+}
+
+func TestHubPublishOnlyToSubscribers(t *testing.T) {
+	hub := &Hub{accountID: "topics", clients: make(map[*Client]struct{})}
+
+	subscribed := newClient(hub, nil, true)
+	subscribed.topics[TopicMessage] = true
+	other := newClient(hub, nil, true)
+	other.topics[TopicReceipt] = true
+	anonymous := newClient(hub, nil, false)
+	anonymous.topics[TopicAll] = true
+
+	for _, c := range []*Client{subscribed, other, anonymous} {
+		hub.add(c)
+	}
+
+	hub.Publish(MessageTopic("123@s.whatsapp.net"), BroadcastMessage{Code: "MESSAGE"})
+
+	if len(subscribed.send) != 1 {
+		t.Fatalf("expected subscriber to receive 1 frame, got %d", len(subscribed.send))
+	}
+	if len(other.send) != 0 {
+		t.Errorf("expected client subscribed to another topic to receive nothing, got %d", len(other.send))
+	}
+	if len(anonymous.send) != 0 {
+		t.Errorf("expected unauthenticated client to receive nothing, got %d", len(anonymous.send))
+	}
+
+	var frame BroadcastMessage
+	if err := json.Unmarshal(<-subscribed.send, &frame); err != nil {
+		t.Fatalf("unmarshal frame: %v", err)
+	}
+	if frame.Topic != MessageTopic("123@s.whatsapp.net") {
+		t.Errorf("frame topic = %q, want %q", frame.Topic, MessageTopic("123@s.whatsapp.net"))
+	}
+}
+
+func TestRegisterPerAccount(t *testing.T) {
+	first := register("a", nil, true)
+	second := register("a", nil, true)
+	other := register("b", nil, true)
+	if first.hub != second.hub {
+		t.Error("expected the same hub for the same account")
+	}
+	if first.hub == other.hub {
+		t.Error("expected different hubs for different accounts")
+	}
+
+	first.close()
+	if lookupHub("a") != second.hub {
+		t.Error("expected the hub to stay while it has connections")
+	}
+	second.close()
+	other.close()
+	if lookupHub("a") != nil || lookupHub("b") != nil {
+		t.Error("expected hubs to be dropped with their last connection")
+	}
+
+	// Publishing to an account nobody is connected to must not create a hub
+	PublishAccount("c", TopicQR, BroadcastMessage{Code: "QR"})
+	if lookupHub("c") != nil {
+		t.Error("expected no hub for an account without connections")
+	}
+}
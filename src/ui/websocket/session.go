@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/gofiber/websocket/v2"
+)
+
+// Event topics a websocket session can subscribe to. Message and receipt topics
+// can be scoped to a single chat with the "<topic>:<chat_jid>" form.
+const (
+	TopicAll        = "*"
+	TopicMessage    = "message"
+	TopicReceipt    = "receipt"
+	TopicQR         = "qr"
+	TopicConnection = "connection"
+)
+
+const localAuthorization = "ws_authorization"
+
+var knownTopics = map[string]bool{
+	TopicAll:        true,
+	TopicMessage:    true,
+	TopicReceipt:    true,
+	TopicQR:         true,
+	TopicConnection: true,
+}
+
+// MessageTopic returns the topic for messages of a single chat
+func MessageTopic(chatJID string) string {
+	return TopicMessage + ":" + chatJID
+}
+
+// ReceiptTopic returns the topic for receipts of a single chat
+func ReceiptTopic(chatJID string) string {
+	return TopicReceipt + ":" + chatJID
+}
+
+// subscribed reports whether the session receives events published on topic.
+// The caller must hold c.mu.
+func (c *Client) subscribed(topic string) bool {
+	if c.topics[TopicAll] || c.topics[topic] {
+		return true
+	}
+	if base, _, found := strings.Cut(topic, ":"); found {
+		return c.topics[base]
+	}
+	return false
+}
+
+// validateTopic checks that the topic has a known base and a chat when scoped
+func validateTopic(topic string) error {
+	base, scope, scoped := strings.Cut(topic, ":")
+	if !knownTopics[base] {
+		return fmt.Errorf("unknown topic: %s", topic)
+	}
+	if scoped && (scope == "" || (base != TopicMessage && base != TopicReceipt)) {
+		return fmt.Errorf("topic %s cannot be scoped to a chat", base)
+	}
+	return nil
+}
+
+// isValidCredential checks a basic auth token against the configured credentials.
+// The token may be a full "Basic <base64>" header, the bare base64 value or "user:secret".
+func isValidCredential(token string) bool {
+	if len(config.AppBasicAuthCredential) == 0 {
+		return true
+	}
+
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Basic "))
+	if token == "" {
+		return false
+	}
+
+	candidate := token
+	if decoded, err := base64.StdEncoding.DecodeString(token); err == nil {
+		candidate = string(decoded)
+	}
+
+	// Every credential is compared in constant time, like the basic auth middleware does
+	valid := false
+	for _, credential := range config.AppBasicAuthCredential {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(credential)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+func isAuthenticatedConn(conn *websocket.Conn) bool {
+	header, _ := conn.Locals(localAuthorization).(string)
+	return isValidCredential(header)
+}
+
+func handleAuth(c *Client, msg incomingMessage) {
+	if !isValidCredential(msg.Token) {
+		c.reply(BroadcastMessage{Code: "AUTH_FAILED", ID: msg.ID, Message: "invalid credential"})
+		return
+	}
+
+	c.mu.Lock()
+	c.authenticated = true
+	c.mu.Unlock()
+	c.reply(BroadcastMessage{Code: "AUTH_SUCCESS", ID: msg.ID, Message: "session authenticated"})
+}
+
+func handleSubscription(c *Client, msg incomingMessage) {
+	for _, topic := range msg.Topics {
+		if err := validateTopic(topic); err != nil {
+			c.reply(BroadcastMessage{Code: "ERROR", ID: msg.ID, Message: err.Error()})
+			return
+		}
+	}
+
+	c.mu.Lock()
+	if !c.authenticated {
+		c.mu.Unlock()
+		c.reply(BroadcastMessage{Code: "UNAUTHORIZED", ID: msg.ID, Message: "authenticate before subscribing"})
+		return
+	}
+	for _, topic := range msg.Topics {
+		if msg.Code == "SUBSCRIBE" {
+			c.topics[topic] = true
+		} else {
+			delete(c.topics, topic)
+		}
+	}
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	c.mu.Unlock()
+
+	code := "SUBSCRIBED"
+	if msg.Code == "UNSUBSCRIBE" {
+		code = "UNSUBSCRIBED"
+	}
+	c.reply(BroadcastMessage{
+		Code:    code,
+		ID:      msg.ID,
+		Message: "subscriptions updated",
+		Result:  topics,
+	})
+}
@@ -0,0 +1,82 @@
+package websocket
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestClientSubscribed(t *testing.T) {
+	tests := []struct {
+		name   string
+		topics []string
+		topic  string
+		want   bool
+	}{
+		{name: "ExactTopic", topics: []string{TopicQR}, topic: TopicQR, want: true},
+		{name: "Wildcard", topics: []string{TopicAll}, topic: MessageTopic("123@s.whatsapp.net"), want: true},
+		{name: "BaseCoversChat", topics: []string{TopicMessage}, topic: MessageTopic("123@s.whatsapp.net"), want: true},
+		{name: "OtherChat", topics: []string{MessageTopic("456@s.whatsapp.net")}, topic: MessageTopic("123@s.whatsapp.net"), want: false},
+		{name: "ChatDoesNotCoverBase", topics: []string{MessageTopic("123@s.whatsapp.net")}, topic: TopicMessage, want: false},
+		{name: "OtherTopic", topics: []string{TopicReceipt}, topic: TopicConnection, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{topics: make(map[string]bool)}
+			for _, topic := range tt.topics {
+				c.topics[topic] = true
+			}
+			if got := c.subscribed(tt.topic); got != tt.want {
+				t.Errorf("subscribed(%q) = %v, want %v", tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTopic(t *testing.T) {
+	tests := []struct {
+		topic   string
+		wantErr bool
+	}{
+		{topic: TopicMessage, wantErr: false},
+		{topic: ReceiptTopic("120363@g.us"), wantErr: false},
+		{topic: TopicAll, wantErr: false},
+		{topic: "qr:123", wantErr: true},
+		{topic: "message:", wantErr: true},
+		{topic: "presence", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			if err := validateTopic(tt.topic); (err != nil) != tt.wantErr {
+				t.Errorf("validateTopic(%q) error = %v, wantErr %v", tt.topic, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsValidCredential(t *testing.T) {
+	original := config.AppBasicAuthCredential
+	defer func() { config.AppBasicAuthCredential = original }()
+
+	config.AppBasicAuthCredential = nil
+	if !isValidCredential("") {
+		t.Fatal("expected any token to be accepted when basic auth is disabled")
+	}
+
+	config.AppBasicAuthCredential = []string{"admin:secret"}
+	encoded := base64.StdEncoding.EncodeToString([]byte("admin:secret"))
+
+	for _, token := range []string{"admin:secret", encoded, "Basic " + encoded} {
+		if !isValidCredential(token) {
+			t.Errorf("expected token %q to be accepted", token)
+		}
+	}
+	for _, token := range []string{"", "admin:wrong", "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:wrong"))} {
+		if isValidCredential(token) {
+			t.Errorf("expected token %q to be rejected", token)
+		}
+	}
+}
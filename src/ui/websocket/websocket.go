@@ -3,95 +3,62 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 )
 
-type client struct{}
-
 type BroadcastMessage struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Result  any    `json:"result"`
+	ID      string `json:"id,omitempty"`
+	Topic   string `json:"topic,omitempty"`
 }
 
-var (
-	Clients    = make(map[*websocket.Conn]client)
-	Register   = make(chan *websocket.Conn)
-	Broadcast  = make(chan BroadcastMessage)
-	Unregister = make(chan *websocket.Conn)
-)
-
-func handleRegister(conn *websocket.Conn) {
-	Clients[conn] = client{}
-	logrus.Println("connection registered")
-}
-
-func handleUnregister(conn *websocket.Conn) {
-	delete(Clients, conn)
-	logrus.Println("connection unregistered")
-}
-
-func broadcastMessage(message BroadcastMessage) {
-	marshalMessage, err := json.Marshal(message)
-	if err != nil {
-		logrus.Println("marshal error:", err)
-		return
-	}
-
-	for conn := range Clients {
-		if err := conn.WriteMessage(websocket.TextMessage, marshalMessage); err != nil {
-			logrus.Println("write error:", err)
-			closeConnection(conn)
-		}
-	}
-}
-
-func closeConnection(conn *websocket.Conn) {
-	if err := conn.WriteMessage(websocket.CloseMessage, []byte{}); err != nil {
-		logrus.Println("write close message error:", err)
-	}
-	if err := conn.Close(); err != nil {
-		logrus.Println("close connection error:", err)
-	}
-	delete(Clients, conn)
+// incomingMessage is a frame sent by a websocket client
+type incomingMessage struct {
+	Code    string          `json:"code"`
+	ID      string          `json:"id,omitempty"`
+	Token   string          `json:"token,omitempty"`
+	Topics  []string        `json:"topics,omitempty"`
+	Command string          `json:"command,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
 }
 
-func RunHub() {
-	for {
-		select {
-		case conn := <-Register:
-			handleRegister(conn)
-
-		case conn := <-Unregister:
-			handleUnregister(conn)
+func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase, sendService domainSend.ISendUsecase, messageService domainMessage.IMessageUsecase) {
+	commands := newCommandHandler(sendService, messageService)
 
-		case message := <-Broadcast:
-			logrus.Println("message received:", message)
-			broadcastMessage(message)
-		}
-	}
-}
-
-func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 	app.Use("/ws", func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
+			c.Locals(localAuthorization, c.Get(fiber.HeaderAuthorization))
 			return c.Next()
 		}
 		return c.SendStatus(fiber.StatusUpgradeRequired)
 	})
 
-	app.Get("/ws", websocket.New(func(conn *websocket.Conn) {
+	serve := func(conn *websocket.Conn) {
+		client := register(conn.Params("accountId"), conn, isAuthenticatedConn(conn))
+		hub := client.hub
+		go client.writePump()
+
 		defer func() {
-			Unregister <- conn
-			_ = conn.Close()
+			client.close()
+			// The connection is released once this handler returns, so wait for the writer first
+			<-client.stopped
 		}()
 
-		Register <- conn
+		conn.SetReadLimit(maxFrameSize)
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
 
 		for {
 			messageType, message, err := conn.ReadMessage()
@@ -102,24 +69,41 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 				return
 			}
 
-			if messageType == websocket.TextMessage {
-				var messageData BroadcastMessage
-				if err := json.Unmarshal(message, &messageData); err != nil {
-					logrus.Println("unmarshal error:", err)
-					return
-				}
-
-				if messageData.Code == "FETCH_DEVICES" {
-					devices, _ := service.FetchDevices(context.Background())
-					Broadcast <- BroadcastMessage{
-						Code:    "LIST_DEVICES",
-						Message: "Device found",
-						Result:  devices,
-					}
-				}
-			} else {
+			if messageType != websocket.TextMessage {
 				logrus.Println("unsupported message type:", messageType)
+				continue
+			}
+
+			var messageData incomingMessage
+			if err := json.Unmarshal(message, &messageData); err != nil {
+				logrus.Println("unmarshal error:", err)
+				return
+			}
+
+			switch messageData.Code {
+			case "FETCH_DEVICES":
+				devices, _ := service.FetchDevices(context.Background())
+				hub.Broadcast(BroadcastMessage{
+					Code:    "LIST_DEVICES",
+					Message: "Device found",
+					Result:  devices,
+				})
+			case "AUTH":
+				handleAuth(client, messageData)
+			case "SUBSCRIBE", "UNSUBSCRIBE":
+				handleSubscription(client, messageData)
+			case "COMMAND":
+				commands.handle(client, messageData)
+			default:
+				client.reply(BroadcastMessage{
+					Code:    "ERROR",
+					ID:      messageData.ID,
+					Message: "unsupported code: " + messageData.Code,
+				})
 			}
 		}
-	}))
+	}
+
+	app.Get("/ws", websocket.New(serve))
+	app.Get("/ws/:accountId", websocket.New(serve))
 }
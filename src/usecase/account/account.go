@@ -10,7 +10,10 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/eventbus"
 	"github.com/sirupsen/logrus"
 	"github.com/skip2/go-qrcode"
 	"go.mau.fi/whatsmeow"
@@ -31,11 +34,13 @@ func NewAccountUsecase(
 	manager domainAccount.IAccountManager,
 	chatStorageRepo domainChatStorage.IChatStorageRepository,
 ) domainAccount.IAccountUsecase {
-	return &AccountUsecase{
+	u := &AccountUsecase{
 		repo:            repo,
 		manager:         manager,
 		chatStorageRepo: chatStorageRepo,
 	}
+	u.registerEventHandlers(whatsapp.EventBus())
+	return u
 }
 
 // CreateAccount creates a new account
@@ -180,7 +185,7 @@ func (u *AccountUsecase) LoginAccount(ctx context.Context, accountID string) (do
 
 	// Add event handler
 	client.AddEventHandler(func(evt interface{}) {
-		u.handleEvent(ctx, accountID, evt)
+		whatsapp.DispatchEvent(ctx, accountID, client, evt)
 	})
 
 	// Register client in manager
@@ -226,6 +231,8 @@ func (u *AccountUsecase) LoginAccount(ctx context.Context, accountID string) (do
 				return domainAccount.LoginResponse{}, fmt.Errorf("failed to generate QR code: %w", err)
 			}
 
+			whatsapp.DispatchEvent(ctx, accountID, client, &domainApp.QRCodeEvent{QRLink: qrPath, Code: evt.Code, Duration: int(evt.Timeout.Seconds())})
+
 			return domainAccount.LoginResponse{
 				ImagePath: qrPath,
 				Duration:  time.Duration(evt.Timeout.Seconds()) * time.Second,
@@ -318,7 +325,7 @@ func (u *AccountUsecase) LoginAccountWithCode(ctx context.Context, accountID str
 
 	// Add event handler
 	client.AddEventHandler(func(evt interface{}) {
-		u.handleEvent(ctx, accountID, evt)
+		whatsapp.DispatchEvent(ctx, accountID, client, evt)
 	})
 
 	// Register client in manager
@@ -528,51 +535,81 @@ func (u *AccountUsecase) cleanupAccountFiles(accountID string) {
 	}
 }
 
-func (u *AccountUsecase) handleEvent(ctx context.Context, accountID string, evt interface{}) {
-	acc, err := u.repo.GetAccount(accountID)
-	if err != nil {
-		logrus.Errorf("[%s] Failed to get account: %v", accountID, err)
-		return
-	}
+// registerEventHandlers keeps the account records in sync with the events of the account clients.
+// Storage, websocket, automations and forwarding are shared with the default device through the bus.
+func (u *AccountUsecase) registerEventHandlers(bus *eventbus.Bus) {
+	accountsOnly := func(meta eventbus.Meta) bool { return !meta.IsDefault() }
+
+	eventbus.Register(bus, eventbus.Handler[*events.Connected]{
+		Name:     "account-connected",
+		Priority: whatsapp.PriorityLifecycle,
+		Filter:   func(meta eventbus.Meta, _ *events.Connected) bool { return accountsOnly(meta) },
+		Handle: func(_ context.Context, meta eventbus.Meta, _ *events.Connected) error {
+			acc, err := u.repo.GetAccount(meta.AccountID)
+			if err != nil {
+				return err
+			}
 
-	switch e := evt.(type) {
-	case *events.Connected:
-		logrus.Infof("[%s] Connected to WhatsApp", accountID)
-		acc.Status = domainAccount.StatusConnected
-		acc.LastConnected = time.Now()
-		u.repo.UpdateAccount(acc)
+			logrus.Infof("[%s] Connected to WhatsApp", meta.AccountID)
+			acc.Status = domainAccount.StatusConnected
+			acc.LastConnected = time.Now()
+			return u.repo.UpdateAccount(acc)
+		},
+	})
 
-	case *events.LoggedOut:
-		logrus.Infof("[%s] Logged out from WhatsApp", accountID)
-		acc.Status = domainAccount.StatusDisconnected
-		acc.DeviceID = ""
-		u.repo.UpdateAccount(acc)
+	eventbus.Register(bus, eventbus.Handler[*events.LoggedOut]{
+		Name:     "account-logged-out",
+		Priority: whatsapp.PriorityLifecycle,
+		Filter:   func(meta eventbus.Meta, _ *events.LoggedOut) bool { return accountsOnly(meta) },
+		Handle: func(_ context.Context, meta eventbus.Meta, _ *events.LoggedOut) error {
+			acc, err := u.repo.GetAccount(meta.AccountID)
+			if err != nil {
+				return err
+			}
 
-		// Cleanup
-		u.cleanupAccountDatabase(accountID)
-		u.manager.RemoveClient(accountID)
+			logrus.Infof("[%s] Logged out from WhatsApp", meta.AccountID)
+			acc.Status = domainAccount.StatusDisconnected
+			acc.DeviceID = ""
+			u.repo.UpdateAccount(acc)
 
-	case *events.PairSuccess:
-		logrus.Infof("[%s] Successfully paired with %s", accountID, e.ID.String())
-		acc.Status = domainAccount.StatusLoggedIn
-		acc.DeviceID = e.ID.String()
-		acc.PhoneNumber = strings.Split(e.ID.String(), "@")[0]
-		acc.LastConnected = time.Now()
-		u.repo.UpdateAccount(acc)
+			// Cleanup
+			u.cleanupAccountDatabase(meta.AccountID)
+			u.manager.RemoveClient(meta.AccountID)
+			return nil
+		},
+	})
 
-	case *events.Message:
-		// Store message in chat storage
-		if u.chatStorageRepo != nil {
-			if err := u.chatStorageRepo.CreateMessage(ctx, e); err != nil {
-				logrus.Errorf("[%s] Failed to store message: %v", accountID, err)
+	eventbus.Register(bus, eventbus.Handler[*events.PairSuccess]{
+		Name:     "account-paired",
+		Priority: whatsapp.PriorityLifecycle,
+		Filter:   func(meta eventbus.Meta, _ *events.PairSuccess) bool { return accountsOnly(meta) },
+		Handle: func(_ context.Context, meta eventbus.Meta, e *events.PairSuccess) error {
+			acc, err := u.repo.GetAccount(meta.AccountID)
+			if err != nil {
+				return err
 			}
-		}
 
-		// Forward to webhook if configured
-		if webhook, err := u.repo.GetWebhook(accountID); err == nil && webhook.URL != "" {
-			go u.forwardToWebhook(accountID, webhook, e)
-		}
-	}
+			logrus.Infof("[%s] Successfully paired with %s", meta.AccountID, e.ID.String())
+			acc.Status = domainAccount.StatusLoggedIn
+			acc.DeviceID = e.ID.String()
+			acc.PhoneNumber = strings.Split(e.ID.String(), "@")[0]
+			acc.LastConnected = time.Now()
+			return u.repo.UpdateAccount(acc)
+		},
+	})
+
+	eventbus.Register(bus, eventbus.Handler[*events.Message]{
+		Name:     "account-webhook",
+		Priority: whatsapp.PriorityForward,
+		Filter:   func(meta eventbus.Meta, _ *events.Message) bool { return accountsOnly(meta) },
+		Handle: func(_ context.Context, meta eventbus.Meta, e *events.Message) error {
+			// Forward to webhook if configured
+			if webhook, err := u.repo.GetWebhook(meta.AccountID); err == nil && webhook.URL != "" {
+				go u.forwardToWebhook(meta.AccountID, webhook, e)
+			}
+			return nil
+		},
+	})
 }
 
 func (u *AccountUsecase) forwardToWebhook(accountID string, webhook *domainAccount.WebhookInfo, message interface{}) {
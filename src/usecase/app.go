@@ -99,6 +99,7 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 							}
 						}
 					}()
+					whatsapp.DispatchEvent(context.Background(), "", client, &domainApp.QRCodeEvent{QRLink: qrPath, Code: evt.Code, Duration: int(response.Duration)})
 					chImage <- qrPath
 				} else {
 					logrus.Error("error when get qrCode", evt.Event, evt.Error)
@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceAutoReply struct {
+	repo            domainAutoReply.IAutoReplyRepository
+	sendService     domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+
+	rules atomic.Pointer[domainAutoReply.RuleSet]
+	// calendars holds the enabled business hours by account
+	calendars atomic.Pointer[map[string]*domainAutoReply.Calendar]
+
+	// cooldowns holds until when a rule stays silent for a contact
+	cooldownMu sync.Mutex
+	cooldowns  map[string]time.Time
+}
+
+// NewAutoReplyService loads the stored rules and business hours and answers incoming messages with them
+func NewAutoReplyService(repo domainAutoReply.IAutoReplyRepository, sendService domainSend.ISendUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainAutoReply.IAutoReplyUsecase {
+	service := &serviceAutoReply{
+		repo:            repo,
+		sendService:     sendService,
+		chatStorageRepo: chatStorageRepo,
+		cooldowns:       make(map[string]time.Time),
+	}
+	service.calendars.Store(&map[string]*domainAutoReply.Calendar{})
+	if err := service.reloadRules(); err != nil {
+		logrus.Errorf("Failed to load auto-reply rules: %v", err)
+	}
+	if err := service.reloadSchedules(); err != nil {
+		logrus.Errorf("Failed to load business hours: %v", err)
+	}
+	whatsapp.SetAutoReplyResponder(service)
+	return service
+}
+
+func (service *serviceAutoReply) ListRules(_ context.Context) ([]domainAutoReply.Rule, error) {
+	return service.repo.ListRules()
+}
+
+func (service *serviceAutoReply) GetRule(_ context.Context, ruleID string) (rule domainAutoReply.Rule, err error) {
+	stored, err := service.repo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceAutoReply) CreateRule(ctx context.Context, request domainAutoReply.RuleRequest) (rule domainAutoReply.Rule, err error) {
+	if err = validations.ValidateAutoReplyRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	now := time.Now()
+	rule = autoReplyRuleFromRequest(request)
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err = service.repo.CreateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service *serviceAutoReply) UpdateRule(ctx context.Context, ruleID string, request domainAutoReply.RuleRequest) (rule domainAutoReply.Rule, err error) {
+	if err = validations.ValidateAutoReplyRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	stored, err := service.repo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+
+	rule = autoReplyRuleFromRequest(request)
+	rule.ID = stored.ID
+	rule.CreatedAt = stored.CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if err = service.repo.UpdateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service *serviceAutoReply) DeleteRule(_ context.Context, ruleID string) error {
+	if err := service.repo.DeleteRule(ruleID); err != nil {
+		return err
+	}
+	return service.reloadRules()
+}
+
+func (service *serviceAutoReply) ListMatches(ctx context.Context, filter domainAutoReply.MatchFilter) ([]domainAutoReply.Match, error) {
+	if err := validations.ValidateAutoReplyMatchFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListMatches(filter)
+}
+
+func (service *serviceAutoReply) Stats(_ context.Context) ([]domainAutoReply.RuleStats, error) {
+	return service.repo.Stats()
+}
+
+func (service *serviceAutoReply) ListSchedules(_ context.Context) ([]domainAutoReply.Schedule, error) {
+	return service.repo.ListSchedules()
+}
+
+func (service *serviceAutoReply) GetSchedule(_ context.Context, accountID string) (schedule domainAutoReply.Schedule, err error) {
+	stored, err := service.repo.GetSchedule(accountID)
+	if err != nil {
+		return schedule, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceAutoReply) SaveSchedule(ctx context.Context, accountID string, request domainAutoReply.ScheduleRequest) (schedule domainAutoReply.Schedule, err error) {
+	if err = validations.ValidateAutoReplySchedule(ctx, request); err != nil {
+		return schedule, err
+	}
+
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+	schedule = domainAutoReply.Schedule{
+		AccountID:       accountID,
+		Enabled:         enabled,
+		TimeZone:        request.TimeZone,
+		Hours:           request.Hours,
+		Holidays:        request.Holidays,
+		AwayMessage:     request.AwayMessage,
+		GreetingMessage: request.GreetingMessage,
+		UpdatedAt:       time.Now(),
+	}
+
+	if err = service.repo.SaveSchedule(&schedule); err != nil {
+		return schedule, err
+	}
+	return schedule, service.reloadSchedules()
+}
+
+func (service *serviceAutoReply) DeleteSchedule(_ context.Context, accountID string) error {
+	if err := service.repo.DeleteSchedule(accountID); err != nil {
+		return err
+	}
+	return service.reloadSchedules()
+}
+
+// Respond greets new contacts, sends the away message outside business hours and answers texts with the
+// first matching rule
+func (service *serviceAutoReply) Respond(ctx context.Context, message domainAutoReply.Incoming) bool {
+	if message.Timestamp.IsZero() {
+		message.Timestamp = time.Now()
+	}
+
+	replied := false
+	if !message.IsGroup {
+		replied = service.respondToContact(ctx, message)
+	}
+	if message.Text == "" {
+		return replied
+	}
+
+	rule := service.rules.Load().Match(message)
+	if rule == nil {
+		return replied
+	}
+	service.answer(ctx, *rule, message)
+	return true
+}
+
+// respondToContact sends the greeting the first time a contact writes and the away message once per contact
+// and closed period
+func (service *serviceAutoReply) respondToContact(ctx context.Context, message domainAutoReply.Incoming) bool {
+	// Contacts are recorded even without a schedule, so enabling a greeting later doesn't greet known contacts
+	isNew, err := service.repo.RecordContact(message.AccountID, message.SenderJID, message.Timestamp)
+	if err != nil {
+		logrus.Warnf("Failed recording auto-reply contact: %v", err)
+		return false
+	}
+
+	calendar := (*service.calendars.Load())[message.AccountID]
+	if calendar == nil {
+		return false
+	}
+	schedule := calendar.Schedule
+
+	replied := false
+	if isNew && schedule.GreetingMessage != "" && !service.hasChatHistory(message) {
+		service.answer(ctx, domainAutoReply.Rule{ID: domainAutoReply.RuleGreeting, Name: domainAutoReply.RuleGreeting, Reply: schedule.GreetingMessage}, message)
+		replied = true
+	}
+
+	if schedule.AwayMessage == "" {
+		return replied
+	}
+	open, since := calendar.Window(message.Timestamp)
+	if open {
+		return replied
+	}
+	claimed, err := service.repo.ClaimAwayWindow(message.AccountID, message.SenderJID, since)
+	if err != nil {
+		logrus.Warnf("Failed claiming away window: %v", err)
+		return replied
+	}
+	if claimed {
+		service.answer(ctx, domainAutoReply.Rule{ID: domainAutoReply.RuleAway, Name: domainAutoReply.RuleAway, Reply: schedule.AwayMessage}, message)
+		replied = true
+	}
+	return replied
+}
+
+// hasChatHistory reports whether the chat has messages from before the incoming one, contacts that wrote before
+// auto-reply recorded them are not greeted
+func (service *serviceAutoReply) hasChatHistory(message domainAutoReply.Incoming) bool {
+	if service.chatStorageRepo == nil {
+		return false
+	}
+
+	before := message.Timestamp.Add(-time.Second)
+	messages, err := service.chatStorageRepo.GetMessages(&domainChatStorage.MessageFilter{
+		ChatJID: message.ChatJID,
+		Limit:   1,
+		EndTime: &before,
+	})
+	if err != nil {
+		logrus.Warnf("Failed checking chat history of %s: %v", message.ChatJID, err)
+		return false
+	}
+	return len(messages) > 0
+}
+
+// answer sends the reply of the rule unless it is cooling down for the contact, and records the match
+func (service *serviceAutoReply) answer(ctx context.Context, rule domainAutoReply.Rule, message domainAutoReply.Incoming) {
+	match := domainAutoReply.Match{
+		ID:        uuid.NewString(),
+		RuleID:    rule.ID,
+		AccountID: message.AccountID,
+		ChatJID:   message.ChatJID,
+		SenderJID: message.SenderJID,
+		MessageID: message.MessageID,
+		Status:    domainAutoReply.MatchStatusSent,
+		CreatedAt: time.Now(),
+	}
+
+	if !service.startCooldown(rule, message.SenderJID, match.CreatedAt) {
+		match.Status = domainAutoReply.MatchStatusCooldown
+	} else if replyID, err := service.reply(ctx, rule, message); err != nil {
+		match.Status = domainAutoReply.MatchStatusFailed
+		match.Error = err.Error()
+		logrus.Warnf("Auto-reply rule %s failed to answer %s: %v", rule.Name, message.ChatJID, err)
+	} else {
+		match.ReplyMessageID = replyID
+	}
+
+	if err := service.repo.CreateMatch(&match); err != nil {
+		logrus.Warnf("Failed recording auto-reply match: %v", err)
+	}
+}
+
+// startCooldown reports whether the rule may reply to the contact and, if so, starts its cooldown
+func (service *serviceAutoReply) startCooldown(rule domainAutoReply.Rule, senderJID string, now time.Time) bool {
+	if rule.CooldownSeconds <= 0 {
+		return true
+	}
+
+	key := rule.ID + "|" + senderJID
+	service.cooldownMu.Lock()
+	defer service.cooldownMu.Unlock()
+
+	if until, ok := service.cooldowns[key]; ok && now.Before(until) {
+		return false
+	}
+	service.cooldowns[key] = now.Add(rule.Cooldown())
+
+	// Forget contacts whose cooldown ended so the map doesn't grow forever
+	for other, until := range service.cooldowns {
+		if !now.Before(until) {
+			delete(service.cooldowns, other)
+		}
+	}
+	return true
+}
+
+// reply sends the rendered reply and media of the rule and returns the ID of the last message sent
+func (service *serviceAutoReply) reply(ctx context.Context, rule domainAutoReply.Rule, message domainAutoReply.Incoming) (messageID string, err error) {
+	defer recoverSend(&err)
+
+	base := domainSend.BaseRequest{Phone: message.ChatJID}
+	if message.AccountID != domainEventSink.DefaultAccountID {
+		base.AccountID = message.AccountID
+	}
+	text := domainAutoReply.RenderReply(rule.Reply, message)
+
+	var response domainSend.GenericResponse
+	switch rule.MediaType {
+	case domainAutoReply.MediaImage:
+		response, err = service.sendService.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, Caption: text, ImageURL: &rule.MediaURL})
+	case domainAutoReply.MediaVideo:
+		response, err = service.sendService.SendVideo(ctx, domainSend.VideoRequest{BaseRequest: base, Caption: text, VideoURL: &rule.MediaURL})
+	case domainAutoReply.MediaAudio:
+		// Audio has no caption, the text goes first
+		if text != "" {
+			if _, err = service.sendService.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: text}); err != nil {
+				return "", err
+			}
+		}
+		response, err = service.sendService.SendAudio(ctx, domainSend.AudioRequest{BaseRequest: base, AudioURL: &rule.MediaURL})
+	default:
+		response, err = service.sendService.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: text})
+	}
+	return response.MessageID, err
+}
+
+// reloadRules compiles the stored rules and swaps them in for incoming messages
+func (service *serviceAutoReply) reloadRules() error {
+	rules, err := service.repo.ListRules()
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := domainAutoReply.NewRuleSet(rules)
+	if err != nil {
+		return pkgError.InternalServerError(err.Error())
+	}
+	service.rules.Store(ruleSet)
+	return nil
+}
+
+// reloadSchedules compiles the enabled business hours and swaps them in for incoming messages
+func (service *serviceAutoReply) reloadSchedules() error {
+	schedules, err := service.repo.ListSchedules()
+	if err != nil {
+		return err
+	}
+
+	calendars := make(map[string]*domainAutoReply.Calendar)
+	for _, schedule := range schedules {
+		if !schedule.Enabled {
+			continue
+		}
+		calendar, err := domainAutoReply.NewCalendar(schedule)
+		if err != nil {
+			return pkgError.InternalServerError(err.Error())
+		}
+		calendars[schedule.AccountID] = calendar
+	}
+	service.calendars.Store(&calendars)
+	return nil
+}
+
+func autoReplyRuleFromRequest(request domainAutoReply.RuleRequest) domainAutoReply.Rule {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	return domainAutoReply.Rule{
+		Name:            request.Name,
+		Priority:        request.Priority,
+		Enabled:         enabled,
+		AccountID:       request.AccountID,
+		ChatJID:         request.ChatJID,
+		TriggerType:     request.TriggerType,
+		Keywords:        request.Keywords,
+		Pattern:         request.Pattern,
+		CaseSensitive:   request.CaseSensitive,
+		Reply:           request.Reply,
+		MediaType:       request.MediaType,
+		MediaURL:        request.MediaURL,
+		CooldownSeconds: request.CooldownSeconds,
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const (
+	// offlineRetryMin and offlineRetryMax bound the wait of a background send for its client to log in
+	offlineRetryMin = 5 * time.Second
+	offlineRetryMax = time.Minute
+)
+
+// recoverSend turns the panic of a send made outside an HTTP request into its error. The usecases panic when the
+// client is not connected or logged in, the panic value is kept as the error so isClientOffline can tell.
+func recoverSend(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if recovered, ok := r.(error); ok {
+		*err = recovered
+		return
+	}
+	*err = fmt.Errorf("%v", r)
+}
+
+// isClientOffline reports whether a send failed because its client is not connected or logged in, it can be tried
+// again once the client is
+func isClientOffline(err error) bool {
+	return errors.Is(err, pkgError.ErrNotConnected) || errors.Is(err, pkgError.ErrNotLoggedIn)
+}
+
+// nextOfflineRetry doubles the wait for a client to log in, starting at offlineRetryMin
+func nextOfflineRetry(previous time.Duration) time.Duration {
+	if previous < offlineRetryMin {
+		return offlineRetryMin
+	}
+	return min(previous*2, offlineRetryMax)
+}
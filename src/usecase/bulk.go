@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// bulkResumePage is how many running jobs are loaded at a time when resuming them on start
+const bulkResumePage = 100
+
+type serviceBulk struct {
+	repo        domainBulk.IBulkRepository
+	sendService domainSend.ISendUsecase
+
+	// runs holds the jobs currently sending, pausing or cancelling a job stops its run first
+	runsMu sync.Mutex
+	runs   map[string]*bulkRun
+	// transitionMu serializes pausing, resuming and cancelling
+	transitionMu sync.Mutex
+}
+
+type bulkRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewBulkService(repo domainBulk.IBulkRepository, sendService domainSend.ISendUsecase) domainBulk.IBulkUsecase {
+	return &serviceBulk{
+		repo:        repo,
+		sendService: sendService,
+		runs:        make(map[string]*bulkRun),
+	}
+}
+
+func (service *serviceBulk) ResumeJobs(_ context.Context) {
+	for offset := 0; ; offset += bulkResumePage {
+		jobs, err := service.repo.ListJobs(domainBulk.JobFilter{Status: domainBulk.JobRunning, Limit: bulkResumePage, Offset: offset})
+		if err != nil {
+			logrus.Errorf("Failed to load running bulk send jobs: %v", err)
+			return
+		}
+		for _, job := range jobs {
+			service.runsMu.Lock()
+			_, running := service.runs[job.ID]
+			service.runsMu.Unlock()
+			if running {
+				continue
+			}
+			logrus.Infof("Resuming bulk send job %s, %d recipients left", job.ID, job.Pending())
+			service.start(job)
+		}
+		if len(jobs) < bulkResumePage {
+			return
+		}
+	}
+}
+
+func (service *serviceBulk) CreateJob(ctx context.Context, request domainBulk.JobRequest) (job domainBulk.Job, err error) {
+	if err = validations.ValidateBulkJob(ctx, request); err != nil {
+		return job, err
+	}
+
+	// Recipients are stored as JIDs, a number listed twice only gets the message once
+	phones := make([]string, 0, len(request.Recipients))
+	seen := make(map[string]bool, len(request.Recipients))
+	for _, phone := range request.Recipients {
+		utils.SanitizePhone(&phone)
+		if !seen[phone] {
+			seen[phone] = true
+			phones = append(phones, phone)
+		}
+	}
+
+	// The payload is validated as the message to the first recipient, it is stored without phone and account
+	payload, err := recipientPayload(request.Payload, request.AccountID, phones[0])
+	if err != nil {
+		return job, err
+	}
+	if _, err = payloadSenders[request.Type](ctx, service.sendService, payload, true); err != nil {
+		return job, err
+	}
+	if request.Payload, err = storedPayload(request.Payload); err != nil {
+		return job, err
+	}
+
+	now := time.Now()
+	job = domainBulk.Job{
+		ID:         uuid.NewString(),
+		AccountID:  request.AccountID,
+		Type:       request.Type,
+		Payload:    request.Payload,
+		Status:     domainBulk.JobRunning,
+		IntervalMs: int(config.WhatsappBulkInterval.Milliseconds()),
+		JitterMs:   int(config.WhatsappBulkJitter.Milliseconds()),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if request.IntervalMs != nil {
+		job.IntervalMs = *request.IntervalMs
+	}
+	if request.JitterMs != nil {
+		job.JitterMs = *request.JitterMs
+	}
+
+	if err = service.repo.CreateJob(&job, phones); err != nil {
+		return job, err
+	}
+	service.publishStatus(job)
+	service.start(job)
+	return job, nil
+}
+
+func (service *serviceBulk) ListJobs(ctx context.Context, filter domainBulk.JobFilter) ([]domainBulk.Job, error) {
+	if err := validations.ValidateBulkJobFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListJobs(filter)
+}
+
+func (service *serviceBulk) GetJob(_ context.Context, jobID string) (job domainBulk.Job, err error) {
+	stored, err := service.repo.GetJob(jobID)
+	if err != nil {
+		return job, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceBulk) ListRecipients(ctx context.Context, jobID string, filter domainBulk.RecipientFilter) ([]domainBulk.Recipient, error) {
+	if err := validations.ValidateBulkRecipientFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	if _, err := service.repo.GetJob(jobID); err != nil {
+		return nil, err
+	}
+	return service.repo.ListRecipients(jobID, filter)
+}
+
+func (service *serviceBulk) PauseJob(_ context.Context, jobID string) (job domainBulk.Job, err error) {
+	return service.transition(jobID, domainBulk.JobPaused, domainBulk.JobRunning)
+}
+
+func (service *serviceBulk) ResumeJob(_ context.Context, jobID string) (job domainBulk.Job, err error) {
+	return service.transition(jobID, domainBulk.JobRunning, domainBulk.JobPaused)
+}
+
+func (service *serviceBulk) CancelJob(_ context.Context, jobID string) (job domainBulk.Job, err error) {
+	return service.transition(jobID, domainBulk.JobCancelled, domainBulk.JobRunning, domainBulk.JobPaused)
+}
+
+// transition stops the run of the job and moves it to the status, if it is in one of the statuses it can leave.
+// Moving it back to running starts a new run.
+func (service *serviceBulk) transition(jobID, status string, from ...string) (job domainBulk.Job, err error) {
+	service.transitionMu.Lock()
+	defer service.transitionMu.Unlock()
+
+	service.runsMu.Lock()
+	run := service.runs[jobID]
+	service.runsMu.Unlock()
+	if run != nil {
+		// Waits for a message being sent to finish
+		run.cancel()
+		<-run.done
+	}
+
+	stored, err := service.repo.GetJob(jobID)
+	if err != nil {
+		return job, err
+	}
+	if !slices.Contains(from, stored.Status) {
+		// The run was stopped for nothing, let it carry on
+		if run != nil && stored.Status == domainBulk.JobRunning {
+			service.start(*stored)
+		}
+		return job, pkgError.ValidationError(fmt.Sprintf("bulk send job %s is %s, it cannot be %s", jobID, stored.Status, status))
+	}
+
+	var finishedAt *time.Time
+	if status == domainBulk.JobCancelled {
+		if err = service.repo.CancelPending(jobID); err != nil {
+			return job, err
+		}
+		now := time.Now()
+		finishedAt = &now
+	}
+	if err = service.repo.UpdateJobStatus(jobID, status, finishedAt); err != nil {
+		return job, err
+	}
+
+	job, err = service.GetJob(context.Background(), jobID)
+	if err != nil {
+		return job, err
+	}
+	service.publishStatus(job)
+	if status == domainBulk.JobRunning {
+		service.start(job)
+	}
+	return job, nil
+}
+
+// start sends the pending recipients of the job in the background
+func (service *serviceBulk) start(job domainBulk.Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &bulkRun{cancel: cancel, done: make(chan struct{})}
+
+	service.runsMu.Lock()
+	service.runs[job.ID] = run
+	service.runsMu.Unlock()
+
+	go func() {
+		defer func() {
+			service.runsMu.Lock()
+			if service.runs[job.ID] == run {
+				delete(service.runs, job.ID)
+			}
+			service.runsMu.Unlock()
+			cancel()
+			close(run.done)
+		}()
+		service.run(ctx, job)
+	}()
+}
+
+// run sends the message to one pending recipient after another until none is left or the job is stopped. While
+// the client is offline the recipient stays pending and is tried again once it may have logged in.
+func (service *serviceBulk) run(ctx context.Context, job domainBulk.Job) {
+	var wait, offlineRetry time.Duration
+	for {
+		recipient, err := service.repo.NextRecipient(job.ID)
+		if err != nil {
+			if _, ok := err.(pkgError.NotFoundError); !ok {
+				logrus.Errorf("Bulk send job %s stopped: %v", job.ID, err)
+				return
+			}
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		// The message is sent on its own context so pausing the job doesn't abort it halfway
+		response, err := service.send(context.Background(), job, recipient.Phone)
+		if isClientOffline(err) {
+			offlineRetry = nextOfflineRetry(offlineRetry)
+			wait = offlineRetry
+			logrus.Warnf("Bulk send job %s waiting %s for the client to log in: %v", job.ID, wait, err)
+			continue
+		}
+		offlineRetry, wait = 0, job.Delay()
+
+		now := time.Now()
+		recipient.SentAt = &now
+		if err != nil {
+			recipient.Status = domainBulk.RecipientFailed
+			recipient.Error = err.Error()
+		} else {
+			recipient.Status = domainBulk.RecipientSent
+			recipient.MessageID = response.MessageID
+		}
+		if err := service.repo.RecordResult(recipient); err != nil {
+			logrus.Errorf("Bulk send job %s stopped, failed recording %s: %v", job.ID, recipient.Phone, err)
+			return
+		}
+		service.publishProgress(job.ID, *recipient)
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	now := time.Now()
+	if err := service.repo.UpdateJobStatus(job.ID, domainBulk.JobCompleted, &now); err != nil {
+		logrus.Errorf("Failed completing bulk send job %s: %v", job.ID, err)
+		return
+	}
+	if completed, err := service.repo.GetJob(job.ID); err == nil {
+		service.publishStatus(*completed)
+	}
+}
+
+// send sends the payload of the job to the recipient
+func (service *serviceBulk) send(ctx context.Context, job domainBulk.Job, phone string) (response domainSend.GenericResponse, err error) {
+	defer recoverSend(&err)
+
+	payload, err := recipientPayload(job.Payload, job.AccountID, phone)
+	if err != nil {
+		return response, err
+	}
+	sender, ok := payloadSenders[job.Type]
+	if !ok {
+		return response, fmt.Errorf("unknown message type %q", job.Type)
+	}
+	return sender(ctx, service.sendService, payload, false)
+}
+
+func (service *serviceBulk) publishStatus(job domainBulk.Job) {
+	payload := &domainWebhook.BulkStatusPayload{
+		Meta:       domainWebhook.NewMeta(domainWebhook.EventBulkStatus),
+		JobID:      job.ID,
+		Status:     job.Status,
+		BulkCounts: bulkCounts(job),
+		Timestamp:  time.Now().Format(time.RFC3339),
+	}
+	if err := whatsapp.ForwardPayload(context.Background(), job.AccountID, payload); err != nil {
+		logrus.Warnf("Failed publishing status of bulk send job %s: %v", job.ID, err)
+	}
+}
+
+func (service *serviceBulk) publishProgress(jobID string, recipient domainBulk.Recipient) {
+	job, err := service.repo.GetJob(jobID)
+	if err != nil {
+		logrus.Warnf("Failed loading bulk send job %s: %v", jobID, err)
+		return
+	}
+
+	payload := &domainWebhook.BulkProgressPayload{
+		Meta:       domainWebhook.NewMeta(domainWebhook.EventBulkProgress),
+		JobID:      jobID,
+		Phone:      recipient.Phone,
+		Status:     recipient.Status,
+		MessageID:  recipient.MessageID,
+		Error:      recipient.Error,
+		BulkCounts: bulkCounts(*job),
+		Timestamp:  time.Now().Format(time.RFC3339),
+	}
+	if err := whatsapp.ForwardPayload(context.Background(), job.AccountID, payload); err != nil {
+		logrus.Warnf("Failed publishing progress of bulk send job %s: %v", jobID, err)
+	}
+}
+
+func bulkCounts(job domainBulk.Job) domainWebhook.BulkCounts {
+	return domainWebhook.BulkCounts{
+		Total:     job.Total,
+		Sent:      job.Sent,
+		Failed:    job.Failed,
+		Cancelled: job.Cancelled,
+		Pending:   job.Pending(),
+	}
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow"
+)
+
+type memoryBulkRepository struct {
+	domainBulk.IBulkRepository
+	mu         sync.Mutex
+	jobs       map[string]*domainBulk.Job
+	recipients []*domainBulk.Recipient
+	attempts   chan string
+}
+
+func (r *memoryBulkRepository) GetJob(jobID string) (*domainBulk.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return nil, pkgError.NotFoundError("not found")
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memoryBulkRepository) ListJobs(filter domainBulk.JobFilter) ([]domainBulk.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []domainBulk.Job
+	for _, job := range r.jobs {
+		if job.Status == filter.Status {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memoryBulkRepository) UpdateJobStatus(jobID, status string, _ *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[jobID].Status = status
+	return nil
+}
+
+func (r *memoryBulkRepository) NextRecipient(jobID string) (*domainBulk.Recipient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, recipient := range r.recipients {
+		if recipient.JobID == jobID && recipient.Status == domainBulk.RecipientPending {
+			copied := *recipient
+			r.attempts <- copied.Phone
+			return &copied, nil
+		}
+	}
+	return nil, pkgError.NotFoundError("no pending recipient")
+}
+
+func (r *memoryBulkRepository) RecordResult(result *domainBulk.Recipient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, recipient := range r.recipients {
+		if recipient.JobID == result.JobID && recipient.Position == result.Position {
+			*recipient = *result
+		}
+	}
+	return nil
+}
+
+func (r *memoryBulkRepository) recipient(position int) domainBulk.Recipient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.recipients[position]
+}
+
+// disconnectedAccounts has no client for any account, as right after a restart
+type disconnectedAccounts struct {
+	domainAccount.IAccountManager
+}
+
+func (disconnectedAccounts) GetClient(string) *whatsmeow.Client { return nil }
+
+type memoryAccountRepository struct {
+	domainAccount.IAccountRepository
+	accounts map[string]bool
+}
+
+func (r memoryAccountRepository) GetAccount(accountID string) (*domainAccount.Account, error) {
+	if !r.accounts[accountID] {
+		return nil, pkgError.NotFoundError("account not found")
+	}
+	return &domainAccount.Account{ID: accountID}, nil
+}
+
+func TestGetClientOfDisconnectedAccount(t *testing.T) {
+	service := serviceSend{accountManager: disconnectedAccounts{}, accountRepo: memoryAccountRepository{accounts: map[string]bool{"shop": true}}}
+
+	if _, err := service.getClient("shop"); !isClientOffline(err) {
+		t.Errorf("a stored account without a client should be offline, got %v", err)
+	}
+	if _, err := service.getClient("unknown"); err == nil || isClientOffline(err) {
+		t.Errorf("an unknown account should not be retried, got %v", err)
+	}
+}
+
+func TestResumedBulkJobWaitsForAccountClient(t *testing.T) {
+	payload := json.RawMessage(`{"message": "Restock alert"}`)
+	repo := &memoryBulkRepository{
+		jobs: map[string]*domainBulk.Job{
+			"shop-job":    {ID: "shop-job", AccountID: "shop", Type: domainSend.TypeText, Payload: payload, Status: domainBulk.JobRunning, Total: 1},
+			"unknown-job": {ID: "unknown-job", AccountID: "unknown", Type: domainSend.TypeText, Payload: payload, Status: domainBulk.JobRunning, Total: 1},
+		},
+		recipients: []*domainBulk.Recipient{
+			{JobID: "shop-job", Position: 0, Phone: "6281234567890@s.whatsapp.net", Status: domainBulk.RecipientPending},
+			{JobID: "unknown-job", Position: 1, Phone: "6289876543210@s.whatsapp.net", Status: domainBulk.RecipientPending},
+		},
+		attempts: make(chan string, 8),
+	}
+	sendService := &serviceSend{accountManager: disconnectedAccounts{}, accountRepo: memoryAccountRepository{accounts: map[string]bool{"shop": true}}}
+	service := NewBulkService(repo, sendService).(*serviceBulk)
+
+	// The gateway restarted before the account clients reconnected
+	service.ResumeJobs(t.Context())
+	for range 2 {
+		select {
+		case <-repo.attempts:
+		case <-time.After(time.Second):
+			t.Fatal("resumed jobs did not send")
+		}
+	}
+
+	// The unknown account fails for good, the stored one keeps its recipient pending
+	deadline := time.Now().Add(time.Second)
+	for repo.recipient(1).Status == domainBulk.RecipientPending && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := repo.recipient(1); got.Status != domainBulk.RecipientFailed {
+		t.Errorf("recipient of an unknown account should fail, got %+v", got)
+	}
+	if got := repo.recipient(0); got.Status != domainBulk.RecipientPending || got.Error != "" {
+		t.Errorf("recipient of a disconnected account should stay pending, got %+v", got)
+	}
+
+	service.runsMu.Lock()
+	run := service.runs["shop-job"]
+	service.runsMu.Unlock()
+	if run == nil {
+		t.Fatal("job of the disconnected account should still be running")
+	}
+	run.cancel()
+	<-run.done
+}
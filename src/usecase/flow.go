@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// flowSweepInterval is how often sessions that timed out are ended
+var flowSweepInterval = time.Minute
+
+type serviceFlow struct {
+	repo        domainFlow.IFlowRepository
+	sendService domainSend.ISendUsecase
+
+	flows atomic.Pointer[[]domainFlow.Flow]
+
+	// locks serialize the messages of a contact, contacts share them by hash
+	locks [64]sync.Mutex
+}
+
+// NewFlowService loads the stored flows, advances the sessions of contacts writing in and ends the ones that time out
+func NewFlowService(repo domainFlow.IFlowRepository, sendService domainSend.ISendUsecase) domainFlow.IFlowUsecase {
+	service := &serviceFlow{
+		repo:        repo,
+		sendService: sendService,
+	}
+	if err := service.reloadFlows(); err != nil {
+		logrus.Errorf("Failed to load flows: %v", err)
+	}
+	whatsapp.SetFlowResponder(service)
+	go service.expireSessions()
+	return service
+}
+
+func (service *serviceFlow) ListFlows(_ context.Context) ([]domainFlow.Flow, error) {
+	return service.repo.ListFlows()
+}
+
+func (service *serviceFlow) GetFlow(_ context.Context, flowID string) (flow domainFlow.Flow, err error) {
+	stored, err := service.repo.GetFlow(flowID)
+	if err != nil {
+		return flow, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceFlow) CreateFlow(ctx context.Context, definition []byte) (flow domainFlow.Flow, err error) {
+	flow, err = domainFlow.ParseFlow(definition)
+	if err != nil {
+		return flow, pkgError.ValidationError(err.Error())
+	}
+	if err = validations.ValidateFlow(ctx, flow); err != nil {
+		return flow, err
+	}
+	if _, err = service.repo.GetFlow(flow.ID); err == nil {
+		return flow, pkgError.ValidationError(fmt.Sprintf("flow %s already exists", flow.ID))
+	}
+
+	now := time.Now()
+	flow.CreatedAt = now
+	flow.UpdatedAt = now
+
+	if err = service.repo.CreateFlow(&flow); err != nil {
+		return flow, err
+	}
+	return flow, service.reloadFlows()
+}
+
+func (service *serviceFlow) UpdateFlow(ctx context.Context, flowID string, definition []byte) (flow domainFlow.Flow, err error) {
+	flow, err = domainFlow.ParseFlow(definition)
+	if err != nil {
+		return flow, pkgError.ValidationError(err.Error())
+	}
+	if flow.ID == "" {
+		flow.ID = flowID
+	}
+	if flow.ID != flowID {
+		return flow, pkgError.ValidationError("id: must match the flow being updated.")
+	}
+	if err = validations.ValidateFlow(ctx, flow); err != nil {
+		return flow, err
+	}
+
+	stored, err := service.repo.GetFlow(flowID)
+	if err != nil {
+		return flow, err
+	}
+	flow.CreatedAt = stored.CreatedAt
+	flow.UpdatedAt = time.Now()
+
+	if err = service.repo.UpdateFlow(&flow); err != nil {
+		return flow, err
+	}
+	return flow, service.reloadFlows()
+}
+
+func (service *serviceFlow) DeleteFlow(_ context.Context, flowID string) error {
+	if err := service.repo.DeleteFlow(flowID); err != nil {
+		return err
+	}
+	return service.reloadFlows()
+}
+
+func (service *serviceFlow) ListSessions(ctx context.Context, filter domainFlow.SessionFilter) ([]domainFlow.Session, error) {
+	if err := validations.ValidateFlowSessionFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListSessions(filter)
+}
+
+func (service *serviceFlow) ReleaseSession(_ context.Context, accountID, contactJID string) error {
+	if !strings.Contains(contactJID, "@") {
+		contactJID += "@" + types.DefaultUserServer
+	}
+
+	lock := service.lock(accountID, contactJID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := service.repo.GetSession(accountID, contactJID)
+	if err != nil {
+		return err
+	}
+	if !session.IsOpen() {
+		return nil
+	}
+
+	session.Status = domainFlow.SessionReleased
+	session.UpdatedAt = time.Now()
+	session.ExpiresAt = nil
+	return service.repo.SaveSession(session)
+}
+
+// Respond advances the open session of the contact or starts the flow the message triggers. Messages of
+// contacts handed off to a human are swallowed so no other automation answers them.
+func (service *serviceFlow) Respond(ctx context.Context, message domainAutoReply.Incoming) bool {
+	if message.IsGroup {
+		return false
+	}
+	now := message.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	lock := service.lock(message.AccountID, message.ChatJID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := service.repo.GetSession(message.AccountID, message.ChatJID)
+	var notFound pkgError.NotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		logrus.Warnf("Failed loading flow session of %s: %v", message.ChatJID, err)
+		return false
+	}
+
+	if session != nil && session.IsOpen() {
+		switch {
+		case session.IsExpired(now):
+			service.expire(ctx, session)
+		case session.Status == domainFlow.SessionHandoff:
+			return true
+		default:
+			if flow := service.flow(session.FlowID); flow != nil {
+				replies := flow.Advance(session, message.Text)
+				service.save(session, *flow, now)
+				service.send(ctx, session, replies)
+				return true
+			}
+			// The flow was deleted, the contact starts over
+			session.Status = domainFlow.SessionCompleted
+			service.save(session, domainFlow.Flow{}, now)
+		}
+	}
+
+	for _, flow := range *service.flows.Load() {
+		if !flow.IsTriggeredBy(message.AccountID, message.Text) {
+			continue
+		}
+
+		phone, _, _ := strings.Cut(message.SenderJID, "@")
+		pushName := message.PushName
+		if pushName == "" {
+			pushName = phone
+		}
+		session := &domainFlow.Session{
+			AccountID:  message.AccountID,
+			ContactJID: message.ChatJID,
+			FlowID:     flow.ID,
+			Vars:       map[string]string{"pushname": pushName, "phone": phone, "message": strings.TrimSpace(message.Text)},
+			StartedAt:  now,
+		}
+		replies := flow.Enter(session, flow.Start)
+		service.save(session, flow, now)
+		service.send(ctx, session, replies)
+		return true
+	}
+	return false
+}
+
+// expireSessions ends the sessions that timed out without waiting for the contact to write again
+func (service *serviceFlow) expireSessions() {
+	ticker := time.NewTicker(flowSweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		sessions, err := service.repo.ListExpiredSessions(time.Now())
+		if err != nil {
+			logrus.Warnf("Failed listing expired flow sessions: %v", err)
+			continue
+		}
+		for _, expired := range sessions {
+			service.expireIfStale(expired.AccountID, expired.ContactJID)
+		}
+	}
+}
+
+// expireIfStale expires the session unless the contact wrote since it was listed
+func (service *serviceFlow) expireIfStale(accountID, contactJID string) {
+	lock := service.lock(accountID, contactJID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := service.repo.GetSession(accountID, contactJID)
+	if err != nil || !session.IsExpired(time.Now()) {
+		return
+	}
+	service.expire(context.Background(), session)
+}
+
+// expire ends the session and tells contacts that stopped answering the flow, handoffs end silently
+func (service *serviceFlow) expire(ctx context.Context, session *domainFlow.Session) {
+	wasActive := session.Status == domainFlow.SessionActive
+
+	session.Status = domainFlow.SessionExpired
+	session.UpdatedAt = time.Now()
+	session.ExpiresAt = nil
+	if err := service.repo.SaveSession(session); err != nil {
+		logrus.Warnf("Failed expiring flow session of %s: %v", session.ContactJID, err)
+		return
+	}
+
+	if flow := service.flow(session.FlowID); wasActive && flow != nil && flow.TimeoutMessage != "" {
+		service.send(ctx, session, []string{domainFlow.Render(flow.TimeoutMessage, session.Vars)})
+	}
+}
+
+// save stores the session with the expiry of its status
+func (service *serviceFlow) save(session *domainFlow.Session, flow domainFlow.Flow, now time.Time) {
+	session.UpdatedAt = now
+	session.ExpiresAt = nil
+	switch session.Status {
+	case domainFlow.SessionActive:
+		expiresAt := now.Add(flow.Timeout())
+		session.ExpiresAt = &expiresAt
+	case domainFlow.SessionHandoff:
+		if flow.HandoffTimeoutSeconds > 0 {
+			expiresAt := now.Add(time.Duration(flow.HandoffTimeoutSeconds) * time.Second)
+			session.ExpiresAt = &expiresAt
+		}
+	}
+
+	if err := service.repo.SaveSession(session); err != nil {
+		logrus.Warnf("Failed saving flow session of %s: %v", session.ContactJID, err)
+	}
+}
+
+// send delivers the replies in order, a failed reply drops the ones after it
+func (service *serviceFlow) send(ctx context.Context, session *domainFlow.Session, replies []string) {
+	if err := service.sendReplies(ctx, session, replies); err != nil {
+		logrus.Warnf("Flow %s failed to answer %s: %v", session.FlowID, session.ContactJID, err)
+	}
+}
+
+func (service *serviceFlow) sendReplies(ctx context.Context, session *domainFlow.Session, replies []string) (err error) {
+	defer recoverSend(&err)
+
+	base := domainSend.BaseRequest{Phone: session.ContactJID}
+	if session.AccountID != domainEventSink.DefaultAccountID {
+		base.AccountID = session.AccountID
+	}
+	for _, reply := range replies {
+		if _, err = service.sendService.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: reply}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *serviceFlow) flow(flowID string) *domainFlow.Flow {
+	for _, flow := range *service.flows.Load() {
+		if flow.ID == flowID {
+			return &flow
+		}
+	}
+	return nil
+}
+
+func (service *serviceFlow) lock(accountID, contactJID string) *sync.Mutex {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(accountID + "|" + contactJID))
+	return &service.locks[hash.Sum32()%uint32(len(service.locks))]
+}
+
+// reloadFlows swaps in the stored flows for incoming messages
+func (service *serviceFlow) reloadFlows() error {
+	flows, err := service.repo.ListFlows()
+	if err != nil {
+		empty := make([]domainFlow.Flow, 0)
+		service.flows.CompareAndSwap(nil, &empty)
+		return err
+	}
+	service.flows.Store(&flows)
+	return nil
+}
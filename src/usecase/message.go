@@ -14,7 +14,6 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
-	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waCommon"
 	"go.mau.fi/whatsmeow/proto/waE2E"
@@ -227,57 +226,14 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	// Create a downloadable message interface based on media type
-	var downloadableMsg interface{}
-
-	switch message.MediaType {
-	case "image":
-		downloadableMsg = &waE2E.ImageMessage{
-			URL:           proto.String(message.URL),
-			MediaKey:      message.MediaKey,
-			FileSHA256:    message.FileSHA256,
-			FileEncSHA256: message.FileEncSHA256,
-			FileLength:    proto.Uint64(message.FileLength),
-		}
-	case "video":
-		downloadableMsg = &waE2E.VideoMessage{
-			URL:           proto.String(message.URL),
-			MediaKey:      message.MediaKey,
-			FileSHA256:    message.FileSHA256,
-			FileEncSHA256: message.FileEncSHA256,
-			FileLength:    proto.Uint64(message.FileLength),
-		}
-	case "audio":
-		downloadableMsg = &waE2E.AudioMessage{
-			URL:           proto.String(message.URL),
-			MediaKey:      message.MediaKey,
-			FileSHA256:    message.FileSHA256,
-			FileEncSHA256: message.FileEncSHA256,
-			FileLength:    proto.Uint64(message.FileLength),
-		}
-	case "document":
-		downloadableMsg = &waE2E.DocumentMessage{
-			URL:           proto.String(message.URL),
-			MediaKey:      message.MediaKey,
-			FileSHA256:    message.FileSHA256,
-			FileEncSHA256: message.FileEncSHA256,
-			FileLength:    proto.Uint64(message.FileLength),
-			FileName:      proto.String(message.Filename),
-		}
-	case "sticker":
-		downloadableMsg = &waE2E.StickerMessage{
-			URL:           proto.String(message.URL),
-			MediaKey:      message.MediaKey,
-			FileSHA256:    message.FileSHA256,
-			FileEncSHA256: message.FileEncSHA256,
-			FileLength:    proto.Uint64(message.FileLength),
-		}
-	default:
-		return response, fmt.Errorf("unsupported media type: %s", message.MediaType)
+	// Create a downloadable message based on media type
+	downloadableMsg, err := storedDownloadable(message)
+	if err != nil {
+		return response, err
 	}
 
 	// Download the media using existing utils.ExtractMedia function
-	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
+	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg)
 	if err != nil {
 		return response, fmt.Errorf("failed to download media: %v", err)
 	}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
+)
+
+type servicePoll struct {
+	repo domainPoll.IPollRepository
+}
+
+func NewPollService(repo domainPoll.IPollRepository) domainPoll.IPollUsecase {
+	return &servicePoll{repo: repo}
+}
+
+func (service *servicePoll) GetResults(_ context.Context, messageID string) (results domainPoll.Results, err error) {
+	poll, err := service.repo.GetPoll(messageID)
+	if err != nil {
+		return results, err
+	}
+	votes, err := service.repo.ListVotes(messageID)
+	if err != nil {
+		return results, err
+	}
+	return poll.Tally(votes), nil
+}
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// relayMessageRetention is how long relayed messages are kept, replies to older ones aren't bridged back
+	relayMessageRetention = 7 * 24 * time.Hour
+	relayPruneInterval    = time.Hour
+)
+
+type serviceRelay struct {
+	repo        domainRelay.IRelayRepository
+	sendService domainSend.ISendUsecase
+
+	rules atomic.Pointer[[]domainRelay.Rule]
+}
+
+// NewRelayService loads the stored relay rules, relays incoming messages with them and bridges replies back
+func NewRelayService(repo domainRelay.IRelayRepository, sendService domainSend.ISendUsecase) domainRelay.IRelayUsecase {
+	service := &serviceRelay{
+		repo:        repo,
+		sendService: sendService,
+	}
+	if err := service.reloadRules(); err != nil {
+		logrus.Errorf("Failed to load relay rules: %v", err)
+	}
+	whatsapp.SetRelayer(service)
+	go service.pruneMessages()
+	return service
+}
+
+func (service *serviceRelay) ListRules(_ context.Context) ([]domainRelay.Rule, error) {
+	return service.repo.ListRules()
+}
+
+func (service *serviceRelay) GetRule(_ context.Context, ruleID string) (rule domainRelay.Rule, err error) {
+	stored, err := service.repo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceRelay) CreateRule(ctx context.Context, request domainRelay.RuleRequest) (rule domainRelay.Rule, err error) {
+	if err = validations.ValidateRelayRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	now := time.Now()
+	rule = relayRuleFromRequest(request)
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err = service.repo.CreateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service *serviceRelay) UpdateRule(ctx context.Context, ruleID string, request domainRelay.RuleRequest) (rule domainRelay.Rule, err error) {
+	if err = validations.ValidateRelayRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	stored, err := service.repo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+
+	rule = relayRuleFromRequest(request)
+	rule.ID = stored.ID
+	rule.CreatedAt = stored.CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if err = service.repo.UpdateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service *serviceRelay) DeleteRule(_ context.Context, ruleID string) error {
+	if err := service.repo.DeleteRule(ruleID); err != nil {
+		return err
+	}
+	return service.reloadRules()
+}
+
+func (service *serviceRelay) ListMessages(ctx context.Context, filter domainRelay.MessageFilter) ([]domainRelay.Message, error) {
+	if err := validations.ValidateRelayMessageFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListMessages(filter)
+}
+
+// Relay sends the message to the destination of every matching rule. Replies to a relayed message are bridged
+// back to the chat it came from instead, when its rule asks for it.
+func (service *serviceRelay) Relay(ctx context.Context, message domainRelay.Incoming) {
+	// Relayed messages reach the destination as incoming messages when another account of the gateway is in the chat
+	if _, err := service.repo.GetMessage(message.MessageID); err == nil {
+		return
+	}
+	if service.bridge(ctx, message) {
+		return
+	}
+
+	media := &relayDownload{media: message.Media}
+	for _, rule := range *service.rules.Load() {
+		if !rule.Matches(message.AccountID, message.ChatJID) {
+			continue
+		}
+
+		record := service.recorder(rule, domainRelay.DirectionRelay, rule.DestinationAccount(), rule.DestinationChatJID, message)
+		err := service.send(ctx, rule.DestinationAccount(), rule.DestinationChatJID, rule.Attribution(message), message, media, nil, record)
+		if err != nil {
+			logrus.Warnf("Relay rule %s failed relaying %s from %s: %v", rule.ID, message.MessageID, message.ChatJID, err)
+		}
+	}
+}
+
+// bridge sends a reply to a relayed message back to the chat the relayed message came from, quoting the original
+func (service *serviceRelay) bridge(ctx context.Context, message domainRelay.Incoming) bool {
+	if message.QuotedMessageID == "" {
+		return false
+	}
+	relayed, err := service.repo.GetMessage(message.QuotedMessageID)
+	if err != nil || relayed.Direction != domainRelay.DirectionRelay ||
+		relayed.AccountID != message.AccountID || relayed.ChatJID != message.ChatJID {
+		return false
+	}
+
+	var rule *domainRelay.Rule
+	for _, candidate := range *service.rules.Load() {
+		if candidate.ID == relayed.RuleID && candidate.Enabled && candidate.BridgeReplies {
+			rule = &candidate
+			break
+		}
+	}
+	if rule == nil {
+		return false
+	}
+
+	record := service.recorder(*rule, domainRelay.DirectionBridge, relayed.SourceAccountID, relayed.SourceChatJID, message)
+	err = service.send(ctx, relayed.SourceAccountID, relayed.SourceChatJID, rule.Attribution(message), message,
+		&relayDownload{media: message.Media}, &relayed.SourceMessageID, record)
+	if err != nil {
+		logrus.Warnf("Relay rule %s failed bridging reply %s back to %s: %v", rule.ID, message.MessageID, relayed.SourceChatJID, err)
+	}
+	return true
+}
+
+// send relays the message with the header on top. Audio and stickers have no caption, their header goes
+// ahead as a text message. Every message is recorded before it is sent.
+func (service *serviceRelay) send(ctx context.Context, accountID, chatJID, header string, message domainRelay.Incoming, media *relayDownload, replyTo *string, record func(messageID string) error) (err error) {
+	defer recoverSend(&err)
+
+	base := domainSend.BaseRequest{Phone: chatJID}
+	if accountID != domainEventSink.DefaultAccountID {
+		base.AccountID = accountID
+	}
+
+	text := header
+	if message.Text != "" {
+		text += "\n" + message.Text
+	}
+	base.ReplyMessageID = replyTo
+	request := domainSend.RelayRequest{BaseRequest: base, Text: text}
+
+	if message.Media != nil {
+		data, err := media.get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to download %s: %w", message.Media.Type, err)
+		}
+		request.Media = &domainSend.RelayMedia{
+			Type:     message.Media.Type,
+			Data:     data,
+			MimeType: message.Media.MimeType,
+			FileName: message.Media.FileName,
+		}
+
+		if message.Media.Type == domainSend.RelayMediaAudio || message.Media.Type == domainSend.RelayMediaSticker {
+			if err := service.sendRecorded(ctx, domainSend.RelayRequest{BaseRequest: base, Text: header}, record); err != nil {
+				return err
+			}
+			request.Text = ""
+			request.ReplyMessageID = nil
+		}
+	}
+
+	return service.sendRecorded(ctx, request, record)
+}
+
+// sendRecorded records the message under the ID it is sent with first. Another account of the gateway in the
+// destination chat can receive the copy before SendRelay returns, it must already be known as relayed then.
+func (service *serviceRelay) sendRecorded(ctx context.Context, request domainSend.RelayRequest, record func(messageID string) error) error {
+	var recorded string
+	request.BeforeSend = func(messageID string) error {
+		if err := record(messageID); err != nil {
+			return err
+		}
+		recorded = messageID
+		return nil
+	}
+
+	if _, err := service.sendService.SendRelay(ctx, request); err != nil {
+		if recorded != "" {
+			if err := service.repo.DeleteMessage(recorded); err != nil {
+				logrus.Warnf("Failed forgetting relayed message %s that was not sent: %v", recorded, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// recorder keeps the relayed messages, so replies to them are bridged back and copies of them aren't relayed again
+func (service *serviceRelay) recorder(rule domainRelay.Rule, direction, accountID, chatJID string, message domainRelay.Incoming) func(messageID string) error {
+	return func(messageID string) error {
+		err := service.repo.SaveMessage(&domainRelay.Message{
+			MessageID:       messageID,
+			AccountID:       accountID,
+			ChatJID:         chatJID,
+			RuleID:          rule.ID,
+			Direction:       direction,
+			SourceAccountID: message.AccountID,
+			SourceChatJID:   message.ChatJID,
+			SourceMessageID: message.MessageID,
+			SourceSenderJID: message.SenderJID,
+			CreatedAt:       time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed recording relayed message %s: %w", messageID, err)
+		}
+		return nil
+	}
+}
+
+// reloadRules swaps the stored rules in for incoming messages
+func (service *serviceRelay) reloadRules() error {
+	rules, err := service.repo.ListRules()
+	if err != nil {
+		empty := make([]domainRelay.Rule, 0)
+		service.rules.CompareAndSwap(nil, &empty)
+		return err
+	}
+	service.rules.Store(&rules)
+	return nil
+}
+
+// pruneMessages forgets relayed messages past their retention
+func (service *serviceRelay) pruneMessages() {
+	ticker := time.NewTicker(relayPruneInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := service.repo.DeleteMessagesBefore(time.Now().Add(-relayMessageRetention))
+		if err != nil {
+			logrus.Warnf("Failed pruning relayed messages: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			logrus.Debugf("Pruned %d relayed messages", deleted)
+		}
+	}
+}
+
+func relayRuleFromRequest(request domainRelay.RuleRequest) domainRelay.Rule {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+	return domainRelay.Rule{
+		Name:                 request.Name,
+		Enabled:              enabled,
+		SourceAccountID:      request.SourceAccountID,
+		SourceChatJID:        request.SourceChatJID,
+		DestinationAccountID: request.DestinationAccountID,
+		DestinationChatJID:   request.DestinationChatJID,
+		Header:               request.Header,
+		BridgeReplies:        request.BridgeReplies,
+	}
+}
+
+// relayDownload downloads the media of a message once for all the rules relaying it
+type relayDownload struct {
+	media *domainRelay.Media
+
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func (download *relayDownload) get(ctx context.Context) ([]byte, error) {
+	if download.media == nil || download.media.Download == nil {
+		return nil, errors.New("the message has no media")
+	}
+	download.once.Do(func() {
+		download.data, download.err = download.media.Download(ctx)
+	})
+	return download.data, download.err
+}
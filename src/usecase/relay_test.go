@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type memoryRelayRepository struct {
+	domainRelay.IRelayRepository
+	messages map[string]domainRelay.Message
+}
+
+func (r *memoryRelayRepository) SaveMessage(message *domainRelay.Message) error {
+	r.messages[message.MessageID] = *message
+	return nil
+}
+
+func (r *memoryRelayRepository) GetMessage(messageID string) (*domainRelay.Message, error) {
+	message, ok := r.messages[messageID]
+	if !ok {
+		return nil, pkgError.NotFoundError("not found")
+	}
+	return &message, nil
+}
+
+func (r *memoryRelayRepository) DeleteMessage(messageID string) error {
+	delete(r.messages, messageID)
+	return nil
+}
+
+// deliveringSender delivers every relayed message to the gateway again before SendRelay returns, like another
+// account of the gateway in the destination chat receiving it
+type deliveringSender struct {
+	domainSend.ISendUsecase
+	relay *serviceRelay
+	sent  int
+	err   error
+}
+
+func (s *deliveringSender) SendRelay(ctx context.Context, request domainSend.RelayRequest) (domainSend.GenericResponse, error) {
+	messageID := fmt.Sprintf("3EB0%d", s.sent)
+	if err := request.BeforeSend(messageID); err != nil {
+		return domainSend.GenericResponse{}, err
+	}
+	if s.err != nil {
+		return domainSend.GenericResponse{}, s.err
+	}
+	s.sent++
+	s.relay.Relay(ctx, domainRelay.Incoming{AccountID: request.AccountID, ChatJID: request.Phone, MessageID: messageID, Text: request.Text})
+	return domainSend.GenericResponse{MessageID: messageID}, nil
+}
+
+func TestRelayRecordsBeforeSending(t *testing.T) {
+	repo := &memoryRelayRepository{messages: map[string]domainRelay.Message{}}
+	service := &serviceRelay{repo: repo}
+	sender := &deliveringSender{relay: service}
+	service.sendService = sender
+
+	// Two rules relaying the chats of two accounts into each other
+	rules := []domainRelay.Rule{
+		{ID: "a-to-b", Enabled: true, SourceAccountID: "a", SourceChatJID: "111@g.us", DestinationAccountID: "b", DestinationChatJID: "222@g.us"},
+		{ID: "b-to-a", Enabled: true, SourceAccountID: "b", SourceChatJID: "222@g.us", DestinationAccountID: "a", DestinationChatJID: "111@g.us"},
+	}
+	service.rules.Store(&rules)
+
+	service.Relay(context.Background(), domainRelay.Incoming{AccountID: "a", ChatJID: "111@g.us", SenderJID: "6289@s.whatsapp.net", MessageID: "ORIGINAL", Text: "hi"})
+
+	if sender.sent != 1 {
+		t.Fatalf("expected the message to be relayed once, relayed %d times", sender.sent)
+	}
+	if relayed, ok := repo.messages["3EB00"]; !ok || relayed.RuleID != "a-to-b" || relayed.SourceMessageID != "ORIGINAL" {
+		t.Errorf("unexpected recorded messages %+v", repo.messages)
+	}
+
+	// A message that could not be sent is forgotten again
+	sender.err = errors.New("not connected")
+	service.Relay(context.Background(), domainRelay.Incoming{AccountID: "a", ChatJID: "111@g.us", MessageID: "LATER"})
+	if len(repo.messages) != 1 {
+		t.Errorf("expected the unsent message to be forgotten, recorded %+v", repo.messages)
+	}
+}
@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// scheduleTick is how often the scheduler looks for due messages
+	scheduleTick = time.Second
+	// scheduleDuePage is how many due messages are loaded per tick
+	scheduleDuePage = 50
+)
+
+type serviceSchedule struct {
+	repo        domainSchedule.IScheduleRepository
+	sendService domainSend.ISendUsecase
+
+	// mu keeps edits and cancellations from crossing a run of the same schedule
+	mu sync.Mutex
+}
+
+// NewScheduleService sends the scheduled messages when they are due, including the ones that came due while
+// the gateway was down. sendService must send right away, it is not given the scheduling decorator.
+func NewScheduleService(repo domainSchedule.IScheduleRepository, sendService domainSend.ISendUsecase) domainSchedule.IScheduleUsecase {
+	service := &serviceSchedule{
+		repo:        repo,
+		sendService: sendService,
+	}
+	go service.loop()
+	return service
+}
+
+func (service *serviceSchedule) CreateSchedule(ctx context.Context, request domainSchedule.CreateRequest) (schedule domainSchedule.Schedule, err error) {
+	if err = validations.ValidateScheduleRequest(ctx, request); err != nil {
+		return schedule, err
+	}
+	utils.SanitizePhone(&request.Phone)
+
+	// The message is validated now, a mistake would only show when it is due
+	payload, err := recipientPayload(request.Payload, request.AccountID, request.Phone)
+	if err != nil {
+		return schedule, err
+	}
+	if _, err = payloadSenders[request.Type](ctx, service.sendService, payload, true); err != nil {
+		return schedule, err
+	}
+	if request.Payload, err = storedPayload(request.Payload); err != nil {
+		return schedule, err
+	}
+
+	now := time.Now()
+	schedule = domainSchedule.Schedule{
+		ID:           uuid.NewString(),
+		AccountID:    request.AccountID,
+		Type:         request.Type,
+		Phone:        request.Phone,
+		Payload:      request.Payload,
+		Recurrence:   request.Recurrence,
+		MissedPolicy: request.MissedPolicy,
+		Status:       domainSchedule.StatusScheduled,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	schedule.SendAt, _ = time.Parse(time.RFC3339, request.SendAt)
+	if err = scheduleFirstRun(&schedule, now); err != nil {
+		return schedule, err
+	}
+
+	if err = service.repo.CreateSchedule(&schedule); err != nil {
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+func (service *serviceSchedule) ListSchedules(ctx context.Context, filter domainSchedule.Filter) ([]domainSchedule.Schedule, error) {
+	if err := validations.ValidateScheduleFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	if filter.Phone != "" {
+		utils.SanitizePhone(&filter.Phone)
+	}
+	return service.repo.ListSchedules(filter)
+}
+
+func (service *serviceSchedule) GetSchedule(_ context.Context, scheduleID string) (schedule domainSchedule.Schedule, err error) {
+	stored, err := service.repo.GetSchedule(scheduleID)
+	if err != nil {
+		return schedule, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceSchedule) UpdateSchedule(ctx context.Context, scheduleID string, request domainSchedule.UpdateRequest) (schedule domainSchedule.Schedule, err error) {
+	if err = validations.ValidateScheduleUpdate(ctx, request); err != nil {
+		return schedule, err
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	stored, err := service.repo.GetSchedule(scheduleID)
+	if err != nil {
+		return schedule, err
+	}
+	if stored.Status != domainSchedule.StatusScheduled {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("scheduled message %s is %s, it cannot be edited", scheduleID, stored.Status))
+	}
+
+	schedule = *stored
+	if request.Phone != "" {
+		schedule.Phone = request.Phone
+		utils.SanitizePhone(&schedule.Phone)
+	}
+	if request.SendAt != "" {
+		schedule.SendAt, _ = time.Parse(time.RFC3339, request.SendAt)
+	}
+	if request.Recurrence != nil {
+		schedule.Recurrence = *request.Recurrence
+	}
+	if request.MissedPolicy != nil {
+		schedule.MissedPolicy = *request.MissedPolicy
+	}
+	if len(request.Payload) > 0 {
+		schedule.Payload = request.Payload
+	}
+
+	payload, err := recipientPayload(schedule.Payload, schedule.AccountID, schedule.Phone)
+	if err != nil {
+		return schedule, err
+	}
+	if _, err = payloadSenders[schedule.Type](ctx, service.sendService, payload, true); err != nil {
+		return schedule, err
+	}
+	if schedule.Payload, err = storedPayload(schedule.Payload); err != nil {
+		return schedule, err
+	}
+
+	// Changing when the message is sent starts over from the first occurrence
+	now := time.Now()
+	if request.SendAt != "" || request.Recurrence != nil {
+		if err = scheduleFirstRun(&schedule, now); err != nil {
+			return schedule, err
+		}
+	}
+	schedule.UpdatedAt = now
+
+	if err = service.repo.UpdateSchedule(&schedule); err != nil {
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+func (service *serviceSchedule) CancelSchedule(_ context.Context, scheduleID string) (schedule domainSchedule.Schedule, err error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	stored, err := service.repo.GetSchedule(scheduleID)
+	if err != nil {
+		return schedule, err
+	}
+	if stored.Status != domainSchedule.StatusScheduled {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("scheduled message %s is %s, it cannot be cancelled", scheduleID, stored.Status))
+	}
+
+	schedule = *stored
+	schedule.Status = domainSchedule.StatusCancelled
+	schedule.NextRunAt, schedule.RetryAt = nil, nil
+	schedule.UpdatedAt = time.Now()
+	if err = service.repo.UpdateSchedule(&schedule); err != nil {
+		return schedule, err
+	}
+	return schedule, nil
+}
+
+// scheduleFirstRun sets the next run to the first occurrence, a recurring schedule that started in the past
+// continues with its next occurrence from now on
+func scheduleFirstRun(schedule *domainSchedule.Schedule, now time.Time) error {
+	from := now.Add(-domainSchedule.MissedGrace)
+	next, ok, err := schedule.NextRun(from, true)
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("recurrence: %v", err))
+	}
+	if !ok {
+		if schedule.Recurrence != "" {
+			return pkgError.ValidationError("recurrence: has no occurrence after now")
+		}
+		return pkgError.ValidationError("send_at: must be in the future")
+	}
+	schedule.NextRunAt = &next
+	schedule.RetryAt = nil
+	return nil
+}
+
+// loop runs the due schedules every tick, one after another
+func (service *serviceSchedule) loop() {
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		service.runDue()
+	}
+}
+
+// runDue runs a page of the due schedules
+func (service *serviceSchedule) runDue() {
+	due, err := service.repo.ListDue(time.Now(), scheduleDuePage)
+	if err != nil {
+		logrus.Errorf("Failed loading due scheduled messages: %v", err)
+		return
+	}
+	for _, schedule := range due {
+		service.run(schedule.ID)
+	}
+}
+
+// run sends the schedule when it is still due, or skips a missed run if its policy says so, and moves it to
+// its next occurrence. A recurring schedule that missed several occurrences runs once for all of them. While the
+// client is offline the run is left due and tried again later, backing off like a bulk send.
+func (service *serviceSchedule) run(scheduleID string) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	// It may have been edited or cancelled since it was loaded
+	schedule, err := service.repo.GetSchedule(scheduleID)
+	if err != nil {
+		logrus.Errorf("Failed loading scheduled message %s: %v", scheduleID, err)
+		return
+	}
+	now := time.Now()
+	if schedule.Status != domainSchedule.StatusScheduled || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+		return
+	}
+
+	due := *schedule.NextRunAt
+	deferred := *schedule
+	schedule.RetryAt = nil
+	schedule.LastRunAt = &now
+	schedule.LastMessageID = ""
+	schedule.LastError = ""
+	if domainSchedule.Missed(due, now) && schedule.Policy(config.WhatsappScheduleMissedPolicy) == domainSchedule.MissedSkip {
+		logrus.Infof("Skipping scheduled message %s, it was due at %s", schedule.ID, due.Format(time.RFC3339))
+		schedule.LastStatus = domainSchedule.StatusSkipped
+		schedule.LastError = fmt.Sprintf("missed the run due at %s", due.Format(time.RFC3339))
+	} else {
+		response, err := service.send(*schedule)
+		if isClientOffline(err) {
+			// The run stays due, waiting runs must not keep the others of the page from running
+			retryAt := now.Add(nextOfflineRetry(now.Sub(due)))
+			deferred.RetryAt = &retryAt
+			logrus.Debugf("Scheduled message %s waiting until %s for the client to log in: %v", schedule.ID, retryAt.Format(time.RFC3339), err)
+			if err := service.repo.UpdateSchedule(&deferred); err != nil {
+				logrus.Errorf("Failed updating scheduled message %s: %v", schedule.ID, err)
+			}
+			return
+		}
+		schedule.Runs++
+		if err != nil {
+			logrus.Warnf("Scheduled message %s failed: %v", schedule.ID, err)
+			schedule.LastStatus = domainSchedule.StatusFailed
+			schedule.LastError = err.Error()
+		} else {
+			schedule.LastStatus = domainSchedule.StatusSent
+			schedule.LastMessageID = response.MessageID
+		}
+	}
+
+	next, ok, err := schedule.NextRun(time.Now(), false)
+	switch {
+	case err != nil:
+		logrus.Errorf("Scheduled message %s stopped, invalid recurrence: %v", schedule.ID, err)
+		schedule.Status = domainSchedule.StatusFailed
+		schedule.NextRunAt = nil
+	case ok:
+		schedule.NextRunAt = &next
+	case schedule.Recurrence != "":
+		schedule.Status = domainSchedule.StatusCompleted
+		schedule.NextRunAt = nil
+	default:
+		schedule.Status = schedule.LastStatus
+		schedule.NextRunAt = nil
+	}
+	schedule.UpdatedAt = time.Now()
+
+	if err := service.repo.UpdateSchedule(schedule); err != nil {
+		logrus.Errorf("Failed updating scheduled message %s: %v", schedule.ID, err)
+	}
+}
+
+// send sends the payload of the schedule with its original account
+func (service *serviceSchedule) send(schedule domainSchedule.Schedule) (response domainSend.GenericResponse, err error) {
+	defer recoverSend(&err)
+
+	payload, err := recipientPayload(schedule.Payload, schedule.AccountID, schedule.Phone)
+	if err != nil {
+		return response, err
+	}
+	sender, ok := payloadSenders[schedule.Type]
+	if !ok {
+		return response, fmt.Errorf("unknown message type %q", schedule.Type)
+	}
+	return sender(context.Background(), service.sendService, payload, false)
+}
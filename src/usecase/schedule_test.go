@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	infraSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/schedule"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestOfflineSchedulesDoNotHoldUpOthers(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	repo := infraSchedule.NewRepository(db)
+	if err := repo.InitializeSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	// More runs of a disconnected account than a tick loads, all due before the run of another account
+	now := time.Now()
+	create := func(id, accountID string, due time.Time) {
+		schedule := domainSchedule.Schedule{
+			ID: id, AccountID: accountID, Type: domainSend.TypeText, Phone: "6281234567890",
+			Payload: json.RawMessage(`{"message": "Your order ships today"}`), SendAt: due,
+			MissedPolicy: domainSchedule.MissedFire, Status: domainSchedule.StatusScheduled, NextRunAt: &due,
+			CreatedAt: now, UpdatedAt: now,
+		}
+		if err := repo.CreateSchedule(&schedule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	offline := scheduleDuePage + 10
+	for i := range offline {
+		create(fmt.Sprintf("shop-%02d", i), "shop", now.Add(-30*time.Second).Add(time.Duration(i)*time.Millisecond))
+	}
+	create("other", "unknown", now.Add(-time.Second))
+
+	service := &serviceSchedule{
+		repo:        repo,
+		sendService: &serviceSend{accountManager: disconnectedAccounts{}, accountRepo: memoryAccountRepository{accounts: map[string]bool{"shop": true}}},
+	}
+	service.runDue()
+	service.runDue()
+
+	// The other account ran, its unknown account fails the run for good
+	other, err := repo.GetSchedule("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Runs != 1 || other.LastStatus != domainSchedule.StatusFailed {
+		t.Errorf("schedule of another account should have run, got %+v", other)
+	}
+
+	// The waiting runs keep their due time and are not loaded again before their retry time
+	for i := range offline {
+		schedule, err := repo.GetSchedule(fmt.Sprintf("shop-%02d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schedule.Runs != 0 || schedule.Status != domainSchedule.StatusScheduled || schedule.RetryAt == nil ||
+			!schedule.RetryAt.After(now) || schedule.NextRunAt.After(now) {
+			t.Fatalf("offline run should wait with its due time, got %+v", schedule)
+		}
+	}
+	if due, err := repo.ListDue(time.Now(), scheduleDuePage); err != nil || len(due) != 0 {
+		t.Errorf("waiting runs should not be due before their retry time, got %d (%v)", len(due), err)
+	}
+}
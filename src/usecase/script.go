@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// scriptConcurrency is how many scripts run at the same time, further events wait for a slot
+	scriptConcurrency = 8
+	// scriptHTTPMaxBody is the largest response body a script receives, longer bodies are cut
+	scriptHTTPMaxBody  = 1 << 20
+	scriptMaxTagLength = 64
+)
+
+type serviceScript struct {
+	repo           domainScript.IScriptRepository
+	sendService    domainSend.ISendUsecase
+	messageService domainMessage.IMessageUsecase
+	httpClient     *http.Client
+
+	scripts atomic.Pointer[[]compiledScript]
+	slots   chan struct{}
+}
+
+type compiledScript struct {
+	domainScript.Script
+	program *domainScript.Program
+}
+
+// NewScriptService loads the stored scripts and runs them on the message, receipt and group events of their account
+func NewScriptService(repo domainScript.IScriptRepository, sendService domainSend.ISendUsecase, messageService domainMessage.IMessageUsecase) domainScript.IScriptUsecase {
+	service := &serviceScript{
+		repo:           repo,
+		sendService:    sendService,
+		messageService: messageService,
+		slots:          make(chan struct{}, scriptConcurrency),
+	}
+	service.httpClient = &http.Client{
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			if !scriptURLAllowed(request.URL.String(), config.WhatsappScriptHTTPAllowlist) {
+				return fmt.Errorf("redirect to %s is not allow-listed", request.URL.Redacted())
+			}
+			return nil
+		},
+	}
+	if err := service.reloadScripts(); err != nil {
+		logrus.Errorf("Failed to load scripts: %v", err)
+	}
+	whatsapp.SetScriptRunner(service)
+	return service
+}
+
+func (service *serviceScript) ListScripts(_ context.Context) ([]domainScript.Script, error) {
+	return service.repo.ListScripts()
+}
+
+func (service *serviceScript) GetScript(_ context.Context, scriptID string) (script domainScript.Script, err error) {
+	stored, err := service.repo.GetScript(scriptID)
+	if err != nil {
+		return script, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceScript) CreateScript(ctx context.Context, request domainScript.ScriptRequest) (script domainScript.Script, err error) {
+	if err = validations.ValidateScript(ctx, request); err != nil {
+		return script, err
+	}
+
+	now := time.Now()
+	script = scriptFromRequest(request)
+	script.ID = uuid.NewString()
+	script.CreatedAt = now
+	script.UpdatedAt = now
+
+	if err = service.repo.CreateScript(&script); err != nil {
+		return script, err
+	}
+	return script, service.reloadScripts()
+}
+
+func (service *serviceScript) UpdateScript(ctx context.Context, scriptID string, request domainScript.ScriptRequest) (script domainScript.Script, err error) {
+	if err = validations.ValidateScript(ctx, request); err != nil {
+		return script, err
+	}
+
+	stored, err := service.repo.GetScript(scriptID)
+	if err != nil {
+		return script, err
+	}
+
+	script = scriptFromRequest(request)
+	script.ID = stored.ID
+	script.Runs = stored.Runs
+	script.Failures = stored.Failures
+	script.LastError = stored.LastError
+	script.LastErrorAt = stored.LastErrorAt
+	script.CreatedAt = stored.CreatedAt
+	script.UpdatedAt = time.Now()
+
+	if err = service.repo.UpdateScript(&script); err != nil {
+		return script, err
+	}
+	return script, service.reloadScripts()
+}
+
+func (service *serviceScript) DeleteScript(_ context.Context, scriptID string) error {
+	if err := service.repo.DeleteScript(scriptID); err != nil {
+		return err
+	}
+	return service.reloadScripts()
+}
+
+func (service *serviceScript) ListErrors(ctx context.Context, scriptID string, filter domainScript.ErrorFilter) ([]domainScript.RunError, error) {
+	if err := validations.ValidateScriptErrorFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	if _, err := service.repo.GetScript(scriptID); err != nil {
+		return nil, err
+	}
+	return service.repo.ListErrors(scriptID, filter)
+}
+
+func (service *serviceScript) ListChatTags(ctx context.Context, filter domainScript.ChatTagFilter) ([]domainScript.ChatTag, error) {
+	if err := validations.ValidateChatTagFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListChatTags(filter)
+}
+
+// Run runs the scripts of the account subscribed to the event one after another
+func (service *serviceScript) Run(ctx context.Context, event domainScript.Event) {
+	for _, script := range *service.scripts.Load() {
+		if !script.Handles(event) {
+			continue
+		}
+
+		limits := domainScript.Limits{
+			Timeout:  script.Timeout(config.WhatsappScriptTimeout),
+			MaxSteps: config.WhatsappScriptMaxSteps,
+		}
+		service.slots <- struct{}{}
+		err := script.program.Run(ctx, event, &scriptActions{service: service, script: script.Script, event: event}, limits)
+		<-service.slots
+
+		var runErr *domainScript.RunError
+		if err != nil {
+			logrus.Warnf("Script %s failed on %s event of %s: %v", script.ID, event.Type, event.ChatJID, err)
+			runErr = &domainScript.RunError{
+				ScriptID:  script.ID,
+				EventType: event.Type,
+				ChatJID:   event.ChatJID,
+				Error:     err.Error(),
+				CreatedAt: time.Now(),
+			}
+		}
+		if err := service.repo.RecordRun(script.ID, runErr); err != nil {
+			logrus.Warnf("Failed recording run of script %s: %v", script.ID, err)
+		}
+	}
+}
+
+// reloadScripts compiles the stored scripts and swaps them in for incoming events
+func (service *serviceScript) reloadScripts() error {
+	scripts, err := service.repo.ListScripts()
+	if err != nil {
+		empty := make([]compiledScript, 0)
+		service.scripts.CompareAndSwap(nil, &empty)
+		return err
+	}
+
+	compiled := make([]compiledScript, 0, len(scripts))
+	for _, script := range scripts {
+		program, err := domainScript.Compile(script.ID, script.Source)
+		if err != nil {
+			logrus.Errorf("Failed to compile script %s: %v", script.ID, err)
+			continue
+		}
+		compiled = append(compiled, compiledScript{Script: script, program: program})
+	}
+	service.scripts.Store(&compiled)
+	return nil
+}
+
+func scriptFromRequest(request domainScript.ScriptRequest) domainScript.Script {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+	return domainScript.Script{
+		Name:      request.Name,
+		AccountID: request.AccountID,
+		Events:    request.Events,
+		Source:    request.Source,
+		Enabled:   enabled,
+		TimeoutMs: request.TimeoutMs,
+	}
+}
+
+// scriptURLAllowed reports whether the URL starts with one of the allow-listed URL prefixes. Scheme and host
+// must match exactly and the path only at segment boundaries, https://api.example.com/v1 allows /v1/users
+// but not /v10.
+func scriptURLAllowed(raw string, allowlist []string) bool {
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.User != nil {
+		return false
+	}
+
+	for _, entry := range allowlist {
+		allowed, err := url.Parse(strings.TrimSpace(entry))
+		if err != nil || allowed.Host == "" {
+			continue
+		}
+		if !strings.EqualFold(target.Scheme, allowed.Scheme) || !strings.EqualFold(target.Host, allowed.Host) {
+			continue
+		}
+		prefix := allowed.EscapedPath()
+		path := target.EscapedPath()
+		if prefix == "" || prefix == "/" || path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptActions carries out what a script asks for on the chat of the event it handles
+type scriptActions struct {
+	service *serviceScript
+	script  domainScript.Script
+	event   domainScript.Event
+}
+
+func (actions *scriptActions) Reply(ctx context.Context, text string) (err error) {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("text cannot be blank")
+	}
+	defer recoverSend(&err)
+
+	base := domainSend.BaseRequest{Phone: actions.event.ChatJID}
+	if actions.event.AccountID != domainEventSink.DefaultAccountID {
+		base.AccountID = actions.event.AccountID
+	}
+	_, err = actions.service.sendService.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: text})
+	return err
+}
+
+func (actions *scriptActions) React(ctx context.Context, emoji string) (err error) {
+	if actions.event.Type != domainScript.EventMessage {
+		return fmt.Errorf("%s events have no message to react to", actions.event.Type)
+	}
+	// Reactions are only available on the default device
+	if actions.event.AccountID != domainEventSink.DefaultAccountID {
+		return errors.New("reactions are only supported for events of the default device")
+	}
+	defer recoverSend(&err)
+
+	_, err = actions.service.messageService.ReactMessage(ctx, domainMessage.ReactionRequest{
+		MessageID: actions.event.MessageID,
+		Phone:     actions.event.ChatJID,
+		Emoji:     emoji,
+	})
+	return err
+}
+
+func (actions *scriptActions) Tag(_ context.Context, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || len(tag) > scriptMaxTagLength {
+		return fmt.Errorf("tag must be 1 to %d characters", scriptMaxTagLength)
+	}
+	return actions.service.repo.AddChatTag(&domainScript.ChatTag{
+		AccountID: actions.event.AccountID,
+		ChatJID:   actions.event.ChatJID,
+		Tag:       tag,
+		ScriptID:  actions.script.ID,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (actions *scriptActions) Untag(_ context.Context, tag string) error {
+	return actions.service.repo.RemoveChatTag(actions.event.AccountID, actions.event.ChatJID, strings.TrimSpace(tag))
+}
+
+func (actions *scriptActions) HTTP(ctx context.Context, request domainScript.HTTPRequest) (response domainScript.HTTPResponse, err error) {
+	if !scriptURLAllowed(request.URL, config.WhatsappScriptHTTPAllowlist) {
+		return response, fmt.Errorf("%s is not allow-listed, see --script-http-allowlist", request.URL)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, request.Method, request.URL, strings.NewReader(request.Body))
+	if err != nil {
+		return response, err
+	}
+	for key, value := range request.Headers {
+		httpRequest.Header.Set(key, value)
+	}
+	httpRequest.Header.Set("User-Agent", "go-whatsapp-web-multidevice/"+config.AppVersion)
+
+	httpResponse, err := actions.service.httpClient.Do(httpRequest)
+	if err != nil {
+		return response, err
+	}
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(httpResponse.Body, scriptHTTPMaxBody))
+	if err != nil {
+		return response, err
+	}
+	return domainScript.HTTPResponse{Status: httpResponse.StatusCode, Body: string(body)}, nil
+}
+
+func (actions *scriptActions) Log(message string) {
+	logrus.Debugf("Script %s: %s", actions.script.ID, message)
+}
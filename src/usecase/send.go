@@ -17,16 +17,15 @@ import (
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainPoll "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/poll"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/disintegration/imaging"
 	fiberUtils "github.com/gofiber/fiber/v2/utils"
 	"github.com/sirupsen/logrus"
-	"github.com/valyala/fasthttp"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
@@ -37,13 +36,19 @@ type serviceSend struct {
 	appService      app.IAppUsecase
 	chatStorageRepo domainChatStorage.IChatStorageRepository
 	accountManager  domainAccount.IAccountManager
+	// accountRepo tells a stored account that has no client yet from an unknown one
+	accountRepo domainAccount.IAccountRepository
+	// pollRepo keeps the secrets of sent polls so their votes can be decrypted
+	pollRepo domainPoll.IPollRepository
 }
 
-func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository, accountManager domainAccount.IAccountManager) domainSend.ISendUsecase {
+func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository, accountManager domainAccount.IAccountManager, accountRepo domainAccount.IAccountRepository, pollRepo domainPoll.IPollRepository) domainSend.ISendUsecase {
 	return &serviceSend{
 		appService:      appService,
 		chatStorageRepo: chatStorageRepo,
 		accountManager:  accountManager,
+		accountRepo:     accountRepo,
+		pollRepo:        pollRepo,
 	}
 }
 
@@ -57,23 +62,35 @@ func (service serviceSend) getClient(accountID string) (*whatsmeow.Client, error
 
 	client := service.accountManager.GetClient(accountID)
 	if client == nil {
-		return nil, fmt.Errorf("account not found or not logged in: %s", accountID)
+		// A stored account has no client until it is reconnected, like right after a restart
+		if service.accountRepo != nil {
+			if _, err := service.accountRepo.GetAccount(accountID); err == nil {
+				return nil, fmt.Errorf("account %s: %w", accountID, pkgError.ErrNotConnected)
+			}
+		}
+		return nil, pkgError.NotFoundError(fmt.Sprintf("account not found: %s", accountID))
 	}
 
 	if !client.IsLoggedIn() {
-		return nil, fmt.Errorf("account not logged in: %s", accountID)
+		return nil, fmt.Errorf("account %s: %w", accountID, pkgError.ErrNotLoggedIn)
 	}
 
 	return client, nil
 }
 
 // wrapSendMessage wraps the message sending process with message ID saving
-func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := client.SendMessage(ctx, recipient, msg)
+func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	ts, err := client.SendMessage(ctx, recipient, msg, extra...)
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
 
+	service.storeSentMessage(client, recipient, ts, msg, content)
+	return ts, nil
+}
+
+// storeSentMessage keeps a sent message in the chat storage without holding up the send
+func (service serviceSend) storeSentMessage(client *whatsmeow.Client, recipient types.JID, ts whatsmeow.SendResponse, msg *waE2E.Message, content string) {
 	// Store the sent message using chatstorage
 	senderJID := ""
 	if client.Store.ID != nil {
@@ -86,7 +103,7 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
+		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp, msg); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
@@ -94,8 +111,6 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 			}
 		}
 	}()
-
-	return ts, nil
 }
 
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
@@ -114,80 +129,18 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		return response, err
 	}
 
-	// Create base message
+	contextInfo, err := service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, request.Message)
+	if err != nil {
+		return response, err
+	}
+
 	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
 			Text:        proto.String(request.Message),
-			ContextInfo: &waE2E.ContextInfo{},
+			ContextInfo: contextInfo,
 		},
 	}
 
-	// Add forwarding context if IsForwarded is true
-	if request.BaseRequest.IsForwarded {
-		msg.ExtendedTextMessage.ContextInfo.IsForwarded = proto.Bool(true)
-		msg.ExtendedTextMessage.ContextInfo.ForwardingScore = proto.Uint32(100)
-	}
-
-	// Set disappearing message duration if provided
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
-	} else {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
-	}
-
-	parsedMentions := service.getMentionFromText(ctx, client, request.Message)
-	if len(parsedMentions) > 0 {
-		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
-	}
-
-	// Reply message
-	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
-		message, err := service.chatStorageRepo.GetMessageByID(*request.ReplyMessageID)
-		if err != nil {
-			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *request.ReplyMessageID, err)
-		} else if message != nil { // Only set reply context if we found the message
-			// Ensure we use a full JID (user@server) for the Participant field
-			// Use the sender JID from storage as-is. Modern storage should already provide
-			// fully-qualified JIDs (e.g., user@s.whatsapp.net or group@g.us). Avoid mutating
-			// the JID here to prevent corrupting valid group or special JIDs.
-			participantJID := message.Sender
-
-			// Build base ContextInfo with reply details
-			ctxInfo := &waE2E.ContextInfo{
-				StanzaID:    request.ReplyMessageID,
-				Participant: proto.String(participantJID),
-				QuotedMessage: &waE2E.Message{
-					Conversation: proto.String(message.Content),
-				},
-			}
-
-			// Preserve forwarding flag if set
-			if request.BaseRequest.IsForwarded {
-				ctxInfo.IsForwarded = proto.Bool(true)
-				ctxInfo.ForwardingScore = proto.Uint32(100)
-			}
-
-			// Preserve disappearing message duration if provided
-			if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-				ctxInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
-			} else {
-				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(participantJID))
-			}
-
-			// Preserve mentions
-			if len(parsedMentions) > 0 {
-				ctxInfo.MentionedJID = parsedMentions
-			}
-
-			msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
-				Text:        proto.String(request.Message),
-				ContextInfo: ctxInfo,
-			}
-		} else {
-			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *request.ReplyMessageID)
-		}
-	}
-
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, request.Message)
 	if err != nil {
 		return response, err
@@ -214,136 +167,21 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 		return response, err
 	}
 
-	var (
-		imagePath      string
-		imageThumbnail string
-		imageName      string
-		deletedItems   []string
-		oriImagePath   string
-	)
-
-	if request.ImageURL != nil && *request.ImageURL != "" {
-		// Download image from URL
-		imageData, fileName, err := utils.DownloadImageFromURL(*request.ImageURL)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
-		}
-
-		// Check if the downloaded image is WebP and convert to PNG if needed
-		mimeType := http.DetectContentType(imageData)
-		if mimeType == "image/webp" {
-			// Convert WebP to PNG
-			webpImage, err := imaging.Decode(bytes.NewReader(imageData))
-			if err != nil {
-				return response, pkgError.InternalServerError(fmt.Sprintf("failed to decode WebP image %v", err))
-			}
-
-			// Change file extension to PNG
-			if strings.HasSuffix(strings.ToLower(fileName), ".webp") {
-				fileName = fileName[:len(fileName)-5] + ".png"
-			} else {
-				fileName = fileName + ".png"
-			}
-
-			// Convert to PNG format
-			var pngBuffer bytes.Buffer
-			err = imaging.Encode(&pngBuffer, webpImage, imaging.PNG)
-			if err != nil {
-				return response, pkgError.InternalServerError(fmt.Sprintf("failed to convert WebP to PNG %v", err))
-			}
-			imageData = pngBuffer.Bytes()
-		}
-
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, fileName)
-		imageName = fileName
-		err = os.WriteFile(oriImagePath, imageData, 0644)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save downloaded image %v", err))
-		}
-	} else if request.Image != nil {
-		// Save image to server
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, request.Image.Filename)
-		err = fasthttp.SaveMultipartFile(request.Image, oriImagePath)
-		if err != nil {
-			return response, err
-		}
-		imageName = request.Image.Filename
-	}
-	deletedItems = append(deletedItems, oriImagePath)
-
-	/* Generate thumbnail with smalled image size */
-	srcImage, err := imaging.Open(oriImagePath)
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to open image file '%s' for thumbnail generation: %v. Possible causes: file not found, unsupported format, or permission denied.", oriImagePath, err))
-	}
-
-	// Resize Thumbnail
-	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	imageThumbnail = fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, imageName)
-	if err = imaging.Save(resizedImage, imageThumbnail); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
-	}
-	deletedItems = append(deletedItems, imageThumbnail)
-
-	if request.Compress {
-		// Resize image
-		openImageBuffer, err := imaging.Open(oriImagePath)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("Failed to open image file '%s' for compression: %v. Possible causes: file not found, unsupported format, or permission denied.", oriImagePath, err))
-		}
-		newImage := imaging.Resize(openImageBuffer, 600, 0, imaging.Lanczos)
-		newImagePath := fmt.Sprintf("%s/new-%s", config.PathSendItems, imageName)
-		if err = imaging.Save(newImage, newImagePath); err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
-		}
-		deletedItems = append(deletedItems, newImagePath)
-		imagePath = newImagePath
-	} else {
-		imagePath = oriImagePath
-	}
-
-	// Send to WA server
-	dataWaCaption := request.Caption
-	dataWaImage, err := os.ReadFile(imagePath)
+	media, err := service.resolveMedia(ctx, client, domainSend.MediaImage, request.MediaSource())
 	if err != nil {
 		return response, err
 	}
-	uploadedImage, err := service.uploadMedia(ctx, client, whatsmeow.MediaImage, dataWaImage, dataWaRecipient)
+	imageMessage, err := service.uploadImage(ctx, client, dataWaRecipient, media, request.Compress)
 	if err != nil {
-		fmt.Printf("failed to upload file: %v", err)
 		return response, err
 	}
-	dataWaThumbnail, err := os.ReadFile(imageThumbnail)
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to read thumbnail %v", err))
-	}
-
-	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
-		JPEGThumbnail: dataWaThumbnail,
-		Caption:       proto.String(dataWaCaption),
-		URL:           proto.String(uploadedImage.URL),
-		DirectPath:    proto.String(uploadedImage.DirectPath),
-		MediaKey:      uploadedImage.MediaKey,
-		Mimetype:      proto.String(http.DetectContentType(dataWaImage)),
-		FileEncSHA256: uploadedImage.FileEncSHA256,
-		FileSHA256:    uploadedImage.FileSHA256,
-		FileLength:    proto.Uint64(uint64(len(dataWaImage))),
-		ViewOnce:      proto.Bool(request.ViewOnce),
-	}}
-
-	if request.BaseRequest.IsForwarded {
-		msg.ImageMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
+	imageMessage.Caption = proto.String(request.Caption)
+	imageMessage.ViewOnce = proto.Bool(request.ViewOnce)
+	msg := &waE2E.Message{ImageMessage: imageMessage}
 
-	// Set duration expiration
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.ImageMessage.ContextInfo == nil {
-			msg.ImageMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.ImageMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.ImageMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, request.Caption)
+	if err != nil {
+		return response, err
 	}
 
 	caption := "🖼️ Image"
@@ -351,12 +189,6 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 		caption = "🖼️ " + request.Caption
 	}
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, caption)
-	go func() {
-		errDelete := utils.RemoveFile(0, deletedItems...)
-		if errDelete != nil {
-			fmt.Println("error when deleting picture: ", errDelete)
-		}
-	}()
 	if err != nil {
 		return response, err
 	}
@@ -382,8 +214,11 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		return response, err
 	}
 
-	fileBytes := helpers.MultipartFormFileHeaderToBytes(request.File)
-	fileMimeType := resolveDocumentMIME(request.File.Filename, fileBytes)
+	media, err := service.resolveMedia(ctx, client, domainSend.MediaDocument, request.MediaSource())
+	if err != nil {
+		return response, err
+	}
+	fileBytes, fileMimeType := media.Data, media.MimeType
 
 	// Send to WA server
 	uploadedFile, err := service.uploadMedia(ctx, client, whatsmeow.MediaDocument, fileBytes, dataWaRecipient)
@@ -395,28 +230,19 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	msg := &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
 		URL:           proto.String(uploadedFile.URL),
 		Mimetype:      proto.String(fileMimeType),
-		Title:         proto.String(request.File.Filename),
+		Title:         proto.String(media.FileName),
 		FileSHA256:    uploadedFile.FileSHA256,
 		FileLength:    proto.Uint64(uploadedFile.FileLength),
 		MediaKey:      uploadedFile.MediaKey,
-		FileName:      proto.String(request.File.Filename),
+		FileName:      proto.String(media.FileName),
 		FileEncSHA256: uploadedFile.FileEncSHA256,
 		DirectPath:    proto.String(uploadedFile.DirectPath),
 		Caption:       proto.String(request.Caption),
 	}}
 
-	if request.BaseRequest.IsForwarded {
-		msg.DocumentMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.DocumentMessage.ContextInfo == nil {
-			msg.DocumentMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.DocumentMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.DocumentMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, request.Caption)
+	if err != nil {
+		return response, err
 	}
 
 	caption := "📄 Document"
@@ -464,155 +290,21 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		return response, err
 	}
 
-	var (
-		videoPath      string
-		videoThumbnail string
-		deletedItems   []string
-	)
-
-	// Ensure temporary files are always removed, even on early returns
-	defer func() {
-		if len(deletedItems) > 0 {
-			// Run cleanup in background with slight delay to avoid race with open handles
-			go utils.RemoveFile(1, deletedItems...)
-		}
-	}()
-
-	generateUUID := fiberUtils.UUIDv4()
-
-	var oriVideoPath string
-
-	// Determine source of video (URL or uploaded file)
-	if request.VideoURL != nil && *request.VideoURL != "" {
-		// Download video bytes
-		videoBytes, fileName, errDownload := utils.DownloadVideoFromURL(*request.VideoURL)
-		if errDownload != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download video from URL %v", errDownload))
-		}
-		// Build file path to save the downloaded video temporarily
-		oriVideoPath = fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+fileName)
-		if errWrite := os.WriteFile(oriVideoPath, videoBytes, 0644); errWrite != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store downloaded video in server %v", errWrite))
-		}
-	} else if request.Video != nil {
-		// Save uploaded video to server
-		oriVideoPath = fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+request.Video.Filename)
-		err = fasthttp.SaveMultipartFile(request.Video, oriVideoPath)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to store video in server %v", err))
-		}
-	} else {
-		// This should not happen due to validation, but guard anyway
-		return response, pkgError.ValidationError("either Video or VideoURL must be provided")
-	}
-
-	// Check if ffmpeg is installed
-	_, err = exec.LookPath("ffmpeg")
-	if err != nil {
-		return response, pkgError.InternalServerError("ffmpeg not installed")
-	}
-
-	// Generate thumbnail using ffmpeg
-	thumbnailVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".png")
-	cmdThumbnail := exec.Command("ffmpeg", "-i", oriVideoPath, "-ss", "00:00:01.000", "-vframes", "1", thumbnailVideoPath)
-	err = cmdThumbnail.Run()
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create thumbnail %v", err))
-	}
-
-	// Resize Thumbnail
-	srcImage, err := imaging.Open(thumbnailVideoPath)
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to open generated video thumbnail image '%s': %v. Possible causes: file not found, unsupported format, or permission denied.", thumbnailVideoPath, err))
-	}
-	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".png")
-	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
-	}
-
-	deletedItems = append(deletedItems, thumbnailVideoPath)
-	deletedItems = append(deletedItems, thumbnailResizeVideoPath)
-	videoThumbnail = thumbnailResizeVideoPath
-
-	// Compress if requested
-	if request.Compress {
-		compresVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".mp4")
-
-		// Use proper compression settings to reduce file size
-		// -crf 28: Constant Rate Factor (18-28 is good range, higher = smaller file)
-		// -preset medium: Balance between encoding speed and compression efficiency
-		// -c:v libx264: Use H.264 codec for video
-		// -c:a aac: Use AAC codec for audio
-		// -movflags +faststart: Optimize for web streaming
-		// -vf scale=720:-2: Scale video to max width 720px, maintain aspect ratio
-		cmdCompress := exec.Command("ffmpeg", "-i", oriVideoPath,
-			"-c:v", "libx264",
-			"-crf", "28",
-			"-preset", "fast",
-			"-vf", "scale=720:-2",
-			"-c:a", "aac",
-			"-b:a", "128k",
-			"-movflags", "+faststart",
-			"-y", // Overwrite output file if it exists
-			compresVideoPath)
-
-		// Capture both stdout and stderr for better error reporting
-		output, err := cmdCompress.CombinedOutput()
-		if err != nil {
-			logrus.Errorf("ffmpeg compression failed: %v, output: %s", err, string(output))
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to compress video: %v", err))
-		}
-
-		videoPath = compresVideoPath
-		deletedItems = append(deletedItems, compresVideoPath)
-	} else {
-		videoPath = oriVideoPath
-	}
-	deletedItems = append(deletedItems, oriVideoPath)
-
-	//Send to WA server
-	dataWaVideo, err := os.ReadFile(videoPath)
+	media, err := service.resolveMedia(ctx, client, domainSend.MediaVideo, request.MediaSource())
 	if err != nil {
 		return response, err
 	}
-	uploaded, err := service.uploadMedia(ctx, client, whatsmeow.MediaVideo, dataWaVideo, dataWaRecipient)
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to upload file: %v", err))
-	}
-	dataWaThumbnail, err := os.ReadFile(videoThumbnail)
+	videoMessage, err := service.uploadVideo(ctx, client, dataWaRecipient, media, request.Compress)
 	if err != nil {
 		return response, err
 	}
+	videoMessage.Caption = proto.String(request.Caption)
+	videoMessage.ViewOnce = proto.Bool(request.ViewOnce)
+	msg := &waE2E.Message{VideoMessage: videoMessage}
 
-	msg := &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
-		URL:                 proto.String(uploaded.URL),
-		Mimetype:            proto.String(http.DetectContentType(dataWaVideo)),
-		Caption:             proto.String(request.Caption),
-		FileLength:          proto.Uint64(uploaded.FileLength),
-		FileSHA256:          uploaded.FileSHA256,
-		FileEncSHA256:       uploaded.FileEncSHA256,
-		MediaKey:            uploaded.MediaKey,
-		DirectPath:          proto.String(uploaded.DirectPath),
-		ViewOnce:            proto.Bool(request.ViewOnce),
-		JPEGThumbnail:       dataWaThumbnail,
-		ThumbnailEncSHA256:  dataWaThumbnail,
-		ThumbnailSHA256:     dataWaThumbnail,
-		ThumbnailDirectPath: proto.String(uploaded.DirectPath),
-	}}
-
-	if request.BaseRequest.IsForwarded {
-		msg.VideoMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.VideoMessage.ContextInfo == nil {
-			msg.VideoMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.VideoMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.VideoMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, request.Caption)
+	if err != nil {
+		return response, err
 	}
 
 	caption := "🎥 Video"
@@ -652,18 +344,9 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 		Vcard:       proto.String(msgVCard),
 	}}
 
-	if request.BaseRequest.IsForwarded {
-		msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.ContactMessage.ContextInfo == nil {
-			msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.ContactMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.ContactMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
 	}
 
 	content := "👤 " + request.ContactName
@@ -715,18 +398,9 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 		JPEGThumbnail: metadata.ImageThumb,
 	}}
 
-	if request.BaseRequest.IsForwarded {
-		msg.ExtendedTextMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.ExtendedTextMessage.ContextInfo == nil {
-			msg.ExtendedTextMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.ExtendedTextMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, request.Caption)
+	if err != nil {
+		return response, err
 	}
 
 	// If we have a thumbnail image, upload it to WhatsApp's servers
@@ -783,18 +457,9 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 		},
 	}
 
-	if request.BaseRequest.IsForwarded {
-		msg.LocationMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.LocationMessage.ContextInfo == nil {
-			msg.LocationMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.LocationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.LocationMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
 	}
 
 	content := "📍 " + request.Latitude + ", " + request.Longitude
@@ -828,22 +493,11 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		return response, err
 	}
 
-	var (
-		audioBytes    []byte
-		audioMimeType string
-	)
-
-	// Handle audio from URL or file
-	if request.AudioURL != nil && *request.AudioURL != "" {
-		audioBytes, _, err = utils.DownloadAudioFromURL(*request.AudioURL)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download audio from URL %v", err))
-		}
-		audioMimeType = http.DetectContentType(audioBytes)
-	} else if request.Audio != nil {
-		audioBytes = helpers.MultipartFormFileHeaderToBytes(request.Audio)
-		audioMimeType = http.DetectContentType(audioBytes)
+	media, err := service.resolveMedia(ctx, client, domainSend.MediaAudio, request.MediaSource())
+	if err != nil {
+		return response, err
 	}
+	audioBytes, audioMimeType := media.Data, media.MimeType
 
 	// upload to WhatsApp servers
 	audioUploaded, err := service.uploadMedia(ctx, client, whatsmeow.MediaAudio, audioBytes, dataWaRecipient)
@@ -864,18 +518,9 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		},
 	}
 
-	if request.BaseRequest.IsForwarded {
-		msg.AudioMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.AudioMessage.ContextInfo == nil {
-			msg.AudioMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.AudioMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.AudioMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
 	}
 
 	content := "🎵 Audio"
@@ -910,11 +555,9 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 
 	msg := client.BuildPollCreation(request.Question, request.Options, request.MaxAnswer)
 
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.PollCreationMessage.ContextInfo == nil {
-			msg.PollCreationMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.PollCreationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.PollCreationMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
 	}
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
@@ -922,6 +565,23 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		return response, err
 	}
 
+	// The poll is sent already, without its secret only the results are lost
+	if service.pollRepo != nil {
+		err := service.pollRepo.CreatePoll(&domainPoll.Poll{
+			MessageID: ts.ID,
+			AccountID: request.AccountID,
+			ChatJID:   dataWaRecipient.String(),
+			Question:  request.Question,
+			Options:   request.Options,
+			MaxAnswer: request.MaxAnswer,
+			Secret:    msg.GetMessageContextInfo().GetMessageSecret(),
+			CreatedAt: ts.Timestamp,
+		})
+		if err != nil {
+			logrus.Warnf("Failed to store poll %s, its votes won't be counted: %v", ts.ID, err)
+		}
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Send poll success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
@@ -1043,42 +703,23 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 		}
 	}()
 
-	// Handle sticker from URL or file
-	if request.StickerURL != nil && *request.StickerURL != "" {
-		// Download sticker from URL
-		imageData, _, err := utils.DownloadImageFromURL(*request.StickerURL)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download sticker from URL: %v", err))
-		}
-
-		// Create safe temporary file within base dir
-		f, err := os.CreateTemp(absBaseDir, "sticker_*")
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to create temp file: %v", err))
-		}
-		stickerPath = f.Name()
-		if _, err := f.Write(imageData); err != nil {
-			f.Close()
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to write sticker: %v", err))
-		}
-		_ = f.Close()
-		deletedItems = append(deletedItems, stickerPath)
-	} else if request.Sticker != nil {
-		// Create safe temporary file within base dir
-		f, err := os.CreateTemp(absBaseDir, "sticker_*")
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to create temp file: %v", err))
-		}
-		stickerPath = f.Name()
-		_ = f.Close()
+	media, err := service.resolveMedia(ctx, client, domainSend.MediaSticker, request.MediaSource())
+	if err != nil {
+		return response, err
+	}
 
-		// Save uploaded file to safe path
-		err = fasthttp.SaveMultipartFile(request.Sticker, stickerPath)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save sticker: %v", err))
-		}
-		deletedItems = append(deletedItems, stickerPath)
+	// Create safe temporary file within base dir
+	f, err := os.CreateTemp(absBaseDir, "sticker_*")
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create temp file: %v", err))
+	}
+	stickerPath = f.Name()
+	deletedItems = append(deletedItems, stickerPath)
+	if _, err := f.Write(media.Data); err != nil {
+		f.Close()
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to write sticker: %v", err))
 	}
+	_ = f.Close()
 
 	// Convert image to WebP format for sticker (512x512 max size)
 	srcImage, err := imaging.Open(stickerPath)
@@ -1166,18 +807,9 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 		},
 	}
 
-	if request.BaseRequest.IsForwarded {
-		msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-		if msg.StickerMessage.ContextInfo == nil {
-			msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{}
-		}
-		msg.StickerMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	msg.StickerMessage.ContextInfo, err = service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
 	}
 
 	content := "🎨 Sticker"
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendAlbum uploads the items concurrently, sends them in order and then the album message counting the sent items. Items that fail are
+// reported in the response and left out of the album, the request only fails when no item could be sent.
+func (service serviceSend) SendAlbum(ctx context.Context, request domainSend.AlbumRequest) (response domainSend.AlbumResponse, err error) {
+	client, err := service.getClient(request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	if err = validations.ValidateSendAlbum(ctx, request); err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	messages := make([]*waE2E.Message, len(request.Items))
+	errs := make([]error, len(request.Items))
+	var wg sync.WaitGroup
+	for i, item := range request.Items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			messages[i], errs[i] = service.uploadAlbumItem(ctx, client, dataWaRecipient, item, request.Compress)
+		}()
+	}
+	wg.Wait()
+
+	// The items go out before the album message so it only announces the items that were actually sent, an
+	// album that expects more items than it has stays a loading placeholder on the clients
+	albumID := client.GenerateMessageID()
+	parentKey := &waCommon.MessageKey{
+		RemoteJID: proto.String(dataWaRecipient.String()),
+		FromMe:    proto.Bool(true),
+		ID:        proto.String(albumID),
+	}
+
+	var imageCount, videoCount uint32
+	var firstErr error
+	for i, item := range request.Items {
+		result := domainSend.AlbumItemResult{Index: i}
+		if errs[i] == nil {
+			result.MessageID, errs[i] = service.sendAlbumItem(ctx, client, dataWaRecipient, request.BaseRequest, imageCount+videoCount == 0, item, messages[i], parentKey)
+		}
+		switch {
+		case errs[i] != nil:
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			result.Error = errs[i].Error()
+		case messages[i].ImageMessage != nil:
+			imageCount++
+		default:
+			videoCount++
+		}
+		response.Items = append(response.Items, result)
+	}
+	sent := imageCount + videoCount
+	if sent == 0 {
+		return response, firstErr
+	}
+
+	_, err = client.SendMessage(ctx, dataWaRecipient, &waE2E.Message{AlbumMessage: &waE2E.AlbumMessage{
+		ExpectedImageCount: proto.Uint32(imageCount),
+		ExpectedVideoCount: proto.Uint32(videoCount),
+	}}, whatsmeow.SendRequestExtra{ID: albumID})
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("%d album items were sent but not the album: %v", sent, err))
+	}
+
+	response.MessageID = albumID
+	response.Status = fmt.Sprintf("Album with %d of %d items sent to %s", sent, len(request.Items), request.Phone)
+	return response, nil
+}
+
+// uploadAlbumItem resolves and uploads one item of an album with the same pipeline as /send/image and /send/video
+func (service serviceSend) uploadAlbumItem(ctx context.Context, client *whatsmeow.Client, recipient types.JID, item domainSend.AlbumItem, compress bool) (*waE2E.Message, error) {
+	kind := domainSend.MediaKind(item.Type)
+	media, err := service.resolveMedia(ctx, client, kind, item.MediaSource())
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == domainSend.MediaVideo {
+		videoMessage, err := service.uploadVideo(ctx, client, recipient, media, compress)
+		if err != nil {
+			return nil, err
+		}
+		videoMessage.Caption = proto.String(item.Caption)
+		return &waE2E.Message{VideoMessage: videoMessage}, nil
+	}
+
+	imageMessage, err := service.uploadImage(ctx, client, recipient, media, compress)
+	if err != nil {
+		return nil, err
+	}
+	imageMessage.Caption = proto.String(item.Caption)
+	return &waE2E.Message{ImageMessage: imageMessage}, nil
+}
+
+// sendAlbumItem sends an uploaded item as part of the album of parentKey. The reply and mentions of the request are
+// put on the first item that is sent only, so the album quotes and notifies once even when earlier items failed.
+func (service serviceSend) sendAlbumItem(ctx context.Context, client *whatsmeow.Client, recipient types.JID, base domainSend.BaseRequest, withContext bool, item domainSend.AlbumItem, msg *waE2E.Message, parentKey *waCommon.MessageKey) (string, error) {
+	if !withContext {
+		base.ReplyMessageID = nil
+		base.Mentions = nil
+		base.MentionEveryone = false
+	}
+	contextInfo, err := service.contextInfo(ctx, client, recipient, base, item.Caption)
+	if err != nil {
+		return "", err
+	}
+
+	label := "🖼️ "
+	if msg.VideoMessage != nil {
+		label = "🎥 "
+		msg.VideoMessage.ContextInfo = contextInfo
+	} else {
+		msg.ImageMessage.ContextInfo = contextInfo
+	}
+	msg.MessageContextInfo = &waE2E.MessageContextInfo{MessageAssociation: &waE2E.MessageAssociation{
+		AssociationType:  waE2E.MessageAssociation_MEDIA_ALBUM.Enum(),
+		ParentMessageKey: parentKey,
+	}}
+
+	ts, err := service.wrapSendMessage(ctx, client, recipient, msg, label+item.Caption)
+	if err != nil {
+		return "", err
+	}
+	return ts.ID, nil
+}
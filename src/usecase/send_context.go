@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"regexp"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// everyonePattern finds @everyone in a text or caption
+var everyonePattern = regexp.MustCompile(`(?i)(^|\s)@everyone\b`)
+
+// contextInfo builds the context every send shares: the quoted reply, the mentions, the forwarded flag and the
+// disappearing timer. text is the text or caption of the message that @mentions are read from.
+func (service serviceSend) contextInfo(ctx context.Context, client *whatsmeow.Client, recipient types.JID, base domainSend.BaseRequest, text string) (*waE2E.ContextInfo, error) {
+	contextInfo := &waE2E.ContextInfo{}
+
+	if base.IsForwarded {
+		contextInfo.IsForwarded = proto.Bool(true)
+		contextInfo.ForwardingScore = proto.Uint32(100)
+	}
+
+	if base.Duration != nil && *base.Duration > 0 {
+		contextInfo.Expiration = proto.Uint32(uint32(*base.Duration))
+	} else {
+		contextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(recipient.String()))
+	}
+
+	mentions, err := service.mentions(ctx, client, recipient, base, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(mentions) > 0 {
+		contextInfo.MentionedJID = mentions
+	}
+
+	if base.ReplyMessageID != nil && *base.ReplyMessageID != "" {
+		message, err := service.chatStorageRepo.GetMessageByID(*base.ReplyMessageID)
+		switch {
+		case err != nil:
+			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *base.ReplyMessageID, err)
+		case message == nil:
+			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *base.ReplyMessageID)
+		default:
+			// Use the sender JID from storage as-is, it is already fully qualified (user@server)
+			contextInfo.StanzaID = proto.String(message.ID)
+			contextInfo.Participant = proto.String(message.Sender)
+			contextInfo.QuotedMessage = storedMessage(message)
+		}
+	}
+
+	return contextInfo, nil
+}
+
+// mentions collects the JIDs a message mentions: the @numbers in its text, the mentions of the request and, with
+// mention_everyone or @everyone in the text, every participant of the group. Numbers that cannot be resolved are
+// left out.
+func (service serviceSend) mentions(ctx context.Context, client *whatsmeow.Client, recipient types.JID, base domainSend.BaseRequest, text string) ([]string, error) {
+	result := service.getMentionFromText(ctx, client, text)
+	for _, mention := range base.Mentions {
+		if jid, err := utils.ValidateJidWithLogin(client, mention); err == nil {
+			result = append(result, jid.String())
+		}
+	}
+
+	everyone := base.MentionEveryone
+	if base.MentionEveryone && recipient.Server != types.GroupServer {
+		return nil, pkgError.ValidationError("mention_everyone: only messages to a group can mention everyone")
+	}
+	if !everyone && recipient.Server == types.GroupServer {
+		everyone = everyonePattern.MatchString(text)
+	}
+	if everyone {
+		group, err := client.GetGroupInfo(ctx, recipient)
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get participants of %s: %v", recipient, err))
+		}
+		for _, participant := range group.Participants {
+			result = append(result, participant.JID.String())
+		}
+	}
+
+	seen := make(map[string]bool, len(result))
+	unique := result[:0]
+	for _, jid := range result {
+		if !seen[jid] {
+			seen[jid] = true
+			unique = append(unique, jid)
+		}
+	}
+	return unique, nil
+}
+
+// storedMessage rebuilds a message of the chat storage. Media keeps its stored URL, direct path, media key and
+// hashes, so the message can be quoted, downloaded or sent again without the original.
+func storedMessage(message *domainChatStorage.Message) *waE2E.Message {
+	var caption, mimeType *string
+	if message.Content != "" {
+		caption = proto.String(message.Content)
+	}
+	if message.Mimetype != "" {
+		mimeType = proto.String(message.Mimetype)
+	} else if extensionMIME := mime.TypeByExtension(filepath.Ext(message.Filename)); extensionMIME != "" {
+		mimeType = proto.String(extensionMIME)
+	}
+	var directPath *string
+	if message.DirectPath != "" {
+		directPath = proto.String(message.DirectPath)
+	}
+
+	switch message.MediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			Caption:       caption,
+			Mimetype:      mimeType,
+			URL:           proto.String(message.URL),
+			DirectPath:    directPath,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "video":
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:       caption,
+			Mimetype:      mimeType,
+			URL:           proto.String(message.URL),
+			DirectPath:    directPath,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			Mimetype:      mimeType,
+			URL:           proto.String(message.URL),
+			DirectPath:    directPath,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "document":
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			Caption:       caption,
+			Mimetype:      mimeType,
+			Title:         proto.String(message.Filename),
+			FileName:      proto.String(message.Filename),
+			URL:           proto.String(message.URL),
+			DirectPath:    directPath,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+			Mimetype:      proto.String("image/webp"),
+			URL:           proto.String(message.URL),
+			DirectPath:    directPath,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	default:
+		return &waE2E.Message{Conversation: proto.String(message.Content)}
+	}
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// forwardMediaTypes maps stored media to the WhatsApp upload type, stickers are uploaded as images
+var forwardMediaTypes = map[string]whatsmeow.MediaType{
+	"image":    whatsmeow.MediaImage,
+	"video":    whatsmeow.MediaVideo,
+	"audio":    whatsmeow.MediaAudio,
+	"document": whatsmeow.MediaDocument,
+	"sticker":  whatsmeow.MediaImage,
+}
+
+func (service serviceSend) ForwardMessage(ctx context.Context, request domainSend.ForwardRequest) (response domainSend.ForwardResponse, err error) {
+	client, err := service.getClient(request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	if err = validations.ValidateForwardMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	stored, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to load message %s: %v", request.MessageID, err))
+	}
+	if stored == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message %s not found", request.MessageID))
+	}
+	if stored.MediaType != "" && (stored.URL == "" || len(stored.MediaKey) == 0) {
+		return response, pkgError.ValidationError(fmt.Sprintf("message %s has %s media without a stored media key, it cannot be forwarded", stored.ID, stored.MediaType))
+	}
+
+	// The media is downloaded at most once, when a newsletter needs it uploaded again
+	var mediaData []byte
+	var firstErr error
+	forwarded := 0
+	for _, phone := range request.Phones {
+		result := domainSend.ForwardResult{Phone: phone}
+		messageID, err := service.forwardTo(ctx, client, stored, phone, request.Duration, &mediaData)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Error = err.Error()
+		} else {
+			result.MessageID = messageID
+			forwarded++
+		}
+		response.Results = append(response.Results, result)
+	}
+	if forwarded == 0 {
+		return response, firstErr
+	}
+
+	response.MessageID = stored.ID
+	response.Status = fmt.Sprintf("Message forwarded to %d of %d chats", forwarded, len(request.Phones))
+	return response, nil
+}
+
+// forwardTo sends a stored message to one chat. mediaData caches the downloaded media across the chats of a request.
+func (service serviceSend) forwardTo(ctx context.Context, client *whatsmeow.Client, stored *domainChatStorage.Message, phone string, duration *int, mediaData *[]byte) (string, error) {
+	recipient, err := utils.ValidateJidWithLogin(client, phone)
+	if err != nil {
+		return "", err
+	}
+
+	// The mentions of the original are not carried over, forwarded messages don't notify anyone again
+	contextInfo, err := service.contextInfo(ctx, client, recipient, domainSend.BaseRequest{Phone: phone, Duration: duration}, "")
+	if err != nil {
+		return "", err
+	}
+	contextInfo.IsForwarded = proto.Bool(true)
+	contextInfo.ForwardingScore = proto.Uint32(stored.ForwardingScore + 1)
+
+	msg := forwardedMessage(stored, contextInfo)
+
+	// Newsletters take unencrypted media, the stored encrypted media can't be reused there
+	if stored.MediaType != "" && recipient.Server == types.NewsletterServer {
+		if *mediaData == nil {
+			downloadable, err := storedDownloadable(stored)
+			if err != nil {
+				return "", pkgError.ValidationError(err.Error())
+			}
+			if *mediaData, err = client.Download(ctx, downloadable); err != nil {
+				return "", pkgError.InternalServerError(fmt.Sprintf("failed to download media of message %s: %v", stored.ID, err))
+			}
+		}
+		uploaded, err := service.uploadMedia(ctx, client, forwardMediaTypes[stored.MediaType], *mediaData, recipient)
+		if err != nil {
+			return "", pkgError.InternalServerError(fmt.Sprintf("failed to upload %s: %v", stored.MediaType, err))
+		}
+		setUploadedMedia(msg, uploaded)
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, recipient, msg, stored.Content)
+	if err != nil {
+		return "", err
+	}
+	return ts.ID, nil
+}
+
+// forwardedMessage rebuilds a stored message with the given context. Text is sent as an extended text message,
+// a plain conversation can't carry the forwarded flag.
+func forwardedMessage(stored *domainChatStorage.Message, contextInfo *waE2E.ContextInfo) *waE2E.Message {
+	msg := storedMessage(stored)
+	switch {
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = contextInfo
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = contextInfo
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = contextInfo
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = contextInfo
+	case msg.StickerMessage != nil:
+		msg.StickerMessage.ContextInfo = contextInfo
+	default:
+		msg = &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        msg.Conversation,
+			ContextInfo: contextInfo,
+		}}
+	}
+	return msg
+}
+
+// setUploadedMedia points the media of a message to a new upload
+func setUploadedMedia(msg *waE2E.Message, uploaded whatsmeow.UploadResponse) {
+	url, directPath, length := proto.String(uploaded.URL), proto.String(uploaded.DirectPath), proto.Uint64(uploaded.FileLength)
+	switch {
+	case msg.ImageMessage != nil:
+		media := msg.ImageMessage
+		media.URL, media.DirectPath, media.MediaKey, media.FileSHA256, media.FileEncSHA256, media.FileLength =
+			url, directPath, uploaded.MediaKey, uploaded.FileSHA256, uploaded.FileEncSHA256, length
+	case msg.VideoMessage != nil:
+		media := msg.VideoMessage
+		media.URL, media.DirectPath, media.MediaKey, media.FileSHA256, media.FileEncSHA256, media.FileLength =
+			url, directPath, uploaded.MediaKey, uploaded.FileSHA256, uploaded.FileEncSHA256, length
+	case msg.AudioMessage != nil:
+		media := msg.AudioMessage
+		media.URL, media.DirectPath, media.MediaKey, media.FileSHA256, media.FileEncSHA256, media.FileLength =
+			url, directPath, uploaded.MediaKey, uploaded.FileSHA256, uploaded.FileEncSHA256, length
+	case msg.DocumentMessage != nil:
+		media := msg.DocumentMessage
+		media.URL, media.DirectPath, media.MediaKey, media.FileSHA256, media.FileEncSHA256, media.FileLength =
+			url, directPath, uploaded.MediaKey, uploaded.FileSHA256, uploaded.FileEncSHA256, length
+	case msg.StickerMessage != nil:
+		media := msg.StickerMessage
+		media.URL, media.DirectPath, media.MediaKey, media.FileSHA256, media.FileEncSHA256, media.FileLength =
+			url, directPath, uploaded.MediaKey, uploaded.FileSHA256, uploaded.FileEncSHA256, length
+	}
+}
@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/disintegration/imaging"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// resolveMedia loads the media of a send from whichever source the request names: an upload, a URL, a base64 data
+// URI or a stored message. The result passes the same size and MIME checks whatever the source was.
+func (service serviceSend) resolveMedia(ctx context.Context, client *whatsmeow.Client, kind domainSend.MediaKind, source domainSend.MediaSource) (media domainSend.Media, err error) {
+	var declaredMIME string
+
+	switch {
+	case source.File != nil:
+		media.Data = helpers.MultipartFormFileHeaderToBytes(source.File)
+		media.FileName = source.File.Filename
+		declaredMIME = source.File.Header.Get("Content-Type")
+	case source.URL != "":
+		media.Data, media.FileName, err = downloadMediaURL(kind, source.URL)
+		if err != nil {
+			return media, pkgError.InternalServerError(fmt.Sprintf("failed to download %s from URL %v", kind, err))
+		}
+	case source.Base64 != "":
+		declaredMIME, media.Data, err = domainSend.ParseDataURI(source.Base64)
+		if err != nil {
+			return media, pkgError.ValidationError("base64: " + err.Error())
+		}
+	case source.FromMessageID != "":
+		media.Data, media.FileName, err = service.downloadStoredMedia(ctx, client, kind, source.FromMessageID)
+		if err != nil {
+			return media, err
+		}
+	default:
+		return media, pkgError.ValidationError(fmt.Sprintf("%s: no media source provided", kind))
+	}
+
+	media.MimeType = mediaMIME(kind, declaredMIME, media.FileName, media.Data)
+	if media.FileName != "" {
+		media.FileName = filepath.Base(media.FileName)
+	} else {
+		media.FileName = mediaFileName(kind, media.MimeType)
+	}
+
+	if err = validations.ValidateMedia(kind, media); err != nil {
+		return media, err
+	}
+	return media, nil
+}
+
+// downloadMediaURL downloads media with the downloader of its kind, each checks the content type and size on its own
+func downloadMediaURL(kind domainSend.MediaKind, url string) ([]byte, string, error) {
+	switch kind {
+	case domainSend.MediaImage, domainSend.MediaSticker:
+		return utils.DownloadImageFromURL(url)
+	case domainSend.MediaVideo:
+		return utils.DownloadVideoFromURL(url)
+	case domainSend.MediaAudio:
+		return utils.DownloadAudioFromURL(url)
+	default:
+		return utils.DownloadFileFromURL(url)
+	}
+}
+
+// downloadStoredMedia downloads the media of a message in chat storage again with its stored media key
+func (service serviceSend) downloadStoredMedia(ctx context.Context, client *whatsmeow.Client, kind domainSend.MediaKind, messageID string) ([]byte, string, error) {
+	message, err := service.chatStorageRepo.GetMessageByID(messageID)
+	if err != nil {
+		return nil, "", pkgError.InternalServerError(fmt.Sprintf("failed to load message %s: %v", messageID, err))
+	}
+	if message == nil {
+		return nil, "", pkgError.ValidationError(fmt.Sprintf("from_message_id: message %s not found", messageID))
+	}
+	if message.MediaType == "" || message.URL == "" || len(message.MediaKey) == 0 {
+		return nil, "", pkgError.ValidationError(fmt.Sprintf("from_message_id: message %s does not contain downloadable media", messageID))
+	}
+	if !storedMediaFits(kind, message.MediaType) {
+		return nil, "", pkgError.ValidationError(fmt.Sprintf("from_message_id: message %s has %s media, it cannot be sent as %s", messageID, message.MediaType, kind))
+	}
+
+	downloadable, err := storedDownloadable(message)
+	if err != nil {
+		return nil, "", pkgError.ValidationError("from_message_id: " + err.Error())
+	}
+	data, err := client.Download(ctx, downloadable)
+	if err != nil {
+		return nil, "", pkgError.InternalServerError(fmt.Sprintf("failed to download media of message %s: %v", messageID, err))
+	}
+	return data, message.Filename, nil
+}
+
+// storedMediaFits reports whether stored media can be sent again as the given kind. Any media can go out as a
+// document, images and stickers can stand in for each other since stickers are converted from images anyway.
+func storedMediaFits(kind domainSend.MediaKind, mediaType string) bool {
+	switch kind {
+	case domainSend.MediaDocument:
+		return true
+	case domainSend.MediaImage, domainSend.MediaSticker:
+		return mediaType == string(domainSend.MediaImage) || mediaType == string(domainSend.MediaSticker)
+	default:
+		return mediaType == string(kind)
+	}
+}
+
+// storedDownloadable rebuilds a downloadable message from the media fields kept in chat storage
+func storedDownloadable(message *domainChatStorage.Message) (whatsmeow.DownloadableMessage, error) {
+	stored := storedMessage(message)
+	switch {
+	case stored.ImageMessage != nil:
+		return stored.ImageMessage, nil
+	case stored.VideoMessage != nil:
+		return stored.VideoMessage, nil
+	case stored.AudioMessage != nil:
+		return stored.AudioMessage, nil
+	case stored.DocumentMessage != nil:
+		return stored.DocumentMessage, nil
+	case stored.StickerMessage != nil:
+		return stored.StickerMessage, nil
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", message.MediaType)
+	}
+}
+
+// mediaMIME picks the MIME type of resolved media. The type declared by the upload or data URI wins, otherwise it is
+// sniffed from the content. Documents keep resolving by file extension first.
+func mediaMIME(kind domainSend.MediaKind, declared, fileName string, data []byte) string {
+	declared = strings.ToLower(strings.TrimSpace(strings.Split(declared, ";")[0]))
+
+	if kind == domainSend.MediaDocument {
+		if declared == "" || filepath.Ext(fileName) != "" {
+			return resolveDocumentMIME(fileName, data)
+		}
+		return declared
+	}
+
+	if declared != "" && declared != "application/octet-stream" {
+		return declared
+	}
+
+	sniffed := strings.Split(http.DetectContentType(data), ";")[0]
+	if kind == domainSend.MediaAudio {
+		// The sniffer names containers, not the audio inside them
+		switch sniffed {
+		case "application/ogg":
+			return "audio/ogg"
+		case "video/mp4":
+			return "audio/mp4"
+		}
+	}
+	return sniffed
+}
+
+// mediaFileName names media that came without a file name, like base64 data
+func mediaFileName(kind domainSend.MediaKind, mimeType string) string {
+	name := fmt.Sprintf("%s_%s", kind, fiberUtils.UUIDv4())
+	extensions, _ := mime.ExtensionsByType(mimeType)
+	switch {
+	case slices.Contains(extensions, ".jpg"):
+		return name + ".jpg"
+	case len(extensions) > 0:
+		return name + extensions[0]
+	default:
+		return name
+	}
+}
+
+// uploadImage prepares resolved image media the way every image send does: WebP is converted to PNG, a thumbnail
+// is generated and the image is resized when compress is set. The returned message has no caption or context yet.
+func (service serviceSend) uploadImage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, media domainSend.Media, compress bool) (*waE2E.ImageMessage, error) {
+	var (
+		imagePath    string
+		deletedItems []string
+	)
+
+	// Ensure temporary files are always removed, even on early returns
+	defer func() {
+		if len(deletedItems) > 0 {
+			go utils.RemoveFile(0, deletedItems...)
+		}
+	}()
+
+	imageData, fileName := media.Data, media.FileName
+
+	// Check if the image is WebP and convert to PNG if needed
+	if http.DetectContentType(imageData) == "image/webp" {
+		webpImage, err := imaging.Decode(bytes.NewReader(imageData))
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to decode WebP image %v", err))
+		}
+
+		// Change file extension to PNG
+		if strings.HasSuffix(strings.ToLower(fileName), ".webp") {
+			fileName = fileName[:len(fileName)-5] + ".png"
+		} else {
+			fileName = fileName + ".png"
+		}
+
+		// Convert to PNG format
+		var pngBuffer bytes.Buffer
+		err = imaging.Encode(&pngBuffer, webpImage, imaging.PNG)
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to convert WebP to PNG %v", err))
+		}
+		imageData = pngBuffer.Bytes()
+	}
+
+	// Images of an album are prepared at the same time, the name alone may not be unique
+	imageName := fiberUtils.UUIDv4() + "-" + fileName
+	oriImagePath := fmt.Sprintf("%s/%s", config.PathSendItems, imageName)
+	if err := os.WriteFile(oriImagePath, imageData, 0644); err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
+	}
+	deletedItems = append(deletedItems, oriImagePath)
+
+	/* Generate thumbnail with smalled image size */
+	srcImage, err := imaging.Open(oriImagePath)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("Failed to open image file '%s' for thumbnail generation: %v. Possible causes: file not found, unsupported format, or permission denied.", oriImagePath, err))
+	}
+
+	// Resize Thumbnail
+	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
+	imageThumbnail := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, imageName)
+	if err = imaging.Save(resizedImage, imageThumbnail); err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+	}
+	deletedItems = append(deletedItems, imageThumbnail)
+
+	if compress {
+		// Resize image
+		newImage := imaging.Resize(srcImage, 600, 0, imaging.Lanczos)
+		newImagePath := fmt.Sprintf("%s/new-%s", config.PathSendItems, imageName)
+		if err = imaging.Save(newImage, newImagePath); err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
+		}
+		deletedItems = append(deletedItems, newImagePath)
+		imagePath = newImagePath
+	} else {
+		imagePath = oriImagePath
+	}
+
+	// Send to WA server
+	dataWaImage, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	uploadedImage, err := service.uploadMedia(ctx, client, whatsmeow.MediaImage, dataWaImage, recipient)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to upload image: %v", err))
+	}
+	dataWaThumbnail, err := os.ReadFile(imageThumbnail)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to read thumbnail %v", err))
+	}
+
+	return &waE2E.ImageMessage{
+		JPEGThumbnail: dataWaThumbnail,
+		URL:           proto.String(uploadedImage.URL),
+		DirectPath:    proto.String(uploadedImage.DirectPath),
+		MediaKey:      uploadedImage.MediaKey,
+		Mimetype:      proto.String(http.DetectContentType(dataWaImage)),
+		FileEncSHA256: uploadedImage.FileEncSHA256,
+		FileSHA256:    uploadedImage.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(dataWaImage))),
+	}, nil
+}
+
+// uploadVideo prepares resolved video media the way every video send does: ffmpeg generates a thumbnail and
+// compresses the video when compress is set. The returned message has no caption or context yet.
+func (service serviceSend) uploadVideo(ctx context.Context, client *whatsmeow.Client, recipient types.JID, media domainSend.Media, compress bool) (*waE2E.VideoMessage, error) {
+	var (
+		videoPath    string
+		deletedItems []string
+	)
+
+	// Ensure temporary files are always removed, even on early returns
+	defer func() {
+		if len(deletedItems) > 0 {
+			// Run cleanup in background with slight delay to avoid race with open handles
+			go utils.RemoveFile(1, deletedItems...)
+		}
+	}()
+
+	generateUUID := fiberUtils.UUIDv4()
+
+	// Store the video temporarily for ffmpeg
+	oriVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+media.FileName)
+	if errWrite := os.WriteFile(oriVideoPath, media.Data, 0644); errWrite != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to store video in server %v", errWrite))
+	}
+	deletedItems = append(deletedItems, oriVideoPath)
+
+	// Check if ffmpeg is installed
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, pkgError.InternalServerError("ffmpeg not installed")
+	}
+
+	// Generate thumbnail using ffmpeg
+	thumbnailVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".png")
+	cmdThumbnail := exec.Command("ffmpeg", "-i", oriVideoPath, "-ss", "00:00:01.000", "-vframes", "1", thumbnailVideoPath)
+	if err := cmdThumbnail.Run(); err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to create thumbnail %v", err))
+	}
+
+	// Resize Thumbnail
+	srcImage, err := imaging.Open(thumbnailVideoPath)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("Failed to open generated video thumbnail image '%s': %v. Possible causes: file not found, unsupported format, or permission denied.", thumbnailVideoPath, err))
+	}
+	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
+	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".png")
+	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+	}
+
+	deletedItems = append(deletedItems, thumbnailVideoPath)
+	deletedItems = append(deletedItems, thumbnailResizeVideoPath)
+
+	// Compress if requested
+	if compress {
+		compresVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".mp4")
+
+		// Use proper compression settings to reduce file size
+		// -crf 28: Constant Rate Factor (18-28 is good range, higher = smaller file)
+		// -preset medium: Balance between encoding speed and compression efficiency
+		// -c:v libx264: Use H.264 codec for video
+		// -c:a aac: Use AAC codec for audio
+		// -movflags +faststart: Optimize for web streaming
+		// -vf scale=720:-2: Scale video to max width 720px, maintain aspect ratio
+		cmdCompress := exec.Command("ffmpeg", "-i", oriVideoPath,
+			"-c:v", "libx264",
+			"-crf", "28",
+			"-preset", "fast",
+			"-vf", "scale=720:-2",
+			"-c:a", "aac",
+			"-b:a", "128k",
+			"-movflags", "+faststart",
+			"-y", // Overwrite output file if it exists
+			compresVideoPath)
+
+		// Capture both stdout and stderr for better error reporting
+		output, err := cmdCompress.CombinedOutput()
+		if err != nil {
+			logrus.Errorf("ffmpeg compression failed: %v, output: %s", err, string(output))
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to compress video: %v", err))
+		}
+
+		videoPath = compresVideoPath
+		deletedItems = append(deletedItems, compresVideoPath)
+	} else {
+		videoPath = oriVideoPath
+	}
+
+	//Send to WA server
+	dataWaVideo, err := os.ReadFile(videoPath)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := service.uploadMedia(ctx, client, whatsmeow.MediaVideo, dataWaVideo, recipient)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("Failed to upload file: %v", err))
+	}
+	dataWaThumbnail, err := os.ReadFile(thumbnailResizeVideoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &waE2E.VideoMessage{
+		URL:                 proto.String(uploaded.URL),
+		Mimetype:            proto.String(http.DetectContentType(dataWaVideo)),
+		FileLength:          proto.Uint64(uploaded.FileLength),
+		FileSHA256:          uploaded.FileSHA256,
+		FileEncSHA256:       uploaded.FileEncSHA256,
+		MediaKey:            uploaded.MediaKey,
+		DirectPath:          proto.String(uploaded.DirectPath),
+		JPEGThumbnail:       dataWaThumbnail,
+		ThumbnailEncSHA256:  dataWaThumbnail,
+		ThumbnailSHA256:     dataWaThumbnail,
+		ThumbnailDirectPath: proto.String(uploaded.DirectPath),
+	}, nil
+}
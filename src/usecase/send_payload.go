@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+// payloadSender decodes a stored send request and sends it, or only validates it
+type payloadSender func(ctx context.Context, sendService domainSend.ISendUsecase, payload []byte, validateOnly bool) (domainSend.GenericResponse, error)
+
+func newPayloadSender[T any](validate func(context.Context, T) error, send func(domainSend.ISendUsecase, context.Context, T) (domainSend.GenericResponse, error)) payloadSender {
+	return func(ctx context.Context, sendService domainSend.ISendUsecase, payload []byte, validateOnly bool) (response domainSend.GenericResponse, err error) {
+		var request T
+		if err = json.Unmarshal(payload, &request); err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("payload: %v", err))
+		}
+		if validateOnly {
+			return response, validate(ctx, request)
+		}
+		return send(sendService, ctx, request)
+	}
+}
+
+// payloadSenders are the message types bulk and scheduled sends can store
+var payloadSenders = map[string]payloadSender{
+	domainSend.TypeText:     newPayloadSender(validations.ValidateSendMessage, domainSend.ISendUsecase.SendText),
+	domainSend.TypeImage:    newPayloadSender(validations.ValidateSendImage, domainSend.ISendUsecase.SendImage),
+	domainSend.TypeVideo:    newPayloadSender(validations.ValidateSendVideo, domainSend.ISendUsecase.SendVideo),
+	domainSend.TypeAudio:    newPayloadSender(validations.ValidateSendAudio, domainSend.ISendUsecase.SendAudio),
+	domainSend.TypeFile:     newPayloadSender(validations.ValidateSendFile, domainSend.ISendUsecase.SendFile),
+	domainSend.TypeSticker:  newPayloadSender(validations.ValidateSendSticker, domainSend.ISendUsecase.SendSticker),
+	domainSend.TypeContact:  newPayloadSender(validations.ValidateSendContact, domainSend.ISendUsecase.SendContact),
+	domainSend.TypeLink:     newPayloadSender(validations.ValidateSendLink, domainSend.ISendUsecase.SendLink),
+	domainSend.TypeLocation: newPayloadSender(validations.ValidateSendLocation, domainSend.ISendUsecase.SendLocation),
+	domainSend.TypePoll:     newPayloadSender(validations.ValidateSendPoll, domainSend.ISendUsecase.SendPoll),
+}
+
+// storedPayloadOmits are kept beside a stored payload, a stored payload is sent as it is when its time comes
+var storedPayloadOmits = []string{"account_id", "phone", "send_at", "recurrence", "missed_policy"}
+
+// storedPayload removes the account, recipient and scheduling fields from a payload
+func storedPayload(payload json.RawMessage) (json.RawMessage, error) {
+	fields, err := payloadFields(payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// recipientPayload sets the account and recipient on a stored payload
+func recipientPayload(payload json.RawMessage, accountID, phone string) ([]byte, error) {
+	fields, err := payloadFields(payload)
+	if err != nil {
+		return nil, err
+	}
+	fields["account_id"] = accountID
+	fields["phone"] = phone
+	return json.Marshal(fields)
+}
+
+func payloadFields(payload json.RawMessage) (map[string]any, error) {
+	fields := make(map[string]any)
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("payload: must be a JSON object: %v", err))
+	}
+	for _, field := range storedPayloadOmits {
+		delete(fields, field)
+	}
+	return fields, nil
+}
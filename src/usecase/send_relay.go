@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// relayMediaTypes maps relayed media to the WhatsApp upload type, stickers are uploaded as images
+var relayMediaTypes = map[string]whatsmeow.MediaType{
+	domainSend.RelayMediaImage:    whatsmeow.MediaImage,
+	domainSend.RelayMediaVideo:    whatsmeow.MediaVideo,
+	domainSend.RelayMediaAudio:    whatsmeow.MediaAudio,
+	domainSend.RelayMediaDocument: whatsmeow.MediaDocument,
+	domainSend.RelayMediaSticker:  whatsmeow.MediaImage,
+}
+
+// relayMediaLabels is stored in the chat storage for relayed media
+var relayMediaLabels = map[string]string{
+	domainSend.RelayMediaImage:    "🖼️ Image",
+	domainSend.RelayMediaVideo:    "🎥 Video",
+	domainSend.RelayMediaAudio:    "🎵 Audio",
+	domainSend.RelayMediaDocument: "📄 Document",
+	domainSend.RelayMediaSticker:  "🎨 Sticker",
+}
+
+func (service serviceSend) SendRelay(ctx context.Context, request domainSend.RelayRequest) (response domainSend.GenericResponse, err error) {
+	// Get client for account
+	client, err := service.getClient(request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	err = validations.ValidateSendRelay(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	contextInfo, err := service.contextInfo(ctx, client, dataWaRecipient, request.BaseRequest, "")
+	if err != nil {
+		return response, err
+	}
+
+	msg := &waE2E.Message{}
+	content := request.Text
+	if request.Media == nil {
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
+			Text:        proto.String(request.Text),
+			ContextInfo: contextInfo,
+		}
+	} else {
+		media := request.Media
+		uploaded, err := service.uploadMedia(ctx, client, relayMediaTypes[media.Type], media.Data, dataWaRecipient)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("Failed to upload %s: %v", media.Type, err))
+		}
+
+		mimeType := media.MimeType
+		if mimeType == "" {
+			mimeType = http.DetectContentType(media.Data)
+		}
+		var caption *string
+		if request.Text != "" {
+			caption = proto.String(request.Text)
+		}
+
+		switch media.Type {
+		case domainSend.RelayMediaImage:
+			msg.ImageMessage = &waE2E.ImageMessage{
+				Caption:       caption,
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(mimeType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				ContextInfo:   contextInfo,
+			}
+		case domainSend.RelayMediaVideo:
+			msg.VideoMessage = &waE2E.VideoMessage{
+				Caption:       caption,
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(mimeType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				ContextInfo:   contextInfo,
+			}
+		case domainSend.RelayMediaAudio:
+			msg.AudioMessage = &waE2E.AudioMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(mimeType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				ContextInfo:   contextInfo,
+			}
+		case domainSend.RelayMediaDocument:
+			msg.DocumentMessage = &waE2E.DocumentMessage{
+				Caption:       caption,
+				FileName:      proto.String(media.FileName),
+				Title:         proto.String(media.FileName),
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(mimeType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				ContextInfo:   contextInfo,
+			}
+		case domainSend.RelayMediaSticker:
+			msg.StickerMessage = &waE2E.StickerMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(mimeType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				ContextInfo:   contextInfo,
+			}
+		}
+
+		content = relayMediaLabels[media.Type]
+		if request.Text != "" {
+			content += " " + request.Text
+		}
+	}
+
+	// The ID is known ahead, so the caller can record the message before a copy of it can arrive anywhere
+	messageID := client.GenerateMessageID()
+	if request.BeforeSend != nil {
+		if err = request.BeforeSend(messageID); err != nil {
+			return response, err
+		}
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content, whatsmeow.SendRequestExtra{ID: messageID})
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Message relayed to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
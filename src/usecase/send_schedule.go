@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// serviceScheduledSend schedules the send requests that have a send_at instead of sending them right away
+type serviceScheduledSend struct {
+	domainSend.ISendUsecase
+	scheduleService domainSchedule.IScheduleUsecase
+}
+
+// NewScheduledSendService wraps sendService, requests without send_at are passed through unchanged
+func NewScheduledSendService(sendService domainSend.ISendUsecase, scheduleService domainSchedule.IScheduleUsecase) domainSend.ISendUsecase {
+	return serviceScheduledSend{ISendUsecase: sendService, scheduleService: scheduleService}
+}
+
+func (service serviceScheduledSend) SendText(ctx context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendText(ctx, request)
+	}
+	return service.schedule(ctx, domainSend.TypeText, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendImage(ctx, request)
+	}
+	if request.Image != nil {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("image: uploaded files cannot be scheduled, use image_url, base64 or from_message_id")
+	}
+	return service.schedule(ctx, domainSend.TypeImage, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendFile(ctx context.Context, request domainSend.FileRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendFile(ctx, request)
+	}
+	if request.File != nil {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("file: uploaded files cannot be scheduled, use file_url, base64 or from_message_id")
+	}
+	return service.schedule(ctx, domainSend.TypeFile, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendVideo(ctx, request)
+	}
+	if request.Video != nil {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("video: uploaded files cannot be scheduled, use video_url, base64 or from_message_id")
+	}
+	return service.schedule(ctx, domainSend.TypeVideo, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendAudio(ctx, request)
+	}
+	if request.Audio != nil {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("audio: uploaded files cannot be scheduled, use audio_url, base64 or from_message_id")
+	}
+	return service.schedule(ctx, domainSend.TypeAudio, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendSticker(ctx, request)
+	}
+	if request.Sticker != nil {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("sticker: uploaded files cannot be scheduled, use sticker_url, base64 or from_message_id")
+	}
+	return service.schedule(ctx, domainSend.TypeSticker, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendContact(ctx, request)
+	}
+	return service.schedule(ctx, domainSend.TypeContact, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendLink(ctx, request)
+	}
+	return service.schedule(ctx, domainSend.TypeLink, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendLocation(ctx, request)
+	}
+	return service.schedule(ctx, domainSend.TypeLocation, request.BaseRequest, request)
+}
+
+func (service serviceScheduledSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (domainSend.GenericResponse, error) {
+	if request.SendAt == "" {
+		return service.ISendUsecase.SendPoll(ctx, request)
+	}
+	return service.schedule(ctx, domainSend.TypePoll, request.BaseRequest, request)
+}
+
+// SendAlbum can't be scheduled, an album reports every item and doesn't fit the stored single message
+func (service serviceScheduledSend) SendAlbum(ctx context.Context, request domainSend.AlbumRequest) (domainSend.AlbumResponse, error) {
+	if request.SendAt != "" {
+		return domainSend.AlbumResponse{}, pkgError.ValidationError("send_at: albums cannot be scheduled")
+	}
+	return service.ISendUsecase.SendAlbum(ctx, request)
+}
+
+// schedule stores the request as it was given, it is decoded and sent again when it is due
+func (service serviceScheduledSend) schedule(ctx context.Context, messageType string, base domainSend.BaseRequest, request any) (response domainSend.GenericResponse, err error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return response, fmt.Errorf("failed to store request: %w", err)
+	}
+
+	schedule, err := service.scheduleService.CreateSchedule(ctx, domainSchedule.CreateRequest{
+		AccountID:    base.AccountID,
+		Type:         messageType,
+		Phone:        base.Phone,
+		SendAt:       base.SendAt,
+		Recurrence:   base.Recurrence,
+		MissedPolicy: base.MissedPolicy,
+		Payload:      payload,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.ScheduleID = schedule.ID
+	response.Status = fmt.Sprintf("Message to %s scheduled for %s", schedule.Phone, schedule.NextRunAt.Format(time.RFC3339))
+	return response, nil
+}
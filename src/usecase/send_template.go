@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// serviceTemplateSend renders the send requests that have a template_id into their message fields
+type serviceTemplateSend struct {
+	domainSend.ISendUsecase
+	templateService domainTemplate.ITemplateUsecase
+}
+
+// NewTemplateSendService wraps sendService, requests without template_id are passed through unchanged. It
+// renders before scheduling, so a scheduled message keeps the template version it was scheduled with.
+func NewTemplateSendService(sendService domainSend.ISendUsecase, templateService domainTemplate.ITemplateUsecase) domainSend.ISendUsecase {
+	return serviceTemplateSend{ISendUsecase: sendService, templateService: templateService}
+}
+
+func (service serviceTemplateSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	if request.TemplateID != "" {
+		rendered, err := service.render(ctx, domainSend.TypeText, &request.BaseRequest)
+		if err != nil {
+			return response, err
+		}
+		request.Message = rendered.Text
+	}
+	return service.ISendUsecase.SendText(ctx, request)
+}
+
+func (service serviceTemplateSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	if request.TemplateID != "" {
+		rendered, err := service.render(ctx, domainSend.TypeImage, &request.BaseRequest)
+		if err != nil {
+			return response, err
+		}
+		request.Caption = rendered.Caption
+		request.ImageURL = &rendered.MediaURL
+		request.Image = nil
+		request.MediaInput = domainSend.MediaInput{}
+	}
+	return service.ISendUsecase.SendImage(ctx, request)
+}
+
+func (service serviceTemplateSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	if request.TemplateID != "" {
+		rendered, err := service.render(ctx, domainSend.TypeVideo, &request.BaseRequest)
+		if err != nil {
+			return response, err
+		}
+		request.Caption = rendered.Caption
+		request.VideoURL = &rendered.MediaURL
+		request.Video = nil
+		request.MediaInput = domainSend.MediaInput{}
+	}
+	return service.ISendUsecase.SendVideo(ctx, request)
+}
+
+func (service serviceTemplateSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	if request.TemplateID != "" {
+		rendered, err := service.render(ctx, domainSend.TypePoll, &request.BaseRequest)
+		if err != nil {
+			return response, err
+		}
+		request.Question = rendered.Question
+		request.Options = rendered.Options
+	}
+	return service.ISendUsecase.SendPoll(ctx, request)
+}
+
+func (service serviceTemplateSend) SendFile(ctx context.Context, request domainSend.FileRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported("file")
+	}
+	return service.ISendUsecase.SendFile(ctx, request)
+}
+
+func (service serviceTemplateSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported(domainSend.TypeAudio)
+	}
+	return service.ISendUsecase.SendAudio(ctx, request)
+}
+
+func (service serviceTemplateSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported(domainSend.TypeSticker)
+	}
+	return service.ISendUsecase.SendSticker(ctx, request)
+}
+
+func (service serviceTemplateSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported(domainSend.TypeContact)
+	}
+	return service.ISendUsecase.SendContact(ctx, request)
+}
+
+func (service serviceTemplateSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported(domainSend.TypeLink)
+	}
+	return service.ISendUsecase.SendLink(ctx, request)
+}
+
+func (service serviceTemplateSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (domainSend.GenericResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.GenericResponse{}, templateNotSupported(domainSend.TypeLocation)
+	}
+	return service.ISendUsecase.SendLocation(ctx, request)
+}
+
+func (service serviceTemplateSend) SendAlbum(ctx context.Context, request domainSend.AlbumRequest) (domainSend.AlbumResponse, error) {
+	if request.TemplateID != "" {
+		return domainSend.AlbumResponse{}, templateNotSupported("album")
+	}
+	return service.ISendUsecase.SendAlbum(ctx, request)
+}
+
+// render renders the template of the request, which must be of the message type of the endpoint. The
+// template fields are cleared so a stored request isn't rendered twice.
+func (service serviceTemplateSend) render(ctx context.Context, messageType string, base *domainSend.BaseRequest) (rendered domainTemplate.Rendered, err error) {
+	template, rendered, err := service.templateService.Render(ctx, domainTemplate.RenderRequest{
+		TemplateID: base.TemplateID,
+		Version:    base.TemplateVersion,
+		Language:   base.TemplateLanguage,
+		Variables:  base.Variables,
+	})
+	// A template of the wrong type is reported before its variables
+	if template.ID != "" && template.Type != messageType {
+		return rendered, pkgError.ValidationError(fmt.Sprintf("template_id: template %s is a %s template, it cannot be sent as %s", template.ID, template.Type, messageType))
+	}
+	if err != nil {
+		return rendered, err
+	}
+
+	base.TemplateID = ""
+	base.TemplateVersion = 0
+	base.TemplateLanguage = ""
+	base.Variables = nil
+	return rendered, nil
+}
+
+func templateNotSupported(messageType string) error {
+	return pkgError.ValidationError(fmt.Sprintf("template_id: %s messages cannot be sent from a template", messageType))
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// statusAudienceMu serializes statuses posted to a list audience, they are sent past the send lock of the client
+var statusAudienceMu sync.Mutex
+
+// statusAudienceTimeout is how long a status to a list audience waits for the server to acknowledge it, as long as
+// whatsmeow waits for other messages
+const statusAudienceTimeout = 75 * time.Second
+
+type serviceStatus struct {
+	repo domainStatus.IStatusRepository
+	// send is the send service whose media pipeline image and video statuses go through
+	send serviceSend
+}
+
+func NewStatusService(repo domainStatus.IStatusRepository, appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository, accountManager domainAccount.IAccountManager, accountRepo domainAccount.IAccountRepository) domainStatus.IStatusUsecase {
+	return &serviceStatus{
+		repo: repo,
+		send: serviceSend{
+			appService:      appService,
+			chatStorageRepo: chatStorageRepo,
+			accountManager:  accountManager,
+			accountRepo:     accountRepo,
+		},
+	}
+}
+
+func (service *serviceStatus) PostStatus(ctx context.Context, request domainStatus.PostRequest) (status domainStatus.Status, err error) {
+	client, err := service.send.getClient(request.AccountID)
+	if err != nil {
+		return status, err
+	}
+
+	if err = validations.ValidateStatusPost(ctx, request); err != nil {
+		return status, err
+	}
+	if request.Audience == "" {
+		request.Audience = domainStatus.AudienceContacts
+	}
+	utils.MustLogin(client)
+
+	// The audience is checked before any media is uploaded
+	var audience []types.JID
+	if request.Audience == domainStatus.AudienceList {
+		if audience, err = statusAudience(client, request.Phones); err != nil {
+			return status, err
+		}
+	}
+
+	msg, content, err := service.statusMessage(ctx, client, request)
+	if err != nil {
+		return status, err
+	}
+
+	var ts whatsmeow.SendResponse
+	if audience != nil {
+		ts, err = service.sendToAudience(ctx, client, audience, msg, content)
+	} else {
+		ts, err = service.send.wrapSendMessage(ctx, client, types.StatusBroadcastJID, msg, content)
+	}
+	if err != nil {
+		return status, err
+	}
+
+	status = domainStatus.Status{
+		ID:              ts.ID,
+		AccountID:       request.AccountID,
+		Type:            request.Type,
+		Text:            request.Text,
+		Caption:         request.Caption,
+		BackgroundColor: request.BackgroundColor,
+		TextColor:       request.TextColor,
+		Font:            request.Font,
+		Audience:        request.Audience,
+		Phones:          request.Phones,
+		PostedAt:        ts.Timestamp,
+	}
+	if status.PostedAt.IsZero() {
+		status.PostedAt = time.Now()
+	}
+	// The status is posted already, failing to store it must not make the caller post it again
+	if err = service.repo.CreateStatus(&status); err != nil {
+		return status, pkgError.InternalServerError(fmt.Sprintf("status %s was posted but could not be stored: %v", status.ID, err))
+	}
+	return status, nil
+}
+
+func (service *serviceStatus) ListStatuses(ctx context.Context, filter domainStatus.Filter) ([]domainStatus.Status, error) {
+	if err := validations.ValidateStatusFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListStatuses(filter)
+}
+
+// statusMessage builds the message of a status, image and video go through the same pipeline as /send/image and
+// /send/video. content is what the chat storage keeps for it.
+func (service *serviceStatus) statusMessage(ctx context.Context, client *whatsmeow.Client, request domainStatus.PostRequest) (msg *waE2E.Message, content string, err error) {
+	switch request.Type {
+	case domainStatus.TypeImage:
+		media, err := service.send.resolveMedia(ctx, client, domainSend.MediaImage, request.MediaSource())
+		if err != nil {
+			return nil, "", err
+		}
+		imageMessage, err := service.send.uploadImage(ctx, client, types.StatusBroadcastJID, media, request.Compress)
+		if err != nil {
+			return nil, "", err
+		}
+		imageMessage.Caption = proto.String(request.Caption)
+		return &waE2E.Message{ImageMessage: imageMessage}, mediaLabel("🖼️ ", "Image", request.Caption), nil
+	case domainStatus.TypeVideo:
+		media, err := service.send.resolveMedia(ctx, client, domainSend.MediaVideo, request.MediaSource())
+		if err != nil {
+			return nil, "", err
+		}
+		videoMessage, err := service.send.uploadVideo(ctx, client, types.StatusBroadcastJID, media, request.Compress)
+		if err != nil {
+			return nil, "", err
+		}
+		videoMessage.Caption = proto.String(request.Caption)
+		return &waE2E.Message{VideoMessage: videoMessage}, mediaLabel("🎥 ", "Video", request.Caption), nil
+	}
+
+	text := &waE2E.ExtendedTextMessage{Text: proto.String(request.Text)}
+	// Validation accepted the colors already
+	if request.BackgroundColor != "" {
+		color, _ := domainStatus.ParseColor(request.BackgroundColor)
+		text.BackgroundArgb = proto.Uint32(color)
+	}
+	if request.TextColor != "" {
+		color, _ := domainStatus.ParseColor(request.TextColor)
+		text.TextArgb = proto.Uint32(color)
+	}
+	if request.Font != "" {
+		text.Font = domainStatus.Fonts[request.Font].Enum()
+	}
+	return &waE2E.Message{ExtendedTextMessage: text}, request.Text, nil
+}
+
+// mediaLabel is the stored content of a media status, its caption or the name of the media
+func mediaLabel(icon, name, caption string) string {
+	if caption != "" {
+		return icon + caption
+	}
+	return icon + name
+}
+
+// statusAudience resolves the phones of a list audience to the users a status is sent to
+func statusAudience(client *whatsmeow.Client, phones []string) ([]types.JID, error) {
+	audience := make([]types.JID, 0, len(phones))
+	for _, phone := range phones {
+		jid, err := utils.ValidateJidWithLogin(client, phone)
+		if err != nil {
+			return nil, err
+		}
+		if jid.Server != types.DefaultUserServer {
+			return nil, pkgError.ValidationError(fmt.Sprintf("phones: %s is not a user", phone))
+		}
+		if jid = jid.ToNonAD(); !slices.Contains(audience, jid) {
+			audience = append(audience, jid)
+		}
+	}
+	return audience, nil
+}
+
+// sendToAudience posts a status to the given users only. SendMessage sends a status to the viewers the status privacy
+// of the account allows, so the status goes through the broadcast send SendMessage uses, with the audience as its
+// participants. The account itself is added like whatsmeow does, so its other devices see the status too.
+func (service *serviceStatus) sendToAudience(ctx context.Context, client *whatsmeow.Client, audience []types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	if client.Store.ID == nil {
+		return whatsmeow.SendResponse{}, pkgError.ErrNotLoggedIn
+	}
+	ownID := *client.Store.ID
+	participants := append(slices.Clone(audience), ownID.ToNonAD())
+
+	statusAudienceMu.Lock()
+	defer statusAudienceMu.Unlock()
+
+	ts := whatsmeow.SendResponse{ID: client.GenerateMessageID(), Sender: ownID}
+	// The internal send is the only one of whatsmeow that takes the participants of a broadcast
+	internals := client.DangerousInternals()
+	respChan := internals.WaitResponse(ts.ID)
+	internals.AddRecentMessage(types.StatusBroadcastJID, ts.ID, msg, nil)
+	if err := sendBroadcast(ctx, internals.SendGroup, ownID, participants, ts.ID, msg, &ts.DebugTimings); err != nil {
+		internals.CancelResponse(ts.ID, respChan)
+		return ts, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusAudienceTimeout)
+	defer cancel()
+	select {
+	case node := <-respChan:
+		if node.Tag != "ack" {
+			return ts, pkgError.InternalServerError(fmt.Sprintf("status %s was not acknowledged, the connection closed", ts.ID))
+		}
+		ag := node.AttrGetter()
+		if code := ag.OptionalInt("error"); code != 0 {
+			return ts, pkgError.InternalServerError(fmt.Sprintf("server returned error %d for status %s", code, ts.ID))
+		}
+		ts.ServerID = types.MessageServerID(ag.OptionalInt("server_id"))
+		ts.Timestamp = ag.UnixTime("t")
+	case <-ctx.Done():
+		internals.CancelResponse(ts.ID, respChan)
+		return ts, ctx.Err()
+	}
+
+	service.send.storeSentMessage(client, types.StatusBroadcastJID, ts, msg, content)
+	return ts, nil
+}
+
+// sendBroadcast calls the broadcast send of whatsmeow, whose extra parameters are unexported and left empty as
+// SendMessage leaves them for a status
+func sendBroadcast[P any](ctx context.Context, send func(context.Context, types.JID, types.JID, []types.JID, types.MessageID, *waE2E.Message, *whatsmeow.MessageDebugTimings, P) (string, []byte, error),
+	ownID types.JID, participants []types.JID, id types.MessageID, msg *waE2E.Message, timings *whatsmeow.MessageDebugTimings) error {
+	var extra P
+	_, _, err := send(ctx, ownID, types.StatusBroadcastJID, participants, id, msg, timings, extra)
+	return err
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+)
+
+type serviceTemplate struct {
+	repo domainTemplate.ITemplateRepository
+}
+
+func NewTemplateService(repo domainTemplate.ITemplateRepository) domainTemplate.ITemplateUsecase {
+	return &serviceTemplate{repo: repo}
+}
+
+func (service *serviceTemplate) ListTemplates(ctx context.Context, filter domainTemplate.Filter) ([]domainTemplate.Template, error) {
+	if err := validations.ValidateTemplateFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.repo.ListTemplates(filter)
+}
+
+func (service *serviceTemplate) GetTemplate(_ context.Context, templateID string) (template domainTemplate.Template, err error) {
+	stored, err := service.repo.GetTemplate(templateID)
+	if err != nil {
+		return template, err
+	}
+	return *stored, nil
+}
+
+func (service *serviceTemplate) CreateTemplate(ctx context.Context, request domainTemplate.TemplateRequest) (template domainTemplate.Template, err error) {
+	if err = validations.ValidateTemplate(ctx, request); err != nil {
+		return template, err
+	}
+
+	now := time.Now()
+	template = templateFromRequest(request)
+	template.ID = uuid.NewString()
+	template.Version = 1
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	if err = service.repo.CreateTemplate(&template); err != nil {
+		return template, err
+	}
+	return template, nil
+}
+
+func (service *serviceTemplate) UpdateTemplate(ctx context.Context, templateID string, request domainTemplate.TemplateRequest) (template domainTemplate.Template, err error) {
+	if err = validations.ValidateTemplate(ctx, request); err != nil {
+		return template, err
+	}
+
+	stored, err := service.repo.GetTemplate(templateID)
+	if err != nil {
+		return template, err
+	}
+	// Sends pinned to an older version must still get the message type they were written for
+	if request.Type != stored.Type {
+		return template, pkgError.ValidationError(fmt.Sprintf("type: template %s is a %s template, it cannot become %s", templateID, stored.Type, request.Type))
+	}
+
+	template = templateFromRequest(request)
+	template.ID = stored.ID
+	template.Version = stored.Version + 1
+	template.CreatedAt = stored.CreatedAt
+	template.UpdatedAt = time.Now()
+
+	if err = service.repo.UpdateTemplate(&template); err != nil {
+		return template, err
+	}
+	return template, nil
+}
+
+func (service *serviceTemplate) DeleteTemplate(_ context.Context, templateID string) error {
+	return service.repo.DeleteTemplate(templateID)
+}
+
+func (service *serviceTemplate) ListVersions(_ context.Context, templateID string) ([]domainTemplate.Version, error) {
+	if _, err := service.repo.GetTemplate(templateID); err != nil {
+		return nil, err
+	}
+	return service.repo.ListVersions(templateID)
+}
+
+func (service *serviceTemplate) Render(_ context.Context, request domainTemplate.RenderRequest) (template domainTemplate.Template, rendered domainTemplate.Rendered, err error) {
+	if request.TemplateID == "" {
+		return template, rendered, pkgError.ValidationError("template_id: cannot be blank.")
+	}
+
+	var stored *domainTemplate.Template
+	if request.Version > 0 {
+		stored, err = service.repo.GetVersion(request.TemplateID, request.Version)
+	} else {
+		stored, err = service.repo.GetTemplate(request.TemplateID)
+	}
+	if err != nil {
+		return template, rendered, err
+	}
+	template = *stored
+
+	variant, ok := template.Variant(request.Language)
+	if !ok {
+		return template, rendered, pkgError.ValidationError(fmt.Sprintf("template_language: template %s has no %s variant", template.ID, request.Language))
+	}
+	rendered, err = variant.Render(request.Variables)
+	if err != nil {
+		var missing domainTemplate.MissingVariablesError
+		if errors.As(err, &missing) {
+			return template, rendered, pkgError.ValidationError(fmt.Sprintf("variables: %v.", missing))
+		}
+		return template, rendered, pkgError.ValidationError(fmt.Sprintf("variables: %v", err))
+	}
+	return template, rendered, nil
+}
+
+func templateFromRequest(request domainTemplate.TemplateRequest) domainTemplate.Template {
+	defaultLanguage := request.DefaultLanguage
+	if defaultLanguage == "" {
+		defaultLanguage = request.Variants[0].Language
+	}
+	return domainTemplate.Template{
+		Name:            request.Name,
+		Type:            request.Type,
+		DefaultLanguage: defaultLanguage,
+		Variants:        request.Variants,
+	}
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceWebhook struct {
+	ruleRepo     domainWebhook.IRuleRepository
+	deliveryRepo domainWebhook.IDeliveryRepository
+}
+
+// NewWebhookService loads the stored routing rules into the event pipeline
+func NewWebhookService(ruleRepo domainWebhook.IRuleRepository, deliveryRepo domainWebhook.IDeliveryRepository) domainWebhook.IWebhookUsecase {
+	service := &serviceWebhook{ruleRepo: ruleRepo, deliveryRepo: deliveryRepo}
+	if err := service.reloadRules(); err != nil {
+		logrus.Errorf("Failed to load webhook rules: %v", err)
+	}
+	return service
+}
+
+func (service serviceWebhook) ListRules(_ context.Context) ([]domainWebhook.Rule, error) {
+	return service.ruleRepo.ListRules()
+}
+
+func (service serviceWebhook) GetRule(_ context.Context, ruleID string) (rule domainWebhook.Rule, err error) {
+	stored, err := service.ruleRepo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+	return *stored, nil
+}
+
+func (service serviceWebhook) CreateRule(ctx context.Context, request domainWebhook.RuleRequest) (rule domainWebhook.Rule, err error) {
+	if err = validations.ValidateWebhookRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	now := time.Now()
+	rule = ruleFromRequest(request)
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err = service.ruleRepo.CreateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service serviceWebhook) UpdateRule(ctx context.Context, ruleID string, request domainWebhook.RuleRequest) (rule domainWebhook.Rule, err error) {
+	if err = validations.ValidateWebhookRule(ctx, request); err != nil {
+		return rule, err
+	}
+
+	stored, err := service.ruleRepo.GetRule(ruleID)
+	if err != nil {
+		return rule, err
+	}
+
+	rule = ruleFromRequest(request)
+	rule.ID = stored.ID
+	rule.CreatedAt = stored.CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if err = service.ruleRepo.UpdateRule(&rule); err != nil {
+		return rule, err
+	}
+	return rule, service.reloadRules()
+}
+
+func (service serviceWebhook) DeleteRule(_ context.Context, ruleID string) error {
+	if err := service.ruleRepo.DeleteRule(ruleID); err != nil {
+		return err
+	}
+	return service.reloadRules()
+}
+
+func (service serviceWebhook) TestRules(_ context.Context, subject domainWebhook.Subject) (domainWebhook.Decision, error) {
+	return whatsapp.EvaluateWebhookRules(subject), nil
+}
+
+func (service serviceWebhook) ListDeliveries(ctx context.Context, filter domainWebhook.DeliveryFilter) ([]domainWebhook.Delivery, error) {
+	if err := validations.ValidateWebhookDeliveryFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return service.deliveryRepo.ListDeliveries(filter)
+}
+
+func (service serviceWebhook) GetDelivery(_ context.Context, deliveryID string) (delivery domainWebhook.Delivery, err error) {
+	stored, err := service.deliveryRepo.GetDelivery(deliveryID)
+	if err != nil {
+		return delivery, err
+	}
+	return *stored, nil
+}
+
+// reloadRules compiles the stored rules and swaps them into the event pipeline
+func (service serviceWebhook) reloadRules() error {
+	rules, err := service.ruleRepo.ListRules()
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := domainWebhook.NewRuleSet(rules)
+	if err != nil {
+		return pkgError.InternalServerError(err.Error())
+	}
+	whatsapp.SetWebhookRules(ruleSet)
+	return nil
+}
+
+func ruleFromRequest(request domainWebhook.RuleRequest) domainWebhook.Rule {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	return domainWebhook.Rule{
+		Name:      request.Name,
+		Priority:  request.Priority,
+		Enabled:   enabled,
+		Match:     request.Match,
+		Action:    request.Action,
+		Endpoints: request.Endpoints,
+		Tags:      request.Tags,
+		Final:     request.Final,
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainEventSink "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/eventsink"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type webhookActionExecutor struct {
+	sendService    domainSend.ISendUsecase
+	messageService domainMessage.IMessageUsecase
+}
+
+// NewWebhookActionExecutor runs webhook reply actions through the send and message usecases
+func NewWebhookActionExecutor(sendService domainSend.ISendUsecase, messageService domainMessage.IMessageUsecase) domainWebhook.IActionExecutor {
+	return &webhookActionExecutor{
+		sendService:    sendService,
+		messageService: messageService,
+	}
+}
+
+// ExecuteActions runs the actions in order. A failing action does not stop the next one.
+func (executor webhookActionExecutor) ExecuteActions(ctx context.Context, subject domainWebhook.Subject, actions []domainWebhook.Action) []domainWebhook.ActionResult {
+	results := make([]domainWebhook.ActionResult, 0, len(actions))
+	for i, action := range actions {
+		if i >= domainWebhook.MaxReplyActions {
+			results = append(results, domainWebhook.ActionResult{
+				Type:   action.Type,
+				Status: domainWebhook.ActionStatusInvalid,
+				Error:  fmt.Sprintf("only the first %d actions of a response are executed", domainWebhook.MaxReplyActions),
+			})
+			continue
+		}
+		results = append(results, executor.execute(ctx, subject, action))
+	}
+	return results
+}
+
+func (executor webhookActionExecutor) execute(ctx context.Context, subject domainWebhook.Subject, action domainWebhook.Action) (result domainWebhook.ActionResult) {
+	result = domainWebhook.ActionResult{Type: action.Type, Status: domainWebhook.ActionStatusSuccess}
+
+	if err := executor.validate(ctx, subject, action); err != nil {
+		result.Status = domainWebhook.ActionStatusInvalid
+		result.Error = err.Error()
+		return result
+	}
+
+	messageID, err := executor.run(ctx, subject, action)
+	if err != nil {
+		result.Status = domainWebhook.ActionStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.MessageID = messageID
+	return result
+}
+
+func (executor webhookActionExecutor) validate(ctx context.Context, subject domainWebhook.Subject, action domainWebhook.Action) error {
+	if err := validations.ValidateWebhookAction(ctx, action); err != nil {
+		return err
+	}
+
+	needsMessage := action.Type == domainWebhook.ActionReact || action.Type == domainWebhook.ActionMarkRead || action.Quote
+	if needsMessage && subject.MessageID == "" {
+		return fmt.Errorf("%s needs a message, %s events have none", action.Type, subject.EventType)
+	}
+
+	// Reactions and read receipts are only available on the default device
+	isDefault := subject.AccountID == "" || subject.AccountID == domainEventSink.DefaultAccountID
+	if !isDefault && (action.Type == domainWebhook.ActionReact || action.Type == domainWebhook.ActionMarkRead) {
+		return fmt.Errorf("%s is only supported for events of the default device", action.Type)
+	}
+	return nil
+}
+
+// run executes a validated action and returns the ID of the message it sent
+func (executor webhookActionExecutor) run(ctx context.Context, subject domainWebhook.Subject, action domainWebhook.Action) (messageID string, err error) {
+	defer recoverSend(&err)
+
+	base := domainSend.BaseRequest{Phone: subject.ChatJID}
+	if subject.AccountID != domainEventSink.DefaultAccountID {
+		base.AccountID = subject.AccountID
+	}
+
+	switch action.Type {
+	case domainWebhook.ActionReply:
+		request := domainSend.MessageRequest{BaseRequest: base, Message: action.Text}
+		if action.Quote {
+			request.ReplyMessageID = &subject.MessageID
+		}
+		response, err := executor.sendService.SendText(ctx, request)
+		return response.MessageID, err
+
+	case domainWebhook.ActionSendMedia:
+		var (
+			response domainSend.GenericResponse
+			err      error
+		)
+		switch action.MediaType {
+		case domainWebhook.MessageTypeImage:
+			response, err = executor.sendService.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, Caption: action.Caption, ImageURL: &action.URL})
+		case domainWebhook.MessageTypeVideo:
+			response, err = executor.sendService.SendVideo(ctx, domainSend.VideoRequest{BaseRequest: base, Caption: action.Caption, VideoURL: &action.URL})
+		case domainWebhook.MessageTypeAudio:
+			response, err = executor.sendService.SendAudio(ctx, domainSend.AudioRequest{BaseRequest: base, AudioURL: &action.URL})
+		}
+		return response.MessageID, err
+
+	case domainWebhook.ActionReact:
+		response, err := executor.messageService.ReactMessage(ctx, domainMessage.ReactionRequest{
+			MessageID: subject.MessageID,
+			Phone:     subject.ChatJID,
+			Emoji:     action.Emoji,
+		})
+		return response.MessageID, err
+
+	case domainWebhook.ActionMarkRead:
+		_, err := executor.messageService.MarkAsRead(ctx, domainMessage.MarkAsReadRequest{
+			MessageID: subject.MessageID,
+			Phone:     subject.ChatJID,
+		})
+		return "", err
+	}
+	return "", fmt.Errorf("unsupported action %s", action.Type)
+}
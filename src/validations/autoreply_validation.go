@@ -0,0 +1,108 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateAutoReplyRule(ctx context.Context, request domainAutoReply.RuleRequest) error {
+	usesPattern := request.TriggerType == domainAutoReply.TriggerRegex || request.TriggerType == domainAutoReply.TriggerExact
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.TriggerType, validation.Required, validation.In(
+			domainAutoReply.TriggerKeyword, domainAutoReply.TriggerRegex, domainAutoReply.TriggerExact,
+		)),
+		validation.Field(&request.Keywords,
+			validation.When(request.TriggerType == domainAutoReply.TriggerKeyword, validation.Required),
+			validation.Each(validation.Required),
+		),
+		validation.Field(&request.Pattern,
+			validation.When(usesPattern, validation.Required),
+			validation.When(request.TriggerType == domainAutoReply.TriggerRegex, validation.By(validRegex)),
+		),
+		validation.Field(&request.Reply, validation.When(request.MediaURL == "", validation.Required)),
+		validation.Field(&request.MediaType,
+			validation.When(request.MediaURL != "", validation.Required),
+			validation.In(domainAutoReply.MediaImage, domainAutoReply.MediaVideo, domainAutoReply.MediaAudio),
+		),
+		validation.Field(&request.MediaURL, validation.When(request.MediaType != "", validation.Required), is.URL),
+		validation.Field(&request.CooldownSeconds, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateAutoReplyMatchFilter(ctx context.Context, filter domainAutoReply.MatchFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(
+			domainAutoReply.MatchStatusSent, domainAutoReply.MatchStatusFailed, domainAutoReply.MatchStatusCooldown,
+		)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateAutoReplySchedule(ctx context.Context, request domainAutoReply.ScheduleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.TimeZone, validation.By(validTimeZone)),
+		validation.Field(&request.Hours,
+			validation.When(request.AwayMessage != "", validation.Required),
+			validation.Each(validation.By(validBusinessHours)),
+		),
+		validation.Field(&request.Holidays, validation.Each(validation.By(validHoliday))),
+		validation.Field(&request.AwayMessage, validation.When(request.GreetingMessage == "", validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validTimeZone(value any) error {
+	timeZone, _ := value.(string)
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return errors.New("must be a valid IANA time zone")
+	}
+	return nil
+}
+
+func validBusinessHours(value any) error {
+	hours, _ := value.(domainAutoReply.BusinessHours)
+	if !slices.Contains(domainAutoReply.Weekdays, strings.ToLower(hours.Day)) {
+		return fmt.Errorf("day must be one of %s", strings.Join(domainAutoReply.Weekdays, ", "))
+	}
+	return domainAutoReply.CheckOpening(hours.Open, hours.Close)
+}
+
+func validHoliday(value any) error {
+	holiday, _ := value.(domainAutoReply.Holiday)
+	if _, err := time.Parse(domainAutoReply.DateLayout, holiday.Date); err != nil {
+		return errors.New("date must be in YYYY-MM-DD format")
+	}
+	if holiday.Open == "" && holiday.Close == "" {
+		return nil
+	}
+	return domainAutoReply.CheckOpening(holiday.Open, holiday.Close)
+}
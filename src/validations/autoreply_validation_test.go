@@ -0,0 +1,158 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAutoReplyRule(t *testing.T) {
+	type args struct {
+		request domainAutoReply.RuleRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with keywords and a templated reply",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:            "pricing",
+				TriggerType:     domainAutoReply.TriggerKeyword,
+				Keywords:        []string{"price", "cost"},
+				Reply:           "Hi {{pushname}}, our price list is at https://example.com/prices",
+				CooldownSeconds: 3600,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with a media reply without text",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:        "menu",
+				TriggerType: domainAutoReply.TriggerExact,
+				Pattern:     "menu",
+				MediaType:   domainAutoReply.MediaImage,
+				MediaURL:    "https://example.com/menu.png",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown trigger type",
+			args: args{request: domainAutoReply.RuleRequest{Name: "unknown", TriggerType: "fuzzy", Reply: "hi"}},
+			err:  pkgError.ValidationError("trigger_type: must be a valid value."),
+		},
+		{
+			name: "should error with keyword trigger without keywords",
+			args: args{request: domainAutoReply.RuleRequest{Name: "empty", TriggerType: domainAutoReply.TriggerKeyword, Reply: "hi"}},
+			err:  pkgError.ValidationError("keywords: cannot be blank."),
+		},
+		{
+			name: "should error with invalid regex",
+			args: args{request: domainAutoReply.RuleRequest{Name: "bad", TriggerType: domainAutoReply.TriggerRegex, Pattern: "(", Reply: "hi"}},
+			err:  pkgError.ValidationError("pattern: must be a valid regular expression."),
+		},
+		{
+			name: "should error without reply and media",
+			args: args{request: domainAutoReply.RuleRequest{Name: "silent", TriggerType: domainAutoReply.TriggerExact, Pattern: "hi"}},
+			err:  pkgError.ValidationError("reply: cannot be blank."),
+		},
+		{
+			name: "should error with media url without type",
+			args: args{request: domainAutoReply.RuleRequest{Name: "media", TriggerType: domainAutoReply.TriggerExact, Pattern: "hi", MediaURL: "https://example.com/a.pdf"}},
+			err:  pkgError.ValidationError("media_type: cannot be blank."),
+		},
+		{
+			name: "should error with negative cooldown",
+			args: args{request: domainAutoReply.RuleRequest{Name: "cooldown", TriggerType: domainAutoReply.TriggerExact, Pattern: "hi", Reply: "hi", CooldownSeconds: -1}},
+			err:  pkgError.ValidationError("cooldown_seconds: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyRule(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateAutoReplySchedule(t *testing.T) {
+	type args struct {
+		request domainAutoReply.ScheduleRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with hours, holidays and both messages",
+			args: args{request: domainAutoReply.ScheduleRequest{
+				TimeZone: "Asia/Jakarta",
+				Hours: []domainAutoReply.BusinessHours{
+					{Day: "monday", Open: "09:00", Close: "17:00"},
+					{Day: "Friday", Open: "20:00", Close: "02:00"},
+				},
+				Holidays:        []domainAutoReply.Holiday{{Date: "2025-12-25", Name: "Christmas"}, {Date: "2025-12-24", Open: "09:00", Close: "12:00"}},
+				AwayMessage:     "We are closed, we'll answer tomorrow",
+				GreetingMessage: "Welcome {{pushname}}!",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with only a greeting",
+			args: args{request: domainAutoReply.ScheduleRequest{GreetingMessage: "Welcome!"}},
+			err:  nil,
+		},
+		{
+			name: "should error without messages",
+			args: args{request: domainAutoReply.ScheduleRequest{Hours: []domainAutoReply.BusinessHours{{Day: "monday", Open: "09:00", Close: "17:00"}}}},
+			err:  pkgError.ValidationError("away_message: cannot be blank."),
+		},
+		{
+			name: "should error with away message without hours",
+			args: args{request: domainAutoReply.ScheduleRequest{AwayMessage: "closed"}},
+			err:  pkgError.ValidationError("hours: cannot be blank."),
+		},
+		{
+			name: "should error with unknown time zone",
+			args: args{request: domainAutoReply.ScheduleRequest{TimeZone: "Mars/Olympus", GreetingMessage: "hi"}},
+			err:  pkgError.ValidationError("time_zone: must be a valid IANA time zone."),
+		},
+		{
+			name: "should error with unknown day",
+			args: args{request: domainAutoReply.ScheduleRequest{
+				Hours:       []domainAutoReply.BusinessHours{{Day: "someday", Open: "09:00", Close: "17:00"}},
+				AwayMessage: "closed",
+			}},
+			err: pkgError.ValidationError("hours: (0: day must be one of sunday, monday, tuesday, wednesday, thursday, friday, saturday.)."),
+		},
+		{
+			name: "should error with empty opening",
+			args: args{request: domainAutoReply.ScheduleRequest{
+				Hours:       []domainAutoReply.BusinessHours{{Day: "monday", Open: "09:00", Close: "09:00"}},
+				AwayMessage: "closed",
+			}},
+			err: pkgError.ValidationError("hours: (0: opening 09:00-09:00 is empty.)."),
+		},
+		{
+			name: "should error with invalid holiday date",
+			args: args{request: domainAutoReply.ScheduleRequest{
+				Holidays:        []domainAutoReply.Holiday{{Date: "25-12-2025"}},
+				GreetingMessage: "hi",
+			}},
+			err: pkgError.ValidationError("holidays: (0: date must be in YYYY-MM-DD format.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplySchedule(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
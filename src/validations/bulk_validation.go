@@ -0,0 +1,68 @@
+package validations
+
+import (
+	"context"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// maxBulkPauseMs is the longest interval and jitter of a bulk send job, one hour
+const maxBulkPauseMs = 60 * 60 * 1000
+
+func ValidateBulkJob(ctx context.Context, request domainBulk.JobRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Type, validation.Required, validation.In(
+			domainSend.TypeText, domainSend.TypeImage, domainSend.TypeVideo, domainSend.TypeAudio, domainSend.TypeFile, domainSend.TypeSticker,
+			domainSend.TypeContact, domainSend.TypeLink, domainSend.TypeLocation, domainSend.TypePoll,
+		)),
+		validation.Field(&request.Recipients,
+			validation.Required,
+			validation.Length(1, domainBulk.MaxRecipients),
+			validation.Each(validation.By(validRecipientPhone)),
+		),
+		validation.Field(&request.Payload, validation.Required),
+		validation.Field(&request.IntervalMs, validation.Min(0), validation.Max(maxBulkPauseMs)),
+		validation.Field(&request.JitterMs, validation.Min(0), validation.Max(maxBulkPauseMs)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateBulkJobFilter(ctx context.Context, filter domainBulk.JobFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(
+			domainBulk.JobRunning, domainBulk.JobPaused, domainBulk.JobCompleted, domainBulk.JobCancelled,
+		)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateBulkRecipientFilter(ctx context.Context, filter domainBulk.RecipientFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(
+			domainBulk.RecipientPending, domainBulk.RecipientSent, domainBulk.RecipientFailed, domainBulk.RecipientCancelled,
+		)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(1000)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
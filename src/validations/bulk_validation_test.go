@@ -0,0 +1,58 @@
+package validations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBulkJob(t *testing.T) {
+	payload := json.RawMessage(`{"message": "Our sale starts today"}`)
+	negative := -1
+
+	type args struct {
+		request domainBulk.JobRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with text payload",
+			args: args{request: domainBulk.JobRequest{Type: "text", Recipients: []string{"6281234567890", "120363025246125486@g.us"}, Payload: payload}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown type",
+			args: args{request: domainBulk.JobRequest{Type: "presence", Recipients: []string{"6281234567890"}, Payload: payload}},
+			err:  pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name: "should error without recipients",
+			args: args{request: domainBulk.JobRequest{Type: "text", Payload: payload}},
+			err:  pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name: "should error with local phone number",
+			args: args{request: domainBulk.JobRequest{Type: "text", Recipients: []string{"6281234567890", "081234567890"}, Payload: payload}},
+			err:  pkgError.ValidationError("recipients: (1: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx.)."),
+		},
+		{
+			name: "should error with negative interval",
+			args: args{request: domainBulk.JobRequest{Type: "text", Recipients: []string{"6281234567890"}, Payload: payload, IntervalMs: &negative}},
+			err:  pkgError.ValidationError("interval_ms: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBulkJob(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var flowIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func ValidateFlow(ctx context.Context, flow domainFlow.Flow) error {
+	err := validation.ValidateStructWithContext(ctx, &flow,
+		validation.Field(&flow.ID, validation.Required, validation.Match(flowIDPattern).Error("must only contain letters, digits, - and _")),
+		validation.Field(&flow.Triggers, validation.Required, validation.Each(validation.Required)),
+		validation.Field(&flow.Start, validation.Required, validation.By(flowStateExists(flow.States))),
+		validation.Field(&flow.TimeoutSeconds, validation.Min(0)),
+		validation.Field(&flow.HandoffTimeoutSeconds, validation.Min(0)),
+		validation.Field(&flow.States, validation.Required, validation.By(func(any) error {
+			return validateFlowStates(ctx, flow.States)
+		})),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateFlowStates(ctx context.Context, states map[string]domainFlow.State) error {
+	errs := validation.Errors{}
+	for name, state := range states {
+		err := validation.ValidateStructWithContext(ctx, &state,
+			validation.Field(&state.Options, validation.Each(validation.By(validFlowOption(states)))),
+			validation.Field(&state.Input,
+				validation.In(domainFlow.InputText, domainFlow.InputNumber),
+				validation.When(len(state.Options) > 0, validation.Empty.Error("must be blank for states with options")),
+			),
+			validation.Field(&state.Pattern, validation.By(validRegex)),
+			validation.Field(&state.Next, validation.By(flowStateExists(states))),
+		)
+		if err != nil {
+			errs[name] = err
+		}
+	}
+	return errs.Filter()
+}
+
+func validFlowOption(states map[string]domainFlow.State) validation.RuleFunc {
+	return func(value any) error {
+		option, _ := value.(domainFlow.Option)
+		if option.Input == "" {
+			return errors.New("input cannot be blank")
+		}
+		if option.Next == "" {
+			return errors.New("next cannot be blank")
+		}
+		return flowStateExists(states)(option.Next)
+	}
+}
+
+func flowStateExists(states map[string]domainFlow.State) validation.RuleFunc {
+	return func(value any) error {
+		name, _ := value.(string)
+		if name == "" {
+			return nil
+		}
+		if _, ok := states[name]; !ok {
+			return fmt.Errorf("state %q does not exist", name)
+		}
+		return nil
+	}
+}
+
+func ValidateFlowSessionFilter(ctx context.Context, filter domainFlow.SessionFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(
+			domainFlow.SessionActive, domainFlow.SessionHandoff, domainFlow.SessionCompleted,
+			domainFlow.SessionExpired, domainFlow.SessionReleased,
+		)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
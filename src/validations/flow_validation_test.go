@@ -0,0 +1,77 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainFlow "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/flow"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFlow(t *testing.T) {
+	menu := map[string]domainFlow.State{
+		"main": {Prompt: "Reply 1 for billing", Options: []domainFlow.Option{{Input: "1", Next: "billing"}}},
+		"billing": {Prompt: "Send your invoice number", Input: domainFlow.InputNumber, SaveAs: "invoice",
+			Next: "done"},
+		"done": {Prompt: "Thanks"},
+	}
+
+	type args struct {
+		flow domainFlow.Flow
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with a menu",
+			args: args{flow: domainFlow.Flow{ID: "support", Triggers: []string{"menu"}, Start: "main", States: menu}},
+			err:  nil,
+		},
+		{
+			name: "should error with invalid id",
+			args: args{flow: domainFlow.Flow{ID: "support menu", Triggers: []string{"menu"}, Start: "main", States: menu}},
+			err:  pkgError.ValidationError("id: must only contain letters, digits, - and _."),
+		},
+		{
+			name: "should error without triggers",
+			args: args{flow: domainFlow.Flow{ID: "support", Start: "main", States: menu}},
+			err:  pkgError.ValidationError("triggers: cannot be blank."),
+		},
+		{
+			name: "should error with unknown start state",
+			args: args{flow: domainFlow.Flow{ID: "support", Triggers: []string{"menu"}, Start: "welcome", States: menu}},
+			err:  pkgError.ValidationError(`start: state "welcome" does not exist.`),
+		},
+		{
+			name: "should error with option to unknown state",
+			args: args{flow: domainFlow.Flow{ID: "support", Triggers: []string{"menu"}, Start: "main", States: map[string]domainFlow.State{
+				"main": {Options: []domainFlow.Option{{Input: "1", Next: "billing"}}},
+			}}},
+			err: pkgError.ValidationError(`states: (main: (options: (0: state "billing" does not exist.).).).`),
+		},
+		{
+			name: "should error with unknown input type",
+			args: args{flow: domainFlow.Flow{ID: "support", Triggers: []string{"menu"}, Start: "main", States: map[string]domainFlow.State{
+				"main": {Input: "date"},
+			}}},
+			err: pkgError.ValidationError("states: (main: (input: must be a valid value.).)."),
+		},
+		{
+			name: "should error with invalid pattern",
+			args: args{flow: domainFlow.Flow{ID: "support", Triggers: []string{"menu"}, Start: "main", States: map[string]domainFlow.State{
+				"main": {Input: domainFlow.InputText, Pattern: "("},
+			}}},
+			err: pkgError.ValidationError("states: (main: (pattern: must be a valid regular expression.).)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFlow(context.Background(), tt.args.flow)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
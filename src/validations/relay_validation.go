@@ -0,0 +1,52 @@
+package validations
+
+import (
+	"context"
+	"errors"
+
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateRelayRule(ctx context.Context, request domainRelay.RuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.SourceAccountID, validation.Required),
+		validation.Field(&request.DestinationChatJID, validation.Required, validation.By(relayDestinationDiffers(request))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// relayDestinationDiffers rejects rules relaying a chat into itself
+func relayDestinationDiffers(request domainRelay.RuleRequest) validation.RuleFunc {
+	return func(value any) error {
+		destinationAccount := request.DestinationAccountID
+		if destinationAccount == "" {
+			destinationAccount = request.SourceAccountID
+		}
+		if request.SourceChatJID != "" && request.SourceChatJID == request.DestinationChatJID &&
+			destinationAccount == request.SourceAccountID {
+			return errors.New("must differ from the source chat")
+		}
+		return nil
+	}
+}
+
+func ValidateRelayMessageFilter(ctx context.Context, filter domainRelay.MessageFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainRelay "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/relay"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRelayRule(t *testing.T) {
+	type args struct {
+		request domainRelay.RuleRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success relaying an account to a group",
+			args: args{request: domainRelay.RuleRequest{Name: "support", SourceAccountID: "default", DestinationChatJID: "120363025@g.us"}},
+			err:  nil,
+		},
+		{
+			name: "should success relaying a chat to the same chat of another account",
+			args: args{request: domainRelay.RuleRequest{Name: "mirror", SourceAccountID: "default", SourceChatJID: "120363025@g.us", DestinationAccountID: "backup", DestinationChatJID: "120363025@g.us"}},
+			err:  nil,
+		},
+		{
+			name: "should error without source account",
+			args: args{request: domainRelay.RuleRequest{Name: "support", DestinationChatJID: "120363025@g.us"}},
+			err:  pkgError.ValidationError("source_account_id: cannot be blank."),
+		},
+		{
+			name: "should error without destination chat",
+			args: args{request: domainRelay.RuleRequest{Name: "support", SourceAccountID: "default"}},
+			err:  pkgError.ValidationError("destination_chat_jid: cannot be blank."),
+		},
+		{
+			name: "should error relaying a chat into itself",
+			args: args{request: domainRelay.RuleRequest{Name: "loop", SourceAccountID: "default", SourceChatJID: "120363025@g.us", DestinationChatJID: "120363025@g.us"}},
+			err:  pkgError.ValidationError("destination_chat_jid: must differ from the source chat."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRelayRule(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateScheduleRequest(ctx context.Context, request domainSchedule.CreateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Type, validation.Required, validation.In(
+			domainSend.TypeText, domainSend.TypeImage, domainSend.TypeVideo, domainSend.TypeAudio, domainSend.TypeFile, domainSend.TypeSticker,
+			domainSend.TypeContact, domainSend.TypeLink, domainSend.TypeLocation, domainSend.TypePoll,
+		)),
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.SendAt, validation.Required, validation.By(validScheduleTime)),
+		validation.Field(&request.MissedPolicy, validation.In(domainSchedule.MissedFire, domainSchedule.MissedSkip)),
+		validation.Field(&request.Payload, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateScheduleUpdate(ctx context.Context, request domainSchedule.UpdateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.SendAt, validation.By(validScheduleTime)),
+		validation.Field(&request.MissedPolicy, validation.In(domainSchedule.MissedFire, domainSchedule.MissedSkip)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validScheduleTime(value any) error {
+	sendAt, _ := value.(string)
+	if sendAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, sendAt); err != nil {
+		return errors.New("must be an RFC 3339 time like 2026-10-20T09:00:00+07:00")
+	}
+	return nil
+}
+
+func ValidateScheduleFilter(ctx context.Context, filter domainSchedule.Filter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(
+			domainSchedule.StatusScheduled, domainSchedule.StatusSent, domainSchedule.StatusFailed,
+			domainSchedule.StatusSkipped, domainSchedule.StatusCompleted, domainSchedule.StatusCancelled,
+		)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
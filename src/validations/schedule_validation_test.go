@@ -0,0 +1,89 @@
+package validations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScheduleRequest(t *testing.T) {
+	payload := json.RawMessage(`{"message": "Standup in 10 minutes"}`)
+
+	type args struct {
+		request domainSchedule.CreateRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with weekly recurrence",
+			args: args{request: domainSchedule.CreateRequest{Type: "text", Phone: "6281234567890", SendAt: "2026-10-19T09:00:00+07:00", Recurrence: "FREQ=WEEKLY;BYDAY=MO", Payload: payload}},
+			err:  nil,
+		},
+		{
+			name: "should error with time without offset",
+			args: args{request: domainSchedule.CreateRequest{Type: "text", Phone: "6281234567890", SendAt: "2026-10-19 09:00", Payload: payload}},
+			err:  pkgError.ValidationError("send_at: must be an RFC 3339 time like 2026-10-20T09:00:00+07:00."),
+		},
+		{
+			name: "should error with unknown missed policy",
+			args: args{request: domainSchedule.CreateRequest{Type: "text", Phone: "6281234567890", SendAt: "2026-10-19T09:00:00Z", MissedPolicy: "retry", Payload: payload}},
+			err:  pkgError.ValidationError("missed_policy: must be a valid value."),
+		},
+		{
+			name: "should error with unknown type",
+			args: args{request: domainSchedule.CreateRequest{Type: "document", Phone: "6281234567890", SendAt: "2026-10-19T09:00:00Z", Payload: payload}},
+			err:  pkgError.ValidationError("type: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScheduleRequest(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateScheduleUpdate(t *testing.T) {
+	skip := "skip"
+	retry := "retry"
+
+	type args struct {
+		request domainSchedule.UpdateRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with missed policy only",
+			args: args{request: domainSchedule.UpdateRequest{MissedPolicy: &skip}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown missed policy",
+			args: args{request: domainSchedule.UpdateRequest{MissedPolicy: &retry}},
+			err:  pkgError.ValidationError("missed_policy: must be a valid value."),
+		},
+		{
+			name: "should error with invalid send_at",
+			args: args{request: domainSchedule.UpdateRequest{SendAt: "tomorrow"}},
+			err:  pkgError.ValidationError("send_at: must be an RFC 3339 time like 2026-10-20T09:00:00+07:00."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScheduleUpdate(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateScript(ctx context.Context, request domainScript.ScriptRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Events, validation.Required, validation.Each(validation.In(
+			domainScript.EventMessage, domainScript.EventReceipt, domainScript.EventGroup,
+		))),
+		validation.Field(&request.Source, validation.Required, validation.By(validScriptSource(request.Events))),
+		validation.Field(&request.TimeoutMs, validation.Min(0), validation.Max(int(domainScript.MaxTimeout.Milliseconds()))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// validScriptSource compiles the script and checks it handles every event it subscribes to
+func validScriptSource(events []string) validation.RuleFunc {
+	return func(value any) error {
+		source, _ := value.(string)
+		if source == "" {
+			return nil
+		}
+
+		program, err := domainScript.Compile("script", source)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			handler, ok := domainScript.Handlers[event]
+			if ok && !program.Defines(handler) {
+				return fmt.Errorf("must define %s(event) for %s events", handler, event)
+			}
+		}
+		return nil
+	}
+}
+
+func ValidateScriptErrorFilter(ctx context.Context, filter domainScript.ErrorFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(domainScript.MaxErrorsKept)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateChatTagFilter(ctx context.Context, filter domainScript.ChatTagFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainScript "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/script"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScript(t *testing.T) {
+	source := "def on_message(event):\n    reply('hi')\n"
+
+	type args struct {
+		request domainScript.ScriptRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with a message handler",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", AccountID: "default", Events: []string{"message"}, Source: source}},
+			err:  nil,
+		},
+		{
+			name: "should error without account",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", Events: []string{"message"}, Source: source}},
+			err:  pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name: "should error with unknown event",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", AccountID: "default", Events: []string{"presence"}, Source: source}},
+			err:  pkgError.ValidationError("events: (0: must be a valid value.)."),
+		},
+		{
+			name: "should error without handler for an event",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", AccountID: "default", Events: []string{"message", "group"}, Source: source}},
+			err:  pkgError.ValidationError("source: must define on_group(event) for group events."),
+		},
+		{
+			name: "should error with syntax error",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", AccountID: "default", Events: []string{"message"}, Source: "def on_message(event)\n"}},
+			err:  pkgError.ValidationError("source: script.star:2:1: got newline, want ':'."),
+		},
+		{
+			name: "should error with timeout above the maximum",
+			args: args{request: domainScript.ScriptRequest{Name: "greeter", AccountID: "default", Events: []string{"message"}, Source: source, TimeoutMs: 60000}},
+			err:  pkgError.ValidationError("timeout_ms: must be no greater than 30000."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScript(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
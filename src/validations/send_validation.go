@@ -2,8 +2,11 @@ package validations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
@@ -11,6 +14,7 @@ import (
 	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // maxDuration represents the maximum allowed duration in seconds (uint32 max).
@@ -49,6 +53,85 @@ func validatePhoneNumber(phone string) error {
 	return nil
 }
 
+// validRecipientPhone is validatePhoneNumber as a rule for each phone of a list
+func validRecipientPhone(value any) error {
+	phone, _ := value.(string)
+	if err := validatePhoneNumber(phone); err != nil {
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+// validateMentionEveryone checks that mention_everyone is only used for messages to a group
+func validateMentionEveryone(base domainSend.BaseRequest) error {
+	if base.MentionEveryone && !strings.HasSuffix(base.Phone, "@"+types.GroupServer) {
+		return pkgError.ValidationError("mention_everyone: only messages to a group can mention everyone")
+	}
+	return nil
+}
+
+// validateMediaSource checks that exactly one source was given for a media send. file and url name the upload and
+// URL fields of the request.
+func validateMediaSource(source domainSend.MediaSource, file, url string) error {
+	switch source.Count() {
+	case 0:
+		return pkgError.ValidationError(fmt.Sprintf("either %s, %s, base64 or from_message_id must be provided", file, url))
+	case 1:
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("only one of %s, %s, base64 or from_message_id can be provided", file, url))
+	}
+
+	if source.Base64 != "" {
+		if _, _, err := domainSend.ParseDataURI(source.Base64); err != nil {
+			return pkgError.ValidationError("base64: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// mediaMimes lists the MIME types each kind of media can be sent as, documents accept any type
+var mediaMimes = map[domainSend.MediaKind][]string{
+	domainSend.MediaImage:   {"image/jpeg", "image/jpg", "image/png", "image/webp"},
+	domainSend.MediaSticker: {"image/jpeg", "image/jpg", "image/png", "image/webp", "image/gif"},
+	domainSend.MediaVideo:   {"video/mp4", "video/x-matroska", "video/avi", "video/x-msvideo"},
+	domainSend.MediaAudio: {
+		"audio/aac", "audio/amr", "audio/flac", "audio/m4a", "audio/m4r", "audio/mp3", "audio/mp4", "audio/mpeg",
+		"audio/ogg", "audio/wma", "audio/x-ms-wma", "audio/wav", "audio/vnd.wav", "audio/vnd.wave", "audio/wave",
+		"audio/x-pn-wav", "audio/x-wav",
+	},
+}
+
+// mediaMaxSize returns the largest media of the given kind that can be sent
+func mediaMaxSize(kind domainSend.MediaKind) int64 {
+	switch kind {
+	case domainSend.MediaImage, domainSend.MediaSticker:
+		return config.WhatsappSettingMaxImageSize
+	case domainSend.MediaVideo:
+		return config.WhatsappSettingMaxVideoSize
+	default:
+		return config.WhatsappSettingMaxFileSize
+	}
+}
+
+// ValidateMedia checks the size and MIME type of a resolved media source, whichever source it came from
+func ValidateMedia(kind domainSend.MediaKind, media domainSend.Media) error {
+	if len(media.Data) == 0 {
+		return pkgError.ValidationError(fmt.Sprintf("%s: the media is empty", kind))
+	}
+
+	if maxSize := mediaMaxSize(kind); int64(len(media.Data)) > maxSize {
+		return pkgError.ValidationError(fmt.Sprintf("%s: max size is %s, the media is %s", kind,
+			humanize.Bytes(uint64(maxSize)), humanize.Bytes(uint64(len(media.Data)))))
+	}
+
+	if mimes, ok := mediaMimes[kind]; ok && !slices.Contains(mimes, media.MimeType) {
+		return pkgError.ValidationError(fmt.Sprintf("%s: %s is not allowed, please use %s", kind, media.MimeType, strings.Join(mimes, ", ")))
+	}
+
+	return nil
+}
+
 func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -68,6 +151,10 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
+
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -85,8 +172,8 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
-	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") {
-		return pkgError.ValidationError("either Image or ImageURL must be provided")
+	if err := validateMediaSource(request.MediaSource(), "Image", "ImageURL"); err != nil {
+		return err
 	}
 
 	if request.Image != nil {
@@ -117,6 +204,10 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -134,16 +225,15 @@ func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest)
 		return err
 	}
 
-	// Either Sticker or StickerURL must be provided
-	if request.Sticker == nil && (request.StickerURL == nil || *request.StickerURL == "") {
-		return pkgError.ValidationError("either Sticker or StickerURL must be provided")
-	}
-
 	// Both cannot be provided at the same time
 	if request.Sticker != nil && request.StickerURL != nil && *request.StickerURL != "" {
 		return pkgError.ValidationError("cannot provide both Sticker file and StickerURL")
 	}
 
+	if err := validateMediaSource(request.MediaSource(), "Sticker", "StickerURL"); err != nil {
+		return err
+	}
+
 	// Validate file type if sticker file is provided
 	if request.Sticker != nil {
 		availableMimes := map[string]bool{
@@ -172,13 +262,16 @@ func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest)
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.File, validation.Required),
 	)
 
 	if err != nil {
@@ -190,15 +283,33 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
-	if request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
+	if err := validateMediaSource(request.MediaSource(), "File", "FileURL"); err != nil {
+		return err
+	}
+
+	if request.File != nil && request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
 		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
 		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
 	}
 
+	if request.FileURL != nil {
+		if *request.FileURL == "" {
+			return pkgError.ValidationError("FileURL cannot be empty")
+		}
+
+		if err := validation.Validate(*request.FileURL, is.URL); err != nil {
+			return pkgError.ValidationError("FileURL must be a valid URL")
+		}
+	}
+
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -217,9 +328,9 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
-	// Ensure at least one of Video or VideoURL is provided
-	if request.Video == nil && (request.VideoURL == nil || *request.VideoURL == "") {
-		return pkgError.ValidationError("either Video or VideoURL must be provided")
+	// Ensure exactly one video source is provided
+	if err := validateMediaSource(request.MediaSource(), "Video", "VideoURL"); err != nil {
+		return err
 	}
 
 	// If Video file provided perform MIME / size validation
@@ -256,6 +367,62 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateSendAlbum(ctx context.Context, request domainSend.AlbumRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Items, validation.Required, validation.Length(domainSend.MinAlbumItems, domainSend.MaxAlbumItems)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	// Custom validation for phone number format
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	for i, item := range request.Items {
+		if err := validateAlbumItem(i, item); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("items.%d: %s", i, err.Error()))
+		}
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateAlbumItem checks the type and media source of the item at index, the size and MIME type are checked
+// once the media is resolved
+func validateAlbumItem(index int, item domainSend.AlbumItem) error {
+	if item.Type != string(domainSend.MediaImage) && item.Type != string(domainSend.MediaVideo) {
+		return errors.New("type must be image or video")
+	}
+
+	if err := validateMediaSource(item.MediaSource(), fmt.Sprintf("media_%d", index), "url"); err != nil {
+		return err
+	}
+
+	if item.URL != nil {
+		if err := validation.Validate(*item.URL, validation.Required, is.URL); err != nil {
+			return errors.New("url must be a valid URL")
+		}
+	}
+
 	return nil
 }
 
@@ -284,6 +451,10 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -307,6 +478,10 @@ func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -330,6 +505,10 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -347,9 +526,9 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
-	// Ensure at least one of Audio or AudioURL is provided
-	if request.Audio == nil && (request.AudioURL == nil || *request.AudioURL == "") {
-		return pkgError.ValidationError("either Audio or AudioURL must be provided")
+	// Ensure exactly one audio source is provided
+	if err := validateMediaSource(request.MediaSource(), "Audio", "AudioURL"); err != nil {
+		return err
 	}
 
 	// If Audio file is provided, validate file MIME
@@ -405,6 +584,10 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -438,6 +621,10 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		return err
 	}
 
+	if err := validateMentionEveryone(request.BaseRequest); err != nil {
+		return err
+	}
+
 	// validate options should be unique each other
 	uniqueOptions := make(map[string]bool)
 	for _, option := range request.Options {
@@ -450,6 +637,63 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+func ValidateSendRelay(ctx context.Context, request domainSend.RelayRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Text, validation.When(request.Media == nil, validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	// Custom validation for phone number format
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	if request.Media != nil {
+		maxSize := config.WhatsappSettingMaxFileSize
+		switch request.Media.Type {
+		case domainSend.RelayMediaImage, domainSend.RelayMediaSticker:
+			maxSize = config.WhatsappSettingMaxImageSize
+		case domainSend.RelayMediaVideo:
+			maxSize = config.WhatsappSettingMaxVideoSize
+		case domainSend.RelayMediaAudio, domainSend.RelayMediaDocument:
+		default:
+			return pkgError.ValidationError(fmt.Sprintf("media type %q cannot be relayed", request.Media.Type))
+		}
+		if len(request.Media.Data) == 0 {
+			return pkgError.ValidationError("media cannot be empty")
+		}
+		if int64(len(request.Media.Data)) > maxSize {
+			return pkgError.ValidationError(fmt.Sprintf("max %s size is %s", request.Media.Type, humanize.Bytes(uint64(maxSize))))
+		}
+		if request.Text != "" && (request.Media.Type == domainSend.RelayMediaAudio || request.Media.Type == domainSend.RelayMediaSticker) {
+			return pkgError.ValidationError(fmt.Sprintf("%s cannot have a caption", request.Media.Type))
+		}
+	}
+
+	return validateDuration(request.Duration)
+}
+
+func ValidateForwardMessage(ctx context.Context, request domainSend.ForwardRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Phones,
+			validation.Required,
+			validation.Length(1, domainSend.MaxForwardTargets),
+			validation.Each(validation.By(validRecipientPhone)),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validateDuration(request.Duration)
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
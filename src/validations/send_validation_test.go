@@ -50,6 +50,28 @@ func TestValidateSendMessage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("message: cannot be blank."),
 		},
+		{
+			name: "should success with mention everyone in a group",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone:           "120363025246125486@g.us",
+					MentionEveryone: true,
+				},
+				Message: "Meeting in 5 minutes",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with mention everyone outside a group",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone:           "1728937129312@s.whatsapp.net",
+					MentionEveryone: true,
+				},
+				Message: "Meeting in 5 minutes",
+			}},
+			err: pkgError.ValidationError("mention_everyone: only messages to a group can mention everyone"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -104,7 +126,7 @@ func TestValidateSendImage(t *testing.T) {
 				},
 				Image: nil,
 			}},
-			err: pkgError.ValidationError("either Image or ImageURL must be provided"),
+			err: pkgError.ValidationError("either Image, ImageURL, base64 or from_message_id must be provided"),
 		},
 		{
 			name: "should error with invalid image type",
@@ -120,6 +142,47 @@ func TestValidateSendImage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png"),
 		},
+		{
+			name: "should success with base64",
+			args: args{request: domainSend.ImageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				MediaInput: domainSend.MediaInput{Base64: "data:image/png;base64,iVBORw0KGgo="},
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with from_message_id",
+			args: args{request: domainSend.ImageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				MediaInput: domainSend.MediaInput{FromMessageID: "3EB0C127D7BACC83D6A1"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with two sources",
+			args: args{request: domainSend.ImageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Image:      image,
+				MediaInput: domainSend.MediaInput{FromMessageID: "3EB0C127D7BACC83D6A1"},
+			}},
+			err: pkgError.ValidationError("only one of Image, ImageURL, base64 or from_message_id can be provided"),
+		},
+		{
+			name: "should error with base64 that is not a data URI",
+			args: args{request: domainSend.ImageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				MediaInput: domainSend.MediaInput{Base64: "iVBORw0KGgo="},
+			}},
+			err: pkgError.ValidationError("base64: must be a data URI like data:image/png;base64,..."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,7 +236,27 @@ func TestValidateSendFile(t *testing.T) {
 				},
 				File: nil,
 			}},
-			err: pkgError.ValidationError("file: cannot be blank."),
+			err: pkgError.ValidationError("either File, FileURL, base64 or from_message_id must be provided"),
+		},
+		{
+			name: "should success with file URL",
+			args: args{request: domainSend.FileRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				FileURL: func() *string { s := "https://example.com/report.pdf"; return &s }(),
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid file URL",
+			args: args{request: domainSend.FileRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				FileURL: func() *string { s := "not-a-url"; return &s }(),
+			}},
+			err: pkgError.ValidationError("FileURL must be a valid URL"),
 		},
 	}
 
@@ -237,7 +320,7 @@ func TestValidateSendVideo(t *testing.T) {
 				ViewOnce: false,
 				Compress: false,
 			}},
-			err: pkgError.ValidationError("either Video or VideoURL must be provided"),
+			err: pkgError.ValidationError("either Video, VideoURL, base64 or from_message_id must be provided"),
 		},
 		{
 			name: "should error with invalid format video",
@@ -270,7 +353,7 @@ func TestValidateSendVideo(t *testing.T) {
 				ViewOnce: false,
 				Compress: false,
 			}},
-			err: pkgError.ValidationError("either Video or VideoURL must be provided"),
+			err: pkgError.ValidationError("either Video, VideoURL, base64 or from_message_id must be provided"),
 		},
 		{
 			name: "should success with video_url provided",
@@ -659,7 +742,7 @@ func TestValidateSendAudio(t *testing.T) {
 				},
 				Audio: nil,
 			}},
-			err: pkgError.ValidationError("either Audio or AudioURL must be provided"),
+			err: pkgError.ValidationError("either Audio, AudioURL, base64 or from_message_id must be provided"),
 		},
 		{
 			name: "should error with invalid audio type",
@@ -875,6 +958,152 @@ func TestValidateSendChatPresence(t *testing.T) {
 	}
 }
 
+func TestValidateSendAlbum(t *testing.T) {
+	imageURL := "https://example.com/photo.jpg"
+	invalidURL := "not a url"
+	type args struct {
+		request domainSend.AlbumRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success normal condition",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net"},
+				Items: []domainSend.AlbumItem{
+					{Type: "image", Caption: "first", URL: &imageURL},
+					{Type: "video", File: &multipart.FileHeader{Filename: "clip.mp4"}},
+					{Type: "image", MediaInput: domainSend.MediaInput{FromMessageID: "3EB0C127D7BACC83D6A1"}},
+				},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainSend.AlbumRequest{
+				Items: []domainSend.AlbumItem{{Type: "image", URL: &imageURL}, {Type: "image", URL: &imageURL}},
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+		{
+			name: "should error with a single item",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net"},
+				Items:       []domainSend.AlbumItem{{Type: "image", URL: &imageURL}},
+			}},
+			err: pkgError.ValidationError("items: the length must be between 2 and 30."),
+		},
+		{
+			name: "should error with unsupported item type",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net"},
+				Items:       []domainSend.AlbumItem{{Type: "image", URL: &imageURL}, {Type: "audio", URL: &imageURL}},
+			}},
+			err: pkgError.ValidationError("items.1: type must be image or video"),
+		},
+		{
+			name: "should error with item without media",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net"},
+				Items:       []domainSend.AlbumItem{{Type: "image", URL: &imageURL}, {Type: "image"}},
+			}},
+			err: pkgError.ValidationError("items.1: either media_1, url, base64 or from_message_id must be provided"),
+		},
+		{
+			name: "should error with invalid item url",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net"},
+				Items:       []domainSend.AlbumItem{{Type: "image", URL: &invalidURL}, {Type: "image", URL: &imageURL}},
+			}},
+			err: pkgError.ValidationError("items.0: url must be a valid URL"),
+		},
+		{
+			name: "should error with mention everyone outside a group",
+			args: args{request: domainSend.AlbumRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129@s.whatsapp.net", MentionEveryone: true},
+				Items:       []domainSend.AlbumItem{{Type: "image", URL: &imageURL}, {Type: "image", URL: &imageURL}},
+			}},
+			err: pkgError.ValidationError("mention_everyone: only messages to a group can mention everyone"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendAlbum(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateForwardMessage(t *testing.T) {
+	type args struct {
+		request domainSend.ForwardRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success normal condition",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0C127D7BACC83D6A1",
+				Phones:    []string{"6289685028129@s.whatsapp.net", "120363024512399999@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message id",
+			args: args{request: domainSend.ForwardRequest{
+				Phones: []string{"6289685028129@s.whatsapp.net"},
+			}},
+			err: pkgError.ValidationError("message_id: cannot be blank."),
+		},
+		{
+			name: "should error without phones",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0C127D7BACC83D6A1",
+			}},
+			err: pkgError.ValidationError("phones: cannot be blank."),
+		},
+		{
+			name: "should error with local phone format",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0C127D7BACC83D6A1",
+				Phones:    []string{"6289685028129@s.whatsapp.net", "089685028129@s.whatsapp.net"},
+			}},
+			err: pkgError.ValidationError("phones: (1: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx.)."),
+		},
+		{
+			name: "should error with too many phones",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0C127D7BACC83D6A1",
+				Phones:    make([]string, domainSend.MaxForwardTargets+1),
+			}},
+			err: pkgError.ValidationError("phones: the length must be between 1 and 50."),
+		},
+		{
+			name: "should error with negative duration",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0C127D7BACC83D6A1",
+				Phones:    []string{"6289685028129@s.whatsapp.net"},
+				Duration:  func() *int { d := -1; return &d }(),
+			}},
+			err: pkgError.ValidationError("duration must be between 0 and 4294967295 seconds (0 means no expiry)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateForwardMessage(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateDuration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -991,7 +1220,7 @@ func TestValidateSendImage_WithImageURL(t *testing.T) {
 				Caption:  "Hello this is testing",
 				ImageURL: func() *string { s := ""; return &s }(),
 			}},
-			err: pkgError.ValidationError("either Image or ImageURL must be provided"),
+			err: pkgError.ValidationError("either Image, ImageURL, base64 or from_message_id must be provided"),
 		},
 	}
 
@@ -1106,3 +1335,43 @@ func TestValidateSendAudio_WithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMedia(t *testing.T) {
+	type args struct {
+		kind  domainSend.MediaKind
+		media domainSend.Media
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with png image",
+			args: args{kind: domainSend.MediaImage, media: domainSend.Media{Data: []byte("png"), MimeType: "image/png"}},
+			err:  nil,
+		},
+		{
+			name: "should success with any document type",
+			args: args{kind: domainSend.MediaDocument, media: domainSend.Media{Data: []byte("zip"), MimeType: "application/zip"}},
+			err:  nil,
+		},
+		{
+			name: "should error with empty media",
+			args: args{kind: domainSend.MediaAudio, media: domainSend.Media{MimeType: "audio/ogg"}},
+			err:  pkgError.ValidationError("audio: the media is empty"),
+		},
+		{
+			name: "should error with video sent as image",
+			args: args{kind: domainSend.MediaImage, media: domainSend.Media{Data: []byte("mp4"), MimeType: "video/mp4"}},
+			err:  pkgError.ValidationError("image: video/mp4 is not allowed, please use image/jpeg, image/jpg, image/png, image/webp"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMedia(tt.args.kind, tt.args.media)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
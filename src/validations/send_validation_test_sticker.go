@@ -61,7 +61,7 @@ func TestValidateSendSticker(t *testing.T) {
 					BaseRequest: domainSend.BaseRequest{Phone: "+6289123456"},
 				},
 			},
-			err: pkgError.ValidationError("either Sticker or StickerURL must be provided"),
+			err: pkgError.ValidationError("either Sticker, StickerURL, base64 or from_message_id must be provided"),
 		},
 		{
 			name: "should error with both sticker and sticker_url",
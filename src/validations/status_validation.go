@@ -0,0 +1,82 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateStatusPost(ctx context.Context, request domainStatus.PostRequest) error {
+	isText := request.Type == domainStatus.TypeText
+	isList := request.Audience == domainStatus.AudienceList
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Type, validation.Required, validation.In(domainStatus.TypeText, domainStatus.TypeImage, domainStatus.TypeVideo)),
+		validation.Field(&request.Text, validation.When(isText, validation.Required).Else(validation.Empty.Error("only text statuses have text, use caption"))),
+		validation.Field(&request.Caption, validation.When(isText, validation.Empty.Error("text statuses have no caption"))),
+		validation.Field(&request.BackgroundColor, validation.When(!isText, validation.Empty.Error("only text statuses have colors")), validation.By(validStatusColor)),
+		validation.Field(&request.TextColor, validation.When(!isText, validation.Empty.Error("only text statuses have colors")), validation.By(validStatusColor)),
+		validation.Field(&request.Font, validation.When(!isText, validation.Empty.Error("only text statuses have a font")), validation.By(validStatusFont)),
+		validation.Field(&request.Audience, validation.In(domainStatus.AudienceContacts, domainStatus.AudienceList)),
+		validation.Field(&request.Phones,
+			validation.When(isList, validation.Required, validation.Length(1, domainStatus.MaxAudience), validation.Each(validation.By(validRecipientPhone))).
+				Else(validation.Empty.Error("only a list audience takes phones")),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if isText {
+		if request.MediaSource().Count() > 0 {
+			return pkgError.ValidationError("text statuses have no media")
+		}
+		return nil
+	}
+	return validateMediaSource(request.MediaSource(), "media", "media_url")
+}
+
+func validStatusColor(value any) error {
+	color, _ := value.(string)
+	if color == "" {
+		return nil
+	}
+	_, err := domainStatus.ParseColor(color)
+	return err
+}
+
+func validStatusFont(value any) error {
+	font, _ := value.(string)
+	if font == "" {
+		return nil
+	}
+	if _, ok := domainStatus.Fonts[font]; !ok {
+		names := make([]string, 0, len(domainStatus.Fonts))
+		for name := range domainStatus.Fonts {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return fmt.Errorf("must be one of %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func ValidateStatusFilter(ctx context.Context, filter domainStatus.Filter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Type, validation.In(domainStatus.TypeText, domainStatus.TypeImage, domainStatus.TypeVideo)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainStatus "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/status"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStatusPost(t *testing.T) {
+	imageURL := "https://example.com/promo.jpg"
+
+	type args struct {
+		request domainStatus.PostRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with text status",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Open until 9 tonight", BackgroundColor: "#25D366", TextColor: "#FFFFFFFF", Font: "calistoga"}},
+			err:  nil,
+		},
+		{
+			name: "should success with image status to a list",
+			args: args{request: domainStatus.PostRequest{Type: "image", MediaURL: &imageURL, Caption: "New stock", Audience: "list", Phones: []string{"6281234567890"}}},
+			err:  nil,
+		},
+		{
+			name: "should success with video status from a stored message",
+			args: args{request: domainStatus.PostRequest{Type: "video", MediaInput: domainSend.MediaInput{FromMessageID: "3EB0C127D7BACC83D6A1"}}},
+			err:  nil,
+		},
+		{
+			name: "should error with text status without text",
+			args: args{request: domainStatus.PostRequest{Type: "text"}},
+			err:  pkgError.ValidationError("text: cannot be blank."),
+		},
+		{
+			name: "should error with invalid color",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", BackgroundColor: "green"}},
+			err:  pkgError.ValidationError("background_color: must be a color like #RRGGBB or #AARRGGBB."),
+		},
+		{
+			name: "should error with unknown font",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", Font: "comic_sans"}},
+			err:  pkgError.ValidationError("font: must be one of calistoga, courier_prime, exo2, fb_script, morning_breeze, system, system_bold, system_text."),
+		},
+		{
+			name: "should error with text status with media",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", MediaURL: &imageURL}},
+			err:  pkgError.ValidationError("text statuses have no media"),
+		},
+		{
+			name: "should error with image status without media",
+			args: args{request: domainStatus.PostRequest{Type: "image", Caption: "New stock"}},
+			err:  pkgError.ValidationError("either media, media_url, base64 or from_message_id must be provided"),
+		},
+		{
+			name: "should error with colors on image status",
+			args: args{request: domainStatus.PostRequest{Type: "image", MediaURL: &imageURL, BackgroundColor: "#25D366"}},
+			err:  pkgError.ValidationError("background_color: only text statuses have colors."),
+		},
+		{
+			name: "should error with list audience without phones",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", Audience: "list"}},
+			err:  pkgError.ValidationError("phones: cannot be blank."),
+		},
+		{
+			name: "should error with phones for contacts audience",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", Phones: []string{"6281234567890"}}},
+			err:  pkgError.ValidationError("phones: only a list audience takes phones."),
+		},
+		{
+			name: "should error with local phone in list",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", Audience: "list", Phones: []string{"081234567890"}}},
+			err:  pkgError.ValidationError("phones: (0: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx.)."),
+		},
+		{
+			name: "should error with unknown audience",
+			args: args{request: domainStatus.PostRequest{Type: "text", Text: "Hello", Audience: "friends"}},
+			err:  pkgError.ValidationError("audience: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStatusPost(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
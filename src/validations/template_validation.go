@@ -0,0 +1,101 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// templateLanguagePattern matches language tags like en, pt-BR or zh-Hant
+var templateLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+func ValidateTemplate(ctx context.Context, request domainTemplate.TemplateRequest) error {
+	types := make([]any, 0, len(domainTemplate.Types))
+	for _, templateType := range domainTemplate.Types {
+		types = append(types, templateType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Type, validation.Required, validation.In(types...)),
+		validation.Field(&request.DefaultLanguage, validation.Match(templateLanguagePattern), validation.By(func(value any) error {
+			language, _ := value.(string)
+			for _, variant := range request.Variants {
+				if language == "" || strings.EqualFold(variant.Language, language) {
+					return nil
+				}
+			}
+			return errors.New("must be the language of one of the variants")
+		})),
+		validation.Field(&request.Variants,
+			validation.Required,
+			validation.By(uniqueTemplateLanguages),
+			validation.Each(validation.By(func(value any) error {
+				variant, _ := value.(domainTemplate.Variant)
+				return validTemplateVariant(request.Type, variant)
+			})),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func uniqueTemplateLanguages(value any) error {
+	variants, _ := value.([]domainTemplate.Variant)
+	seen := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		language := strings.ToLower(variant.Language)
+		if seen[language] {
+			return fmt.Errorf("language %s is given twice", variant.Language)
+		}
+		seen[language] = true
+	}
+	return nil
+}
+
+// validTemplateVariant checks the variant has the fields its template type sends and that they parse
+func validTemplateVariant(templateType string, variant domainTemplate.Variant) error {
+	err := validation.ValidateStruct(&variant,
+		validation.Field(&variant.Language, validation.Required, validation.Match(templateLanguagePattern)),
+		validation.Field(&variant.Text, validation.When(templateType == domainSend.TypeText, validation.Required)),
+		validation.Field(&variant.MediaURL, validation.When(
+			templateType == domainSend.TypeImage || templateType == domainSend.TypeVideo, validation.Required,
+		)),
+		validation.Field(&variant.Question, validation.When(templateType == domainSend.TypePoll, validation.Required)),
+		validation.Field(&variant.Options, validation.When(templateType == domainSend.TypePoll, validation.Required), validation.Each(validation.Required)),
+	)
+	if err != nil {
+		return err
+	}
+	return variant.Check()
+}
+
+func ValidateTemplateFilter(ctx context.Context, filter domainTemplate.Filter) error {
+	types := make([]any, 0, len(domainTemplate.Types))
+	for _, templateType := range domainTemplate.Types {
+		types = append(types, templateType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Type, validation.In(types...)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	type args struct {
+		request domainTemplate.TemplateRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with language variants",
+			args: args{request: domainTemplate.TemplateRequest{Name: "Order shipped", Type: "text", DefaultLanguage: "en", Variants: []domainTemplate.Variant{
+				{Language: "en", Text: "Hi {{.name}}, your order shipped"},
+				{Language: "pt-BR", Text: "Olá {{.name}}, seu pedido foi enviado"},
+			}}},
+			err: nil,
+		},
+		{
+			name: "should error with default language without variant",
+			args: args{request: domainTemplate.TemplateRequest{Name: "Order shipped", Type: "text", DefaultLanguage: "de", Variants: []domainTemplate.Variant{
+				{Language: "en", Text: "Hi {{.name}}"},
+			}}},
+			err: pkgError.ValidationError("default_language: must be the language of one of the variants."),
+		},
+		{
+			name: "should error with duplicate language",
+			args: args{request: domainTemplate.TemplateRequest{Name: "Order shipped", Type: "text", Variants: []domainTemplate.Variant{
+				{Language: "en", Text: "Hi"},
+				{Language: "EN", Text: "Hello"},
+			}}},
+			err: pkgError.ValidationError("variants: language EN is given twice."),
+		},
+		{
+			name: "should error with image without media url",
+			args: args{request: domainTemplate.TemplateRequest{Name: "Catalog", Type: "image", Variants: []domainTemplate.Variant{
+				{Language: "en", Caption: "New arrivals"},
+			}}},
+			err: pkgError.ValidationError("variants: (0: (media_url: cannot be blank.).)."),
+		},
+		{
+			name: "should error with template syntax error",
+			args: args{request: domainTemplate.TemplateRequest{Name: "Order shipped", Type: "text", Variants: []domainTemplate.Variant{
+				{Language: "en", Text: "Hi {{.name"},
+			}}},
+			err: pkgError.ValidationError("variants: (0: text: template: text:1: unclosed action.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"path"
+	"regexp"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+var (
+	webhookEventTypes = []any{
+		domainWebhook.EventMessage, domainWebhook.EventMessageRevoked, domainWebhook.EventMessageEdited,
+		domainWebhook.EventMessageAck, domainWebhook.EventMessageDeleteForMe, domainWebhook.EventGroupParticipants,
+		domainWebhook.EventHistorySyncStarted, domainWebhook.EventHistorySyncProgress, domainWebhook.EventHistorySyncCompleted,
+		domainWebhook.EventStatusViewed, domainWebhook.EventPollVote,
+	}
+	webhookMessageTypes = []any{
+		domainWebhook.MessageTypeText, domainWebhook.MessageTypeImage, domainWebhook.MessageTypeVideo,
+		domainWebhook.MessageTypeAudio, domainWebhook.MessageTypeDocument, domainWebhook.MessageTypeSticker,
+		domainWebhook.MessageTypeContact, domainWebhook.MessageTypeLocation, domainWebhook.MessageTypeLiveLocation,
+		domainWebhook.MessageTypeList, domainWebhook.MessageTypeOrder, domainWebhook.MessageTypeReaction,
+		domainWebhook.MessageTypeRevoked, domainWebhook.MessageTypeEdited,
+	}
+)
+
+func ValidateWebhookRule(ctx context.Context, request domainWebhook.RuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.Action, validation.Required, validation.In(
+			domainWebhook.RuleActionRoute, domainWebhook.RuleActionDrop, domainWebhook.RuleActionTag,
+		)),
+		validation.Field(&request.Endpoints,
+			validation.When(request.Action == domainWebhook.RuleActionRoute, validation.Required),
+			validation.Each(validation.Required, is.URL),
+		),
+		validation.Field(&request.Tags,
+			validation.When(request.Action == domainWebhook.RuleActionTag, validation.Required),
+			validation.Each(validation.Required),
+		),
+		validation.Field(&request.Match, validation.By(func(any) error {
+			return validateRuleMatch(ctx, &request.Match)
+		})),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateRuleMatch(ctx context.Context, match *domainWebhook.RuleMatch) error {
+	return validation.ValidateStructWithContext(ctx, match,
+		validation.Field(&match.ChatType, validation.In(domainWebhook.ChatTypeGroup, domainWebhook.ChatTypePersonal)),
+		validation.Field(&match.ChatJID, validation.By(validGlob)),
+		validation.Field(&match.Sender, validation.By(validGlob)),
+		validation.Field(&match.EventTypes, validation.Each(validation.In(webhookEventTypes...))),
+		validation.Field(&match.MessageTypes, validation.Each(validation.In(webhookMessageTypes...))),
+		validation.Field(&match.ContentRegex, validation.By(validRegex)),
+	)
+}
+
+func validGlob(value any) error {
+	pattern, _ := value.(string)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.New("must be a valid glob pattern")
+	}
+	return nil
+}
+
+func validRegex(value any) error {
+	pattern, _ := value.(string)
+	if _, err := regexp.Compile(pattern); err != nil {
+		return errors.New("must be a valid regular expression")
+	}
+	return nil
+}
+
+func ValidateWebhookAction(ctx context.Context, action domainWebhook.Action) error {
+	err := validation.ValidateStructWithContext(ctx, &action,
+		validation.Field(&action.Type, validation.Required, validation.In(
+			domainWebhook.ActionReply, domainWebhook.ActionReact, domainWebhook.ActionSendMedia, domainWebhook.ActionMarkRead,
+		)),
+		validation.Field(&action.Text, validation.When(action.Type == domainWebhook.ActionReply, validation.Required)),
+		validation.Field(&action.Emoji, validation.When(action.Type == domainWebhook.ActionReact, validation.Required)),
+		validation.Field(&action.MediaType, validation.When(action.Type == domainWebhook.ActionSendMedia,
+			validation.Required,
+			validation.In(domainWebhook.MessageTypeImage, domainWebhook.MessageTypeVideo, domainWebhook.MessageTypeAudio),
+		)),
+		validation.Field(&action.URL, validation.When(action.Type == domainWebhook.ActionSendMedia, validation.Required, is.URL)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateWebhookDeliveryFilter(ctx context.Context, filter domainWebhook.DeliveryFilter) error {
+	err := validation.ValidateStructWithContext(ctx, &filter,
+		validation.Field(&filter.Status, validation.In(domainWebhook.DeliveryStatusDelivered, domainWebhook.DeliveryStatusFailed)),
+		validation.Field(&filter.Limit, validation.Min(0), validation.Max(500)),
+		validation.Field(&filter.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
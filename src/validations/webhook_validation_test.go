@@ -0,0 +1,149 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookRule(t *testing.T) {
+	type args struct {
+		request domainWebhook.RuleRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success routing groups to an endpoint",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:      "support groups",
+				Action:    domainWebhook.RuleActionRoute,
+				Endpoints: []string{"https://support.example.com/webhook"},
+				Match:     domainWebhook.RuleMatch{ChatType: domainWebhook.ChatTypeGroup, ChatJID: "1203630*@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should success dropping by content",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "spam",
+				Action: domainWebhook.RuleActionDrop,
+				Match:  domainWebhook.RuleMatch{ContentRegex: "(?i)casino", MessageTypes: []string{"text"}},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown action",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "unknown",
+				Action: "archive",
+			}},
+			err: pkgError.ValidationError("action: must be a valid value."),
+		},
+		{
+			name: "should error routing without endpoints",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "no endpoints",
+				Action: domainWebhook.RuleActionRoute,
+			}},
+			err: pkgError.ValidationError("endpoints: cannot be blank."),
+		},
+		{
+			name: "should error tagging without tags",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "no tags",
+				Action: domainWebhook.RuleActionTag,
+			}},
+			err: pkgError.ValidationError("tags: cannot be blank."),
+		},
+		{
+			name: "should error with invalid regex",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "bad regex",
+				Action: domainWebhook.RuleActionDrop,
+				Match:  domainWebhook.RuleMatch{ContentRegex: "("},
+			}},
+			err: pkgError.ValidationError("match: (content_regex: must be a valid regular expression.)."),
+		},
+		{
+			name: "should error with unknown message type",
+			args: args{request: domainWebhook.RuleRequest{
+				Name:   "bad type",
+				Action: domainWebhook.RuleActionDrop,
+				Match:  domainWebhook.RuleMatch{MessageTypes: []string{"hologram"}},
+			}},
+			err: pkgError.ValidationError("match: (message_types: (0: must be a valid value.).)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookRule(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateWebhookAction(t *testing.T) {
+	type args struct {
+		action domainWebhook.Action
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success replying with text",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionReply, Text: "Thanks, we got it", Quote: true}},
+			err:  nil,
+		},
+		{
+			name: "should success sending an image by url",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionSendMedia, MediaType: "image", URL: "https://example.com/a.png"}},
+			err:  nil,
+		},
+		{
+			name: "should success marking as read",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionMarkRead}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown type",
+			args: args{action: domainWebhook.Action{Type: "forward"}},
+			err:  pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name: "should error replying without text",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionReply}},
+			err:  pkgError.ValidationError("text: cannot be blank."),
+		},
+		{
+			name: "should error reacting without emoji",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionReact}},
+			err:  pkgError.ValidationError("emoji: cannot be blank."),
+		},
+		{
+			name: "should error sending a document",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionSendMedia, MediaType: "document", URL: "https://example.com/a.pdf"}},
+			err:  pkgError.ValidationError("media_type: must be a valid value."),
+		},
+		{
+			name: "should error sending media without url",
+			args: args{action: domainWebhook.Action{Type: domainWebhook.ActionSendMedia, MediaType: "video"}},
+			err:  pkgError.ValidationError("url: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookAction(context.Background(), tt.args.action)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}